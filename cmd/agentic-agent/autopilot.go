@@ -30,9 +30,19 @@ The autopilot will:
 6. Execute AI agent (if --execute-agent enabled)
 7. Auto-complete task if agent succeeds
 
+With --parallel N, up to N tasks run at once, each in its own worktree.
+Only tasks whose scopes do not overlap run side by side; a task without a
+scope runs alone. A live summary line tracks progress across workers.
+
+Autopilot renews the claim lease (workflow.claim_lease) on the tasks it
+works on. Claims that expired without a heartbeat are reported at start;
+with --reclaim-expired they are returned to the backlog and picked up again.
+
 Flags:
   --max-iterations  Maximum number of tasks to process (default 10)
   --execute-agent   Execute AI agent for each task (default false)
+  --parallel        Number of tasks to work on concurrently (default 1)
+  --reclaim-expired Return tasks whose claim lease expired to the backlog
   --stop-signal     Custom stop signal string
   --dry-run         Show what would be processed without making changes`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -40,11 +50,15 @@ Flags:
 		stopSignal, _ := cmd.Flags().GetString("stop-signal")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		executeAgent, _ := cmd.Flags().GetBool("execute-agent")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		reclaimExpired, _ := cmd.Flags().GetBool("reclaim-expired")
 
 		cfg := getConfig()
 
 		loop := orchestrator.NewAutopilotLoop(cfg, maxIterations, stopSignal, dryRun).
-			WithAgentExecution(executeAgent)
+			WithAgentExecution(executeAgent).
+			WithParallel(parallel).
+			WithReclaimExpired(reclaimExpired)
 
 		// Set up context with Ctrl+C cancellation
 		ctx, cancel := context.WithCancel(context.Background())
@@ -68,6 +82,8 @@ Flags:
 func init() {
 	autopilotStartCmd.Flags().Int("max-iterations", 10, "Maximum number of tasks to process")
 	autopilotStartCmd.Flags().Bool("execute-agent", false, "Execute AI agent for each task")
+	autopilotStartCmd.Flags().Int("parallel", 1, "Number of tasks to work on concurrently")
+	autopilotStartCmd.Flags().Bool("reclaim-expired", false, "Return tasks whose claim lease expired to the backlog before starting")
 	autopilotStartCmd.Flags().String("stop-signal", "", "Custom stop signal string")
 	autopilotStartCmd.Flags().Bool("dry-run", false, "Show what would be processed without making changes")
 
@@ -197,6 +197,10 @@ func (m *contextBuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.step == "select-format" {
 				m.format = "json"
 			}
+		case "4":
+			if m.step == "select-format" {
+				m.format = "yaml"
+			}
 
 		default:
 			if m.step == "select-task" {
@@ -234,6 +238,7 @@ func (m *contextBuildModel) View() string {
 			{"1", "toon", "TOON format (default)"},
 			{"2", "markdown", "Markdown format"},
 			{"3", "json", "JSON format"},
+			{"4", "yaml", "YAML format"},
 		}
 
 		for _, f := range formats {
@@ -251,7 +256,7 @@ func (m *contextBuildModel) View() string {
 		}
 
 		b.WriteString("\n")
-		b.WriteString(styles.HelpStyle.Render("1-3 select format • Enter confirm • Esc back") + "\n")
+		b.WriteString(styles.HelpStyle.Render("1-4 select format • Enter confirm • Esc back") + "\n")
 	} else if m.step == "building" {
 		b.WriteString(styles.TitleStyle.Render("Building Context Bundle...") + "\n")
 	}
@@ -384,10 +389,24 @@ var contextScanCmd = &cobra.Command{
 var contextUpdateCmd = &cobra.Command{
 	Use:   "update [dir]",
 	Short: "Update context.md for a directory",
-	Args:  cobra.ExactArgs(1),
+	Long: `Regenerate the facts in context.md (exported symbols, dependencies and
+key files) and merge them into the existing file. Hand-written purpose, rules,
+prose and extra sections are kept. A missing file is generated from scratch.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		// logic same as generate for now
-		contextGenerateCmd.Run(cmd, args)
+		dir := args[0]
+		generated, err := context.GenerateContext(dir)
+		if err != nil {
+			fmt.Printf("Error generating context: %v\n", err)
+			os.Exit(1)
+		}
+
+		dcm := context.NewDirectoryContextManager(dir)
+		if _, err := dcm.UpdateContext(dir, generated); err != nil {
+			fmt.Printf("Error updating context: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Updated context for %s\n", dir)
 	},
 }
 
@@ -428,19 +447,34 @@ var contextBuildCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		bundle, err := encoding.CreateContextBundle(taskID, format, getConfig())
+		bundle, err := encoding.BuildContextBundle(taskID, format, getConfig())
 		if err != nil {
 			fmt.Printf("Error building bundle: %v\n", err)
 			os.Exit(1)
 		}
+		data, err := encoding.EncodeAs(bundle, format)
+		if err != nil {
+			fmt.Printf("Error encoding bundle: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(data))
 
-		fmt.Println(string(bundle))
+		// Budget report goes to stderr so the bundle can be piped
+		manifest := bundle.Manifest
+		fmt.Fprintf(os.Stderr, "Bundle: %s\n", manifest.Summary())
+		for _, e := range manifest.Reduced() {
+			fmt.Fprintf(os.Stderr, "  %-10s %s %s (%d -> %d tokens)\n", e.Status, e.Kind, e.Name, e.Original, e.Tokens)
+		}
+		for _, e := range manifest.Dropped() {
+			fmt.Fprintf(os.Stderr, "  %-10s %s %s (%s, %d tokens)\n", e.Status, e.Kind, e.Name, e.Relevance, e.Original)
+		}
 	},
 }
 
 func init() {
 	contextBuildCmd.Flags().String("task", "", "Task ID to build context for")
-	contextBuildCmd.Flags().String("format", "toon", "Output format")
+	contextBuildCmd.Flags().String("format", "toon", "Output format: toon, yaml, json, markdown")
 
 	contextCmd.AddCommand(contextGenerateCmd)
 	contextCmd.AddCommand(contextScanCmd)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,6 +19,11 @@ import (
 // syncOpenSpecChanges auto-imports tasks from any draft openspec changes
 // that have a populated tasks.md. Returns the sync result for optional logging.
 func syncOpenSpecChanges(cfg *models.Config) *openspec.SyncResult {
+	return syncOpenSpecChangesTo(os.Stdout, cfg)
+}
+
+// syncOpenSpecChangesTo is syncOpenSpecChanges reporting imports to w.
+func syncOpenSpecChangesTo(w io.Writer, cfg *models.Config) *openspec.SyncResult {
 	if cfg.Paths.OpenSpecDir == "" {
 		return &openspec.SyncResult{}
 	}
@@ -25,7 +31,7 @@ func syncOpenSpecChanges(cfg *models.Config) *openspec.SyncResult {
 	tm := tasks.NewTaskManager(".agentic/tasks")
 	result, _ := m.Sync(tm)
 	if result != nil && len(result.ChangesImported) > 0 {
-		fmt.Printf("Auto-imported %d tasks from %d change(s)\n",
+		fmt.Fprintf(w, "Auto-imported %d tasks from %d change(s)\n",
 			result.TasksCreated, len(result.ChangesImported))
 	}
 	return result
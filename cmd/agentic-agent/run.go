@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/javierbenavides/agentic-agent/internal/orchestrator"
@@ -18,6 +22,17 @@ import (
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run the agent orchestrator for a task",
+	Long: `Run the agent orchestrator for a single task.
+
+The orchestrator claims the task if it is still in the backlog, then drives
+the active agent (see --agent) through PLANNING → EXECUTION → VERIFICATION → DONE.
+Each iteration's output and verification failures feed the next prompt.
+The task is completed once every acceptance criterion is verified.
+
+Flags:
+  --task            Task ID to run
+  --max-iterations  Maximum agent iterations (default 10)
+  --stop-signal     Output string that makes the agent stop early`,
 	Run: func(cmd *cobra.Command, args []string) {
 		taskID, _ := cmd.Flags().GetString("task")
 
@@ -37,6 +52,7 @@ var runCmd = &cobra.Command{
 					fmt.Println(styles.RenderSuccess(model.message))
 				} else {
 					fmt.Println(styles.RenderError(model.message))
+					os.Exit(1)
 				}
 			}
 			return
@@ -70,7 +86,22 @@ var runCmd = &cobra.Command{
 			}
 		}
 
-		if err := orchestrator.RunLoop(taskID); err != nil {
+		maxIterations, _ := cmd.Flags().GetInt("max-iterations")
+		stopSignal, _ := cmd.Flags().GetString("stop-signal")
+
+		// Set up context with Ctrl+C cancellation
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-sigCh
+			fmt.Println("\nReceived interrupt signal. Stopping orchestrator...")
+			cancel()
+		}()
+
+		if err := orchestrator.RunLoop(ctx, getConfig(), taskID, maxIterations, stopSignal); err != nil {
 			fmt.Printf("Error running orchestrator: %v\n", err)
 			os.Exit(1)
 		}
@@ -85,6 +116,19 @@ type runOrchestratorModel struct {
 	selectedTask string
 	success      bool
 	message      string
+	cancel       context.CancelFunc // stops a running orchestrator
+}
+
+// orchestratorDoneMsg reports the result of a run started from the TUI
+type orchestratorDoneMsg struct {
+	err error
+}
+
+// runOrchestrator runs the loop off the UI goroutine so the view stays live
+func runOrchestrator(ctx context.Context, taskID string) tea.Cmd {
+	return func() tea.Msg {
+		return orchestratorDoneMsg{err: orchestrator.RunLoop(ctx, getConfig(), taskID, 0, "")}
+	}
 }
 
 func (m *runOrchestratorModel) Init() tea.Cmd {
@@ -119,15 +163,36 @@ func (m *runOrchestratorModel) Init() tea.Cmd {
 
 func (m *runOrchestratorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case orchestratorDoneMsg:
+		switch {
+		case errors.Is(msg.err, orchestrator.ErrStopped):
+			m.message = fmt.Sprintf("Orchestrator stopped; task %s is unfinished: %v", m.selectedTask, msg.err)
+			m.success = false
+		case msg.err != nil:
+			m.message = fmt.Sprintf("Error: %v", msg.err)
+			m.success = false
+		default:
+			m.message = fmt.Sprintf("Orchestrator completed for task %s", m.selectedTask)
+			m.success = true
+		}
+		m.step = "done"
+		return m, tea.Quit
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
+			if m.cancel != nil {
+				m.cancel()
+			}
 			return m, tea.Quit
 
 		case "esc":
 			if m.step == "confirm" {
 				m.step = "select-task"
 			} else {
+				if m.cancel != nil {
+					m.cancel()
+				}
 				return m, tea.Quit
 			}
 
@@ -144,15 +209,9 @@ func (m *runOrchestratorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.step == "confirm" {
 				// Run the orchestrator
 				m.step = "running"
-				if err := orchestrator.RunLoop(m.selectedTask); err != nil {
-					m.message = fmt.Sprintf("Error: %v", err)
-					m.success = false
-				} else {
-					m.message = fmt.Sprintf("Orchestrator completed for task %s", m.selectedTask)
-					m.success = true
-				}
-				m.step = "done"
-				return m, tea.Quit
+				ctx, cancel := context.WithCancel(context.Background())
+				m.cancel = cancel
+				return m, runOrchestrator(ctx, m.selectedTask)
 			}
 
 		case "n":
@@ -202,4 +261,6 @@ func (m *runOrchestratorModel) View() string {
 
 func init() {
 	runCmd.Flags().String("task", "", "Task ID to run")
+	runCmd.Flags().Int("max-iterations", 10, "Maximum agent iterations before giving up")
+	runCmd.Flags().String("stop-signal", "", "Custom stop signal string")
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/javierbenavides/agentic-agent/internal/sdd"
 	"github.com/javierbenavides/agentic-agent/internal/ui/helpers"
 	"github.com/javierbenavides/agentic-agent/internal/ui/styles"
+	"github.com/javierbenavides/agentic-agent/internal/validator"
 	"github.com/spf13/cobra"
 )
 
@@ -129,7 +130,7 @@ var sddAgentsInstallCmd = &cobra.Command{
 	},
 }
 
-// sdd gate-check <spec-id> [--format text|json]
+// sdd gate-check <spec-id> [--format text|json|sarif|junit]
 var sddGateCheckCmd = &cobra.Command{
 	Use:   "gate-check <spec-id>",
 	Short: "Run all 5 SDD gates on a spec",
@@ -167,10 +168,19 @@ var sddGateCheckCmd = &cobra.Command{
 		}
 
 		// Format output
-		if format == "json" {
+		switch format {
+		case "json":
 			data, _ := json.MarshalIndent(report, "", "  ")
 			fmt.Println(string(data))
-		} else {
+		case "sarif":
+			if err := validator.WriteSARIF(os.Stdout, report.RuleResults()); err != nil {
+				return fmt.Errorf("failed to write SARIF report: %w", err)
+			}
+		case "junit":
+			if err := validator.WriteJUnit(os.Stdout, report.RuleResults()); err != nil {
+				return fmt.Errorf("failed to write JUnit report: %w", err)
+			}
+		default:
 			printGateReport(cmd, report)
 		}
 
@@ -329,7 +339,7 @@ func initSDDCmd() {
 	sddStartCmd.Flags().String("risk", "", "Risk level: low|medium|high|critical")
 	sddAgentsInstallCmd.Flags().String("dir", "", "Target directory (default: .claude/agents)")
 	sddAgentsInstallCmd.Flags().Bool("force", false, "Overwrite existing files")
-	sddGateCheckCmd.Flags().String("format", "text", "Output format: text|json")
+	sddGateCheckCmd.Flags().String("format", "text", "Output format: text|json|sarif|junit")
 	sddSyncGraphCmd.Flags().String("from", "", "Source graph path")
 	sddSyncGraphCmd.Flags().String("to", "", "Destination graph path")
 	sddADRCreateCmd.Flags().String("title", "", "ADR title (required)")
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -9,7 +8,6 @@ import (
 	"github.com/javierbenavides/agentic-agent/internal/simplify"
 	"github.com/javierbenavides/agentic-agent/internal/tasks"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var simplifyCmd = &cobra.Command{
@@ -54,17 +52,7 @@ Examples:
 		}
 
 		// Encode output
-		var data []byte
-		switch format {
-		case "json":
-			data, err = json.MarshalIndent(bundle, "", "  ")
-		case "yaml":
-			data, err = yaml.Marshal(bundle)
-		default:
-			// toon format — wrap in the toon encoder
-			encoder := encoding.NewToonEncoder()
-			data, err = encoder.Encode(bundle)
-		}
+		data, err := encoding.EncodeAs(bundle, format)
 		if err != nil {
 			fmt.Printf("Error encoding output: %v\n", err)
 			os.Exit(1)
@@ -104,5 +92,5 @@ func dirsFromTask(taskID string) []string {
 func init() {
 	simplifyCmd.Flags().String("task", "", "Task ID (uses task's scope directories)")
 	simplifyCmd.Flags().String("output", "", "Output file path (default: stdout)")
-	simplifyCmd.Flags().String("format", "toon", "Output format: toon, json, yaml")
+	simplifyCmd.Flags().String("format", "toon", "Output format: toon, json, yaml, markdown")
 }
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/javierbenavides/agentic-agent/internal/status"
 	"github.com/javierbenavides/agentic-agent/internal/tasks"
 	"github.com/javierbenavides/agentic-agent/internal/ui/helpers"
 	"github.com/javierbenavides/agentic-agent/internal/ui/styles"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +23,22 @@ var statusCmd = &cobra.Command{
 		cfg := getConfig()
 		tm := tasks.NewTaskManager(".agentic/tasks")
 
+		if reclaim, _ := cmd.Flags().GetBool("reclaim-expired"); reclaim {
+			lease, err := tasks.ClaimLease(cfg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			reclaimed, err := tm.ReclaimExpired(time.Now(), lease, false, nil)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reclaiming expired claims: %v\n", err)
+				os.Exit(1)
+			}
+			for _, t := range reclaimed {
+				fmt.Fprintf(os.Stderr, "↩️  Reclaimed %s from %s (lease expired %s)\n", t.ID, t.AssignedTo, tasks.LeaseExpiry(&t, lease).Format("Jan 02 15:04"))
+			}
+		}
+
 		data, err := status.Gather(tm, cfg)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error gathering status: %v\n", err)
@@ -89,6 +107,19 @@ func renderInteractiveStatus(d *status.DashboardData) {
 		b.WriteString("\n")
 	}
 
+	// Claims whose lease lapsed
+	if len(d.ExpiredClaims) > 0 {
+		b.WriteString(styles.WarningStyle.Render("Expired Claims") + "\n")
+		for _, t := range d.ExpiredClaims {
+			b.WriteString(fmt.Sprintf("  %s %s %s\n",
+				styles.IconPending,
+				t.ID+": "+t.Title,
+				styles.MutedStyle.Render("("+expiredClaimNote(t)+")"),
+			))
+		}
+		b.WriteString(styles.MutedStyle.Render("  Run 'agentic-agent status --reclaim-expired' to return them to the backlog") + "\n\n")
+	}
+
 	// Next ready task
 	if d.NextReady != nil {
 		b.WriteString(styles.SubtitleStyle.Render("Next Up") + "\n")
@@ -139,6 +170,15 @@ func renderPlainStatus(d *status.DashboardData) {
 		fmt.Println()
 	}
 
+	if len(d.ExpiredClaims) > 0 {
+		fmt.Println("Expired Claims:")
+		for _, t := range d.ExpiredClaims {
+			fmt.Printf("  ? %s: %s (%s)\n", t.ID, t.Title, expiredClaimNote(t))
+		}
+		fmt.Println("  Run 'agentic-agent status --reclaim-expired' to return them to the backlog")
+		fmt.Println()
+	}
+
 	if d.NextReady != nil {
 		fmt.Printf("Next Ready: %s: %s\n\n", d.NextReady.ID, d.NextReady.Title)
 	}
@@ -159,6 +199,20 @@ func renderPlainStatus(d *status.DashboardData) {
 	}
 }
 
+// expiredClaimNote says who held an expired claim and when it was last
+// renewed.
+func expiredClaimNote(t models.Task) string {
+	who := t.AssignedTo
+	if who == "" {
+		who = "unassigned"
+	}
+	last := t.HeartbeatAt
+	if last.IsZero() {
+		last = t.ClaimedAt
+	}
+	return fmt.Sprintf("%s, last seen %s", who, last.Format("Jan 02 15:04"))
+}
+
 func renderProgressBar(pct float64, width int) string {
 	filled := int(pct / 100 * float64(width))
 	if filled > width {
@@ -172,4 +226,5 @@ func renderProgressBar(pct float64, width int) string {
 
 func init() {
 	statusCmd.Flags().String("format", "text", "Output format (text|json)")
+	statusCmd.Flags().Bool("reclaim-expired", false, "Return tasks whose claim lease expired to the backlog (worktrees are kept)")
 }
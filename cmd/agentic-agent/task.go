@@ -1,11 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/javierbenavides/agentic-agent/internal/orchestrator"
 	"github.com/javierbenavides/agentic-agent/internal/tasks"
 	"github.com/javierbenavides/agentic-agent/internal/ui/components"
 	"github.com/javierbenavides/agentic-agent/internal/ui/helpers"
@@ -13,6 +19,7 @@ import (
 	"github.com/javierbenavides/agentic-agent/internal/ui/styles"
 	"github.com/javierbenavides/agentic-agent/pkg/models"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var taskCmd = &cobra.Command{
@@ -64,6 +71,7 @@ Flag Mode (with flags):
 		inputsStr, _ := cmd.Flags().GetString("inputs")
 		outputsStr, _ := cmd.Flags().GetString("outputs")
 		acceptanceStr, _ := cmd.Flags().GetString("acceptance")
+		dependsOnStr, _ := cmd.Flags().GetString("depends-on")
 
 		tm := tasks.NewTaskManager(".agentic/tasks")
 		task, err := tm.CreateTask(title)
@@ -112,7 +120,18 @@ Flag Mode (with flags):
 			os.Exit(1)
 		}
 
+		if dependsOnStr != "" {
+			if err := tm.AddDependency(task.ID, parseCommaSeparated(dependsOnStr)...); err != nil {
+				fmt.Printf("Error adding dependencies: %v\n", err)
+				os.Exit(1)
+			}
+			task.DependsOn = parseCommaSeparated(dependsOnStr)
+		}
+
 		fmt.Printf("Created task %s: %s\n", task.ID, task.Title)
+		if len(task.DependsOn) > 0 {
+			fmt.Printf("  Depends on: %s\n", strings.Join(task.DependsOn, ", "))
+		}
 		if len(task.SpecRefs) > 0 {
 			fmt.Printf("  Spec refs: %s\n", strings.Join(task.SpecRefs, ", "))
 		}
@@ -136,10 +155,48 @@ Interactive Mode (no flags):
 Flag Mode (with flags or --no-interactive):
   agentic-agent task list --no-interactive
 
-  Simple text output listing all tasks.`,
+  Simple text output listing all tasks.
+
+Filtering:
+  --where takes space-separated terms that must all match:
+
+    status=in-progress           backlog, in-progress or done
+    type=review track=auth       exact value or glob; change=, assignee=, id= too
+    assignee=                    unassigned
+    scope=internal/api           scope contains or lies under the path (globs allowed)
+    spec=auth.md                 references the spec
+    pr=true                      has a GitHub PR
+    claimed<2d completed>=2026-01-01
+    status=backlog,in-progress   comma-separated values match any
+    -type=research               exclude matches
+    login "rate limit"           free text in ID, title and description
+
+  --query runs a query saved under queries: in agnostic-agent.yaml:
+
+    queries:
+      my-reviews:
+        where: status=in-progress type=review assignee=alice
+        sort: -claimed
+
+Examples:
+  agentic-agent task list --where "status=in-progress type=review track=auth scope=internal/api"
+  agentic-agent task list --where "pr=false status=done" --sort -completed --format table
+  agentic-agent task list --query my-reviews --format json`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Auto-import tasks from draft openspec changes
-		syncOpenSpecChanges(getConfig())
+		format, _ := cmd.Flags().GetString("format")
+		where, _ := cmd.Flags().GetString("where")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		queryName, _ := cmd.Flags().GetString("query")
+
+		cfg := getConfig()
+
+		// Auto-import tasks from draft openspec changes, keeping stdout clean
+		// for machine-readable output
+		if format == "json" || format == "yaml" {
+			syncOpenSpecChangesTo(os.Stderr, cfg)
+		} else {
+			syncOpenSpecChanges(cfg)
+		}
 
 		// Check if we should use interactive mode
 		if helpers.ShouldUseInteractiveMode(cmd) {
@@ -147,29 +204,42 @@ Flag Mode (with flags or --no-interactive):
 			return
 		}
 
-		// Traditional text-based list
-		tm := tasks.NewTaskManager(".agentic/tasks")
+		if queryName != "" {
+			saved, ok := cfg.Queries[queryName]
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: no saved query %q in config\n", queryName)
+				os.Exit(1)
+			}
+			where = strings.TrimSpace(saved.Where + " " + where)
+			if sortBy == "" {
+				sortBy = saved.Sort
+			}
+		}
 
-		sources := []string{"backlog", "in-progress", "done"}
-		for _, src := range sources {
-			list, err := tm.LoadTasks(src)
+		var query *tasks.Query
+		if where != "" {
+			q, err := tasks.ParseQuery(where, time.Now())
 			if err != nil {
-				fmt.Printf("Error loading %s: %v\n", src, err)
-				continue
-			}
-			if len(list.Tasks) > 0 {
-				fmt.Printf("\n--- %s ---\n", strings.ToUpper(src))
-				for _, t := range list.Tasks {
-					assignee := ""
-					if t.AssignedTo != "" {
-						assignee = fmt.Sprintf(" (@%s)", t.AssignedTo)
-					}
-					fmt.Printf("[%s] %s%s\n", t.ID, t.Title, assignee)
-					for _, st := range t.SubTasks {
-						fmt.Printf("  - [%s] %s\n", st.ID, st.Title)
-					}
-				}
+				fmt.Fprintf(os.Stderr, "Error: invalid query: %v\n", err)
+				os.Exit(1)
 			}
+			query = q
+		}
+
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		listed, err := tm.ListTasks(query)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing tasks: %v\n", err)
+			os.Exit(1)
+		}
+		if err := tasks.SortTasks(listed, sortBy); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := printTaskListing(os.Stdout, listed, format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 	},
 }
@@ -344,6 +414,177 @@ var taskDecomposeCmd = &cobra.Command{
 	},
 }
 
+var taskDependCmd = &cobra.Command{
+	Use:   "depend <task-id> <depends-on-id> [depends-on-id...]",
+	Short: "Declare that a task depends on other tasks",
+	Long: `Declare prerequisites for a task. A task cannot be claimed until all of
+its dependencies are done, and autopilot and status pick backlog tasks in
+dependency order.
+
+Dependencies that would create a cycle are rejected.
+
+Examples:
+  agentic-agent task depend TASK-2 TASK-1
+  agentic-agent task depend TASK-3 TASK-1 TASK-2`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		deps := args[1:]
+
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		if err := tm.AddDependency(taskID, deps...); err != nil {
+			fmt.Printf("Error adding dependency: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Task %s now depends on: %s\n", taskID, strings.Join(deps, ", "))
+	},
+}
+
+var taskUndependCmd = &cobra.Command{
+	Use:   "undepend <task-id> <depends-on-id> [depends-on-id...]",
+	Short: "Remove dependencies from a task",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		deps := args[1:]
+
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		if err := tm.RemoveDependency(taskID, deps...); err != nil {
+			fmt.Printf("Error removing dependency: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed dependencies from %s: %s\n", taskID, strings.Join(deps, ", "))
+	},
+}
+
+var taskHeartbeatCmd = &cobra.Command{
+	Use:   "heartbeat [task-id]",
+	Short: "Renew the lease on claimed tasks",
+	Long: `Renew the lease on a claimed task so it is not reported as abandoned.
+
+A claim lapses once workflow.claim_lease (default 30m) passes without a
+heartbeat. Agents working a task should call this periodically; autopilot
+does so on its own. Without a task ID, every in-progress task assigned to
+$USER is renewed.
+
+Examples:
+  agentic-agent task heartbeat TASK-1
+  agentic-agent task heartbeat`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		user := os.Getenv("USER")
+		if user == "" {
+			user = "unknown-agent"
+		}
+		lease, err := tasks.ClaimLease(getConfig())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		ids := args
+		if len(ids) == 0 {
+			inProgress, err := tm.LoadTasks("in-progress")
+			if err != nil {
+				fmt.Printf("Error loading tasks: %v\n", err)
+				os.Exit(1)
+			}
+			for _, t := range inProgress.Tasks {
+				if t.AssignedTo == user {
+					ids = append(ids, t.ID)
+				}
+			}
+			if len(ids) == 0 {
+				fmt.Printf("No in-progress tasks assigned to %s\n", user)
+				return
+			}
+		}
+
+		for _, id := range ids {
+			task, err := tm.Heartbeat(id, user, lease)
+			if err != nil {
+				fmt.Printf("Error renewing lease: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("💓 %s: lease renewed until %s\n", task.ID, task.LeaseExpiresAt.Format("15:04:05"))
+		}
+	},
+}
+
+var taskReleaseCmd = &cobra.Command{
+	Use:   "release <task-id>",
+	Short: "Return a claimed task to the backlog",
+	Long: `Give up a claim and return the task to the backlog so it can be claimed
+again.
+
+The task's worktree and branch are kept by default, and the next claim picks
+up where the work was left. Use --discard-worktree to remove them instead.
+
+Examples:
+  agentic-agent task release TASK-1
+  agentic-agent task release TASK-1 --discard-worktree`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		discard, _ := cmd.Flags().GetBool("discard-worktree")
+
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		task, err := tm.ReleaseTask(args[0], discard)
+		if err != nil {
+			fmt.Printf("Error releasing task: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("↩️  Released task %s back to the backlog\n", task.ID)
+		if task.WorktreePath != "" {
+			if discard {
+				fmt.Printf("   Discarded worktree %s\n", task.WorktreePath)
+			} else {
+				fmt.Printf("   Kept worktree %s for the next claim\n", task.WorktreePath)
+			}
+		}
+	},
+}
+
+var taskMigrateStoreCmd = &cobra.Command{
+	Use:   "migrate-store",
+	Short: "Convert the task lists to another storage backend",
+	Long: `Copy every task list to another storage backend.
+
+Two backends are available:
+  yaml  one file per list (backlog.yaml, in-progress.yaml, done.yaml); the default
+  bolt  a single embedded database (tasks.db) with indexes by ID, status and
+        track, for projects with thousands of tasks
+
+The copy is read back and compared with the original before the old files are
+moved aside (to yaml-backup-<time>/ or tasks.db.bak-<time>), so nothing is
+deleted. Run it while no agent is working on the tasks. Without --to, the
+current backend is shown.
+
+Examples:
+  agentic-agent task migrate-store
+  agentic-agent task migrate-store --to bolt
+  agentic-agent task migrate-store --to yaml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		const tasksDir = ".agentic/tasks"
+		to, _ := cmd.Flags().GetString("to")
+		if to == "" {
+			fmt.Printf("Task store: %s (%s)\n", tasks.DetectStore(tasksDir), tasksDir)
+			return
+		}
+
+		res, err := tasks.MigrateStore(tasksDir, to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error migrating task store: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Migrated tasks from %s to %s\n", res.From, res.To)
+		fmt.Printf("   backlog: %d, in-progress: %d, done: %d\n",
+			res.Counts["backlog"], res.Counts["in-progress"], res.Counts["done"])
+		fmt.Printf("   Previous %s store moved to %s\n", res.From, res.Backup)
+	},
+}
+
 // taskDecomposeModel is a Bubble Tea model for task decomposition
 type taskDecomposeModel struct {
 	step            string // "select-task", "edit-subtasks", "confirm", "done"
@@ -455,6 +696,407 @@ func (m *taskDecomposeModel) View() string {
 	return styles.ContainerStyle.Render(b.String())
 }
 
+var taskHistoryCmd = &cobra.Command{
+	Use:   "history <task-id>",
+	Short: "Show the orchestrator state timeline of a task",
+	Long: `Print every orchestrator state transition recorded for a task by
+'run' and 'autopilot', with its timestamp, actor and reason.
+
+Examples:
+  agentic-agent task history TASK-123`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+
+		store := orchestrator.NewStateStore(orchestrator.DefaultStateDir)
+		ts, err := store.Load(taskID)
+		if err != nil {
+			fmt.Printf("Error loading task history: %v\n", err)
+			os.Exit(1)
+		}
+		if ts == nil || len(ts.History) == 0 {
+			fmt.Printf("No recorded history for task %s\n", taskID)
+			return
+		}
+
+		fmt.Printf("Task %s: %s (iteration %d)\n\n", ts.TaskID, ts.State, ts.Iteration)
+		for _, tr := range ts.History {
+			line := fmt.Sprintf("%s  %-12s → %-12s %s", tr.Timestamp.Format("2006-01-02 15:04:05"), tr.From, tr.To, tr.Event)
+			if tr.Actor != "" {
+				line += fmt.Sprintf("  by %s", tr.Actor)
+			}
+			if tr.Reason != "" {
+				line += fmt.Sprintf("  (%s)", tr.Reason)
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+var taskLogCmd = &cobra.Command{
+	Use:   "log [task-id]",
+	Short: "Show the change log of one task or all tasks",
+	Long: `Print the events recorded in .agentic/tasks/events.jsonl: every change
+made to a task through agentic-agent (create, claim, release, complete, move,
+decompose, depend, pr-link, update, delete and undo), who made it and when.
+Lease renewals are not recorded.
+
+Examples:
+  agentic-agent task log
+  agentic-agent task log TASK-123
+  agentic-agent task log --limit 20
+  agentic-agent task log TASK-123 --format json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := ""
+		if len(args) == 1 {
+			taskID = args[0]
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		format, _ := cmd.Flags().GetString("format")
+
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		events, err := tm.Events(taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading task log: %v\n", err)
+			os.Exit(1)
+		}
+		undone := map[int64]bool{}
+		for _, e := range events {
+			for _, seq := range e.Undoes {
+				undone[seq] = true
+			}
+		}
+		if limit > 0 && len(events) > limit {
+			events = events[len(events)-limit:]
+		}
+
+		switch format {
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			for _, e := range events {
+				enc.Encode(e)
+			}
+		case "text", "":
+			if len(events) == 0 {
+				fmt.Println("No recorded events.")
+				return
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "#\tTIME\tTYPE\tTASK\tACTOR\tCHANGE")
+			for _, e := range events {
+				change := eventSummary(e)
+				if undone[e.Seq] {
+					change += " (undone)"
+				}
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", e.Seq, e.Time.Local().Format("2006-01-02 15:04:05"),
+					e.Type, e.TaskID, dashIfEmpty(e.Actor), change)
+			}
+			tw.Flush()
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected text or json)\n", format)
+			os.Exit(1)
+		}
+	},
+}
+
+var taskUndoCmd = &cobra.Command{
+	Use:   "undo <task-id>",
+	Short: "Revert the last changes made to a task",
+	Long: `Revert the last N logged events of a task (see 'task log'), restoring the
+list it was in and all of its fields. Undoing a create removes the task. The
+undo is logged too, so running it again goes further back.
+
+Undo refuses to run if the task was changed outside agentic-agent since the
+last event. It only touches the task lists: worktrees, branches and pull
+requests are left as they are.
+
+Examples:
+  agentic-agent task undo TASK-123          # e.g. an accidental 'task complete'
+  agentic-agent task undo TASK-123 -n 3`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n, _ := cmd.Flags().GetInt("count")
+
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		reverted, err := tm.Undo(args[0], n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error undoing task changes: %v\n", err)
+			os.Exit(1)
+		}
+		for _, e := range reverted {
+			fmt.Printf("↩️  Undid #%d %s (%s)\n", e.Seq, e.Type, eventSummary(e))
+		}
+		task, list, err := tm.FindTask(args[0])
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		case task == nil:
+			fmt.Printf("   Task %s no longer exists\n", args[0])
+		default:
+			fmt.Printf("   Task %s is in %s (%s)\n", task.ID, list, task.Status)
+		}
+		for _, e := range reverted {
+			if e.After == nil || e.After.WorktreePath == "" || (task != nil && task.WorktreePath == e.After.WorktreePath) {
+				continue
+			}
+			if _, err := os.Stat(e.After.WorktreePath); err == nil {
+				fmt.Printf("   Worktree %s was left in place\n", e.After.WorktreePath)
+				break
+			}
+		}
+	},
+}
+
+// eventSummary describes what an event changed in a few words.
+func eventSummary(e tasks.Event) string {
+	var parts []string
+	switch {
+	case e.Type == tasks.EventCreate || e.Before == nil:
+		parts = append(parts, "created in "+e.ToList)
+	case e.Type == tasks.EventDelete || e.After == nil:
+		parts = append(parts, "removed from "+e.FromList)
+	case e.FromList != e.ToList:
+		parts = append(parts, e.FromList+" → "+e.ToList)
+	}
+	if len(e.Fields) > 0 && e.Before != nil && e.After != nil {
+		parts = append(parts, strings.Join(e.Fields, ", "))
+	}
+	if len(e.Undoes) > 0 {
+		seqs := make([]string, len(e.Undoes))
+		for i, seq := range e.Undoes {
+			seqs[i] = fmt.Sprintf("#%d", seq)
+		}
+		parts = append(parts, "reverts "+strings.Join(seqs, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// taskEditListFlags maps the add/remove flag stem of task edit to the
+// list field it changes.
+var taskEditListFlags = []struct{ flag, field string }{
+	{"scope", "scope"},
+	{"acceptance", "acceptance"},
+	{"spec-ref", "spec_refs"},
+	{"skill-ref", "skill_refs"},
+	{"input", "inputs"},
+	{"output", "outputs"},
+}
+
+var taskEditCmd = &cobra.Command{
+	Use:   "edit <task-id>",
+	Short: "Change the title, description, type or lists of a task",
+	Long: `Change a task after it was created, in whichever list it is.
+
+With field flags the changes are applied directly. List flags can be
+repeated: --add-* skips entries already present and --remove-* fails if an
+entry is missing. With --editor the task's editable fields open as YAML in
+$VISUAL or $EDITOR and are validated on save. With no flags in a terminal
+the create wizard opens pre-filled with the task.
+
+Status, assignment and claim fields are not editable; use claim, release
+and complete for those. Every edit is recorded in 'task log' and can be
+reverted with 'task undo'.
+
+Examples:
+  agentic-agent task edit TASK-123
+  agentic-agent task edit TASK-123 --set-title "Add rate limiting" --set-type review
+  agentic-agent task edit TASK-123 --add-scope internal/api --remove-scope internal/old
+  agentic-agent task edit TASK-123 --add-acceptance "Returns 429 when over limit"
+  agentic-agent task edit TASK-123 --editor`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		useEditor, _ := cmd.Flags().GetBool("editor")
+
+		var task *models.Task
+		var list string
+		var err error
+		switch {
+		case taskEditFlagsSet(cmd):
+			task, list, err = tm.EditTask(taskID, func(e *tasks.TaskEdit) error {
+				return applyTaskEditFlags(cmd, e)
+			})
+		case useEditor:
+			var changed bool
+			task, list, changed, err = editTaskInEditor(tm, taskID)
+			if err == nil && !changed {
+				fmt.Println("No changes.")
+				return
+			}
+		case helpers.ShouldUseInteractiveMode(cmd):
+			runInteractiveTaskEdit(tm, taskID)
+			return
+		default:
+			fmt.Fprintln(os.Stderr, "Error: nothing to change; pass field flags such as --set-title or --add-scope, or --editor")
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error editing task: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✏️  Updated task %s in %s: %s\n", task.ID, list, task.Title)
+		if task.Type != "" {
+			fmt.Printf("   Type: %s\n", task.Type)
+		}
+		for _, f := range []struct {
+			name  string
+			items []string
+		}{
+			{"Scope", task.Scope}, {"Spec refs", task.SpecRefs}, {"Skill refs", task.SkillRefs},
+			{"Inputs", task.Inputs}, {"Outputs", task.Outputs}, {"Acceptance", task.Acceptance},
+		} {
+			if len(f.items) > 0 {
+				fmt.Printf("   %s: %s\n", f.name, strings.Join(f.items, ", "))
+			}
+		}
+	},
+}
+
+// taskEditFlagsSet reports whether any field flag of task edit was given.
+func taskEditFlagsSet(cmd *cobra.Command) bool {
+	for _, name := range []string{"set-title", "set-description", "set-type"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	for _, f := range taskEditListFlags {
+		if cmd.Flags().Changed("add-"+f.flag) || cmd.Flags().Changed("remove-"+f.flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTaskEditFlags applies the task edit field flags to e. Removals run
+// before additions so an entry can be replaced in one call.
+func applyTaskEditFlags(cmd *cobra.Command, e *tasks.TaskEdit) error {
+	if cmd.Flags().Changed("set-title") {
+		e.Title, _ = cmd.Flags().GetString("set-title")
+	}
+	if cmd.Flags().Changed("set-description") {
+		e.Description, _ = cmd.Flags().GetString("set-description")
+	}
+	if cmd.Flags().Changed("set-type") {
+		e.Type, _ = cmd.Flags().GetString("set-type")
+	}
+	for _, f := range taskEditListFlags {
+		remove, _ := cmd.Flags().GetStringArray("remove-" + f.flag)
+		if err := e.Remove(f.field, remove...); err != nil {
+			return err
+		}
+		add, _ := cmd.Flags().GetStringArray("add-" + f.flag)
+		if err := e.Add(f.field, add...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const taskEditHeader = `# Editing %s. Save and quit to apply; save the file unchanged to cancel.
+# Status, assignment and claim fields are not editable here.
+`
+
+// editTaskInEditor opens the editable fields of a task in the user's
+// editor until they parse and validate, then saves them. An invalid file
+// is reopened with the error on top; saving it unchanged gives up.
+func editTaskInEditor(tm *tasks.TaskManager, taskID string) (*models.Task, string, bool, error) {
+	task, _, err := tm.FindTask(taskID)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if task == nil {
+		return nil, "", false, fmt.Errorf("task %s not found", taskID)
+	}
+	shown := tasks.EditableFields(task)
+	body, err := tasks.MarshalTaskEdit(shown)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	f, err := os.CreateTemp("", "agentic-task-*.yaml")
+	if err != nil {
+		return nil, "", false, err
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	content := fmt.Sprintf(taskEditHeader, taskID) + string(body)
+	original := string(body)
+	var edited tasks.TaskEdit
+	for {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, "", false, err
+		}
+		if err := runEditor(path); err != nil {
+			return nil, "", false, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", false, err
+		}
+		if string(data) == content {
+			if strings.HasPrefix(content, "# Error:") {
+				return nil, "", false, fmt.Errorf("edit abandoned")
+			}
+			return nil, "", false, nil
+		}
+
+		edited, err = tasks.ParseTaskEdit(data)
+		if err == nil {
+			break
+		}
+		content = "# Error: " + strings.ReplaceAll(err.Error(), "\n", "\n# ") + "\n" +
+			fmt.Sprintf(taskEditHeader, taskID) + stripComments(string(data))
+	}
+	if out, err := tasks.MarshalTaskEdit(edited); err == nil && string(out) == original {
+		return nil, "", false, nil
+	}
+
+	updated, list, err := tm.EditTask(taskID, func(e *tasks.TaskEdit) error {
+		current, _ := tasks.MarshalTaskEdit(*e)
+		if string(current) != original {
+			return fmt.Errorf("task %s changed while it was being edited; run the edit again", taskID)
+		}
+		*e = edited
+		return nil
+	})
+	return updated, list, err == nil, err
+}
+
+// stripComments drops the leading comment lines added by editTaskInEditor.
+func stripComments(s string) string {
+	for strings.HasPrefix(s, "#") {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			return ""
+		}
+		s = s[i+1:]
+	}
+	return s
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, falling back to vi.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	argv := append(strings.Fields(editor), path)
+	c := exec.Command(argv[0], argv[1:]...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+	return nil
+}
+
 var taskShowCmd = &cobra.Command{
 	Use:   "show [task-id]",
 	Short: "Display detailed information about a task",
@@ -506,6 +1148,29 @@ var taskShowCmd = &cobra.Command{
 			fmt.Printf("Assigned To: %s\n", task.AssignedTo)
 		}
 
+		if source == "in-progress" {
+			lease, _ := tasks.ClaimLease(getConfig())
+			expiry := tasks.LeaseExpiry(task, lease)
+			if !expiry.IsZero() {
+				state := "expires"
+				if tasks.LeaseExpired(task, time.Now(), lease) {
+					state = "expired"
+				}
+				fmt.Printf("Lease: %s %s\n", state, expiry.Format("2006-01-02 15:04:05"))
+			}
+		}
+
+		if len(task.DependsOn) > 0 {
+			fmt.Printf("Depends On:\n")
+			for _, dep := range task.DependsOn {
+				depStatus := "not found"
+				if depTask, depSource, err := tm.FindTask(dep); err == nil && depTask != nil {
+					depStatus = depSource
+				}
+				fmt.Printf("  - %s (%s)\n", dep, depStatus)
+			}
+		}
+
 		if len(task.Scope) > 0 {
 			fmt.Printf("Scope:\n")
 			for _, s := range task.Scope {
@@ -686,6 +1351,105 @@ func runInteractiveTaskCreate() {
 	}
 }
 
+// runInteractiveTaskEdit runs the task creation wizard pre-filled with an
+// existing task
+func runInteractiveTaskEdit(tm *tasks.TaskManager, taskID string) {
+	task, _, err := tm.FindTask(taskID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if task == nil {
+		fmt.Fprintf(os.Stderr, "Error: task %s not found\n", taskID)
+		os.Exit(1)
+	}
+
+	p := tea.NewProgram(uimodels.NewTaskEditModel(task), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Printf("Error running task edit wizard: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printTaskListing writes listed tasks in the given format. Text groups
+// tasks by list, table prints one row per task in the listed order, and
+// JSON and YAML use the task file field names plus "list".
+func printTaskListing(w io.Writer, listed []tasks.ListedTask, format string) error {
+	switch format {
+	case "", "text":
+		if len(listed) == 0 {
+			fmt.Fprintln(w, "No matching tasks.")
+			return nil
+		}
+		for _, src := range []string{"backlog", "in-progress", "done"} {
+			header := false
+			for _, t := range listed {
+				if t.List != src {
+					continue
+				}
+				if !header {
+					fmt.Fprintf(w, "\n--- %s ---\n", strings.ToUpper(src))
+					header = true
+				}
+				assignee := ""
+				if t.AssignedTo != "" {
+					assignee = fmt.Sprintf(" (@%s)", t.AssignedTo)
+				}
+				fmt.Fprintf(w, "[%s] %s%s\n", t.ID, t.Title, assignee)
+				for _, st := range t.SubTasks {
+					fmt.Fprintf(w, "  - [%s] %s\n", st.ID, st.Title)
+				}
+			}
+		}
+		return nil
+	case "table":
+		if len(listed) == 0 {
+			fmt.Fprintln(w, "No matching tasks.")
+			return nil
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSTATUS\tTYPE\tTRACK\tASSIGNEE\tTITLE")
+		for _, t := range listed {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.List, dashIfEmpty(t.Type), dashIfEmpty(t.TrackID), dashIfEmpty(t.AssignedTo), t.Title)
+		}
+		return tw.Flush()
+	case "yaml":
+		if listed == nil {
+			listed = []tasks.ListedTask{}
+		}
+		data, err := yaml.Marshal(listed)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case "json":
+		// Round-trip through YAML so keys match the task files
+		data, err := yaml.Marshal(listed)
+		if err != nil {
+			return err
+		}
+		generic := []map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		out, err := json.MarshalIndent(generic, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	}
+	return fmt.Errorf("unknown format %q (expected text, table, json or yaml)", format)
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 // runInteractiveTaskList runs the interactive task list menu
 func runInteractiveTaskList() {
 	model := uimodels.NewTaskSelectModel()
@@ -956,6 +1720,7 @@ func init() {
 	taskCreateCmd.Flags().String("inputs", "", "Comma-separated required input files")
 	taskCreateCmd.Flags().String("outputs", "", "Comma-separated expected output files")
 	taskCreateCmd.Flags().String("acceptance", "", "Comma-separated acceptance criteria")
+	taskCreateCmd.Flags().String("depends-on", "", "Comma-separated IDs of tasks that must be done first")
 
 	// from-template flags
 	taskFromTemplateCmd.Flags().String("template", "", "Template name (feature, bug-fix, refactoring, documentation, testing)")
@@ -972,10 +1737,42 @@ func init() {
 	taskCmd.AddCommand(taskFromTemplateCmd)
 	taskCmd.AddCommand(taskListCmd)
 	taskCmd.AddCommand(taskShowCmd)
+	taskCmd.AddCommand(taskEditCmd)
+	taskCmd.AddCommand(taskHistoryCmd)
+	taskCmd.AddCommand(taskLogCmd)
+	taskCmd.AddCommand(taskUndoCmd)
 	taskCmd.AddCommand(taskClaimCmd)
 	taskCmd.AddCommand(taskContinueCmd)
 	taskCmd.AddCommand(taskCompleteCmd)
 	taskCmd.AddCommand(taskDecomposeCmd)
+	taskCmd.AddCommand(taskDependCmd)
+	taskCmd.AddCommand(taskUndependCmd)
+	taskCmd.AddCommand(taskHeartbeatCmd)
+	taskCmd.AddCommand(taskReleaseCmd)
+	taskCmd.AddCommand(taskMigrateStoreCmd)
+
+	taskListCmd.Flags().String("where", "", "Only list tasks matching this query, e.g. \"status=in-progress type=review\"")
+	taskListCmd.Flags().String("query", "", "Run a query saved under queries: in the config")
+	taskListCmd.Flags().String("sort", "", "Sort keys, comma-separated; prefix with - to reverse (e.g. status,-claimed)")
+	taskListCmd.Flags().String("format", "text", "Output format (text|table|json|yaml)")
+
+	taskReleaseCmd.Flags().Bool("discard-worktree", false, "Remove the task's worktree and branch instead of keeping them")
+
+	taskMigrateStoreCmd.Flags().String("to", "", "Backend to migrate to (yaml|bolt)")
+
+	taskLogCmd.Flags().Int("limit", 0, "Show only the last N events")
+	taskLogCmd.Flags().String("format", "text", "Output format (text|json)")
+	taskUndoCmd.Flags().IntP("count", "n", 1, "Number of events to undo")
+
+	taskEditCmd.Flags().String("set-title", "", "New task title")
+	taskEditCmd.Flags().String("set-description", "", "New task description")
+	taskEditCmd.Flags().String("set-type", "", "New task type")
+	for _, f := range taskEditListFlags {
+		name := strings.ReplaceAll(f.field, "_", " ")
+		taskEditCmd.Flags().StringArray("add-"+f.flag, nil, "Add an entry to "+name+" (repeatable)")
+		taskEditCmd.Flags().StringArray("remove-"+f.flag, nil, "Remove an entry from "+name+" (repeatable)")
+	}
+	taskEditCmd.Flags().BoolP("editor", "e", false, "Edit the task as YAML in $VISUAL or $EDITOR")
 
 	// NEW: Add learnings flag to complete command
 	taskCompleteCmd.Flags().StringP("learnings", "l", "", "Lessons learned during task (optional)")
@@ -6,10 +6,12 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/javierbenavides/agentic-agent/internal/encoding"
 	"github.com/javierbenavides/agentic-agent/internal/token"
 	"github.com/javierbenavides/agentic-agent/internal/ui/helpers"
 	"github.com/javierbenavides/agentic-agent/internal/ui/styles"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var tokenCmd = &cobra.Command{
@@ -77,6 +79,73 @@ var tokenStatusCmd = &cobra.Command{
 	},
 }
 
+var tokenCompareCmd = &cobra.Command{
+	Use:   "compare [file]",
+	Short: "Compare the token cost of each output format",
+	Long: `Encode data in every output format (toon, yaml, json, markdown) and
+compare the estimated token cost of each.
+
+With --task the context bundle for that task is compared. Otherwise a YAML
+or JSON file is read and re-encoded; markdown only applies to bundles.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, _ := cmd.Flags().GetString("task")
+
+		var value interface{}
+		var source string
+		switch {
+		case taskID != "":
+			bundle, err := encoding.BuildContextBundle(taskID, "", getConfig())
+			if err != nil {
+				fmt.Printf("Error building bundle: %v\n", err)
+				os.Exit(1)
+			}
+			value, source = bundle, "context bundle for "+taskID
+		case len(args) == 1:
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				fmt.Printf("Error reading %s: %v\n", args[0], err)
+				os.Exit(1)
+			}
+			// YAML is a superset of JSON, so one decoder covers both
+			if err := yaml.Unmarshal(data, &value); err != nil {
+				fmt.Printf("Error parsing %s: %v\n", args[0], err)
+				os.Exit(1)
+			}
+			source = args[0]
+		default:
+			fmt.Println("Error: specify a file or --task")
+			fmt.Println("Usage: agentic-agent token compare <file> or --task <task-id>")
+			os.Exit(1)
+		}
+
+		costs := encoding.CompareFormats(value)
+		baseline := 0
+		for _, c := range costs {
+			if c.Format == encoding.FormatJSON {
+				baseline = c.Tokens
+			}
+		}
+
+		fmt.Printf("Token cost of %s:\n\n", source)
+		fmt.Printf("  %-10s %10s %10s %10s\n", "FORMAT", "BYTES", "TOKENS", "VS JSON")
+		for _, c := range costs {
+			if c.Err != nil {
+				fmt.Printf("  %-10s %10s %10s %10s\n", c.Format, "-", "-", "n/a")
+				continue
+			}
+			delta := "-"
+			if baseline > 0 {
+				delta = fmt.Sprintf("%+.1f%%", float64(c.Tokens-baseline)/float64(baseline)*100)
+			}
+			fmt.Printf("  %-10s %10d %10d %10s\n", c.Format, c.Bytes, c.Tokens, delta)
+		}
+	},
+}
+
 func init() {
+	tokenCompareCmd.Flags().String("task", "", "Compare the context bundle for this task")
+
 	tokenCmd.AddCommand(tokenStatusCmd)
+	tokenCmd.AddCommand(tokenCompareCmd)
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/javierbenavides/agentic-agent/internal/ui/helpers"
@@ -18,20 +19,82 @@ var validateCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		cwd, _ := os.Getwd()
 
-		v := validator.NewValidator()
-		v.Register(&rules.DirectoryContextRule{})
-		v.Register(&rules.ContextUpdateRule{})
-		v.Register(&rules.TaskScopeRule{})
-		v.Register(&rules.TaskSizeRule{})
-		v.Register(&rules.BrowserVerificationRule{})
-		v.Register(&rules.SpecMetadataRule{})
-		v.Register(&rules.SpecGraphRule{})
-		v.Register(&rules.ADRBlockingRule{})
-		v.Register(&rules.VerifyMdRule{})
-	v.Register(&rules.SkillTierRule{})
+		cfg := getConfig()
+		registry, err := rules.ProjectRegistry(cwd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading custom rules: %v\n", err)
+			os.Exit(1)
+		}
+		v, err := registry.Build(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error configuring validators: %v\n", err)
+			os.Exit(1)
+		}
+		if cmd.Flags().Changed("rule-timeout") {
+			timeout, _ := cmd.Flags().GetDuration("rule-timeout")
+			v.SetRuleTimeout(timeout)
+		}
+		failOn, _ := validator.ParseFailOn(cfg.Workflow.Validation.FailOn)
+		if cmd.Flags().Changed("fail-on") {
+			flagValue, _ := cmd.Flags().GetString("fail-on")
+			if failOn, err = validator.ParseFailOn(flagValue); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
 		ctx := &validator.ValidationContext{
 			ProjectRoot: cwd,
+			Config:      cfg,
+		}
+
+		since, _ := cmd.Flags().GetString("since")
+		staged, _ := cmd.Flags().GetBool("staged")
+		if since != "" && staged {
+			fmt.Fprintf(os.Stderr, "Error: --since and --staged cannot be used together\n")
+			os.Exit(1)
+		}
+		if since != "" || staged {
+			changed, err := validator.GitChangedFiles(cwd, since, staged)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error listing changed files: %v\n", err)
+				os.Exit(1)
+			}
+			ctx.ChangedFiles = changed
+		}
+
+		baselinePath := cfg.Workflow.Validation.Baseline
+		if baselinePath == "" {
+			baselinePath = validator.DefaultBaselinePath
+		}
+		if !filepath.IsAbs(baselinePath) {
+			baselinePath = filepath.Join(cwd, baselinePath)
+		}
+		updateBaseline, _ := cmd.Flags().GetBool("update-baseline")
+		noBaseline, _ := cmd.Flags().GetBool("no-baseline")
+		if updateBaseline && ctx.Scoped() {
+			fmt.Fprintf(os.Stderr, "Error: --update-baseline needs a full run; drop --since and --staged\n")
+			os.Exit(1)
+		}
+		if !updateBaseline && !noBaseline {
+			baseline, err := validator.LoadBaseline(baselinePath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading baseline: %v\n", err)
+				os.Exit(1)
+			}
+			v.UseBaseline(baseline)
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		fix, _ := cmd.Flags().GetBool("fix")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if dryRun && !fix {
+			fmt.Fprintf(os.Stderr, "Error: --dry-run needs --fix\n")
+			os.Exit(1)
+		}
+		if fix && (updateBaseline || format != "text") {
+			fmt.Fprintf(os.Stderr, "Error: --fix prints diffs and works only with the text format, without --update-baseline\n")
+			os.Exit(1)
 		}
 
 		results, err := v.Validate(ctx)
@@ -40,7 +103,33 @@ var validateCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		format, _ := cmd.Flags().GetString("format")
+		if fix {
+			fixes := v.Fix(ctx, results, dryRun)
+			validator.WriteFixReport(os.Stdout, fixes, dryRun)
+			after := make(map[string]*validator.RuleResult)
+			for _, f := range fixes {
+				if f.After != nil {
+					after[f.RuleName] = f.After
+				}
+			}
+			for i, res := range results {
+				if res, ok := after[res.RuleName]; ok {
+					results[i] = res
+				}
+			}
+			validator.PrintReport(results, format, failOn)
+			return
+		}
+
+		if updateBaseline {
+			baseline := validator.NewBaseline(results)
+			if err := baseline.Save(baselinePath); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Baseline written to %s (%d findings accepted)\n", baselinePath, len(baseline.Findings))
+			return
+		}
 
 		// Interactive mode - styled output
 		if helpers.ShouldUseInteractiveMode(cmd) && format == "text" {
@@ -65,7 +154,7 @@ var validateCmd = &cobra.Command{
 					icon = "⚠"
 					statusText = styles.WarningStyle.Render(res.Status)
 					warnCount++
-				case "FAIL":
+				case "FAIL", "ERROR":
 					icon = styles.IconCross
 					statusText = styles.ErrorStyle.Render(res.Status)
 					failCount++
@@ -79,6 +168,9 @@ var validateCmd = &cobra.Command{
 					statusText,
 					styles.BoldStyle.Render(res.RuleName),
 				)
+				if res.Suppressed > 0 {
+					ruleLine += " " + styles.MutedStyle.Render(fmt.Sprintf("(%d suppressed)", res.Suppressed))
+				}
 				b.WriteString(ruleLine + "\n")
 
 				for _, errMsg := range res.Errors {
@@ -96,19 +188,27 @@ var validateCmd = &cobra.Command{
 
 			fmt.Println(styles.ContainerStyle.Render(b.String()))
 
-			if failCount > 0 {
-				os.Exit(1)
+			if code := validator.ExitCode(results, failOn); code != 0 {
+				os.Exit(code)
 			}
 			return
 		}
 
 		// Flag mode or JSON format - use existing report
-		validator.PrintReport(results, format)
+		validator.PrintReport(results, format, failOn)
 	},
 }
 
 func init() {
-	validateCmd.Flags().String("format", "text", "Output format (text|json)")
+	validateCmd.Flags().String("format", "text", "Output format (text|json|sarif|junit)")
+	validateCmd.Flags().String("since", "", "Only check files changed since this git ref (committed, uncommitted and untracked)")
+	validateCmd.Flags().Bool("staged", false, "Only check files staged for commit")
+	validateCmd.Flags().Bool("update-baseline", false, "Accept all current findings into the baseline file and exit")
+	validateCmd.Flags().Bool("no-baseline", false, "Report findings even if the baseline accepts them")
+	validateCmd.Flags().Bool("fix", false, "Apply automatic fixes, print a diff of each change and run the fixed rules again")
+	validateCmd.Flags().Bool("dry-run", false, "With --fix, print the diffs without writing any file")
+	validateCmd.Flags().Duration("rule-timeout", 0, "Stop each rule after this long and report it as ERROR (default: workflow.validation.rule_timeout, else 2m)")
+	validateCmd.Flags().String("fail-on", "", "Exit nonzero at this level: fail, warn or never (default: workflow.validation.fail_on, else fail)")
 	// Register validateCmd in root.go via this init?
 	// No, standard pattern in this codebase is to have root.go add it.
 }
@@ -117,11 +117,11 @@ Flag Mode:
 					continue
 				}
 				dcm := context.NewDirectoryContextManager(dir)
-				if err := dcm.SaveContext(dir, ctx); err != nil {
+				if _, err := dcm.UpdateContext(dir, ctx); err != nil {
 					fmt.Printf("  Warning: Could not save context for %s: %v\n", dir, err)
 					continue
 				}
-				fmt.Printf("  ✓ Generated context for %s\n", dir)
+				fmt.Printf("  ✓ Updated context for %s\n", dir)
 			}
 		}
 
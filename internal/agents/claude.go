@@ -58,16 +58,14 @@ func (c *ClaudeExecutor) Execute(ctx context.Context, prompt string, task *model
 		}
 	}
 
-	// Check acceptance criteria
-	criteriaMet, criteriaFailed := c.checkCriteria(output, task.Acceptance)
-
-	return &models.AgentExecutionResult{
-		Output:         output,
-		Success:        len(criteriaFailed) == 0,
-		CriteriaMet:    criteriaMet,
-		CriteriaFailed: criteriaFailed,
-		TokensUsed:     int(message.Usage.InputTokens + message.Usage.OutputTokens),
-	}, nil
+	result := &models.AgentExecutionResult{
+		Output:     output,
+		TokensUsed: int(message.Usage.InputTokens + message.Usage.OutputTokens),
+	}
+
+	// Check acceptance criteria one by one
+	verifyInto(ctx, result, task, taskWorkDir(task))
+	return result, nil
 }
 
 func (c *ClaudeExecutor) buildPrompt(basePrompt string, task *models.Task) string {
@@ -84,20 +82,8 @@ func (c *ClaudeExecutor) buildPrompt(basePrompt string, task *models.Task) strin
 		b.WriteString("\n")
 	}
 
+	b.WriteString("After working, report a verdict per criterion as JSON: <verdict>[{\"index\": 1, \"passed\": true, \"evidence\": \"...\"}]</verdict>\n")
 	b.WriteString("When all criteria are met, include in your response: <promise>TASK COMPLETE</promise>\n")
 
 	return b.String()
 }
-
-func (c *ClaudeExecutor) checkCriteria(output string, criteria []string) (met []string, failed []string) {
-	// Simple string matching for now
-	// TODO: More sophisticated criteria checking
-
-	if strings.Contains(output, "<promise>TASK COMPLETE</promise>") {
-		// Assume all criteria met if completion signal found
-		return criteria, []string{}
-	}
-
-	// Otherwise, mark all as failed
-	return []string{}, criteria
-}
@@ -0,0 +1,252 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+const (
+	PromptModeStdin = "stdin"
+	PromptModeArg   = "arg"
+	PromptModeFile  = "file"
+
+	defaultCommandTimeout = 30 * time.Minute
+)
+
+// cliPreset runs an agent CLI headlessly; modelFlag selects the model.
+type cliPreset struct {
+	cmd       models.CommandConfig
+	modelFlag string
+}
+
+// cliPresets are the agent CLIs known by name, with aliases resolved by
+// CLIPreset.
+var cliPresets = map[string]cliPreset{
+	"claude":   {models.CommandConfig{Binary: "claude", Args: []string{"-p", "--permission-mode", "acceptEdits"}}, "--model"},
+	"copilot":  {models.CommandConfig{Binary: "copilot", Args: []string{"-p", "{{.Prompt}}", "--allow-all-tools"}, PromptMode: PromptModeArg}, "--model"},
+	"codex":    {models.CommandConfig{Binary: "codex", Args: []string{"exec", "--full-auto", "-"}}, "--model"},
+	"gemini":   {models.CommandConfig{Binary: "gemini", Args: []string{"--yolo"}}, "--model"},
+	"opencode": {models.CommandConfig{Binary: "opencode", Args: []string{"run", "{{.Prompt}}"}, PromptMode: PromptModeArg}, "--model"},
+}
+
+// CLIPreset returns the command that runs agent's CLI headlessly, if the
+// agent has one. A non-empty model is passed with the CLI's model flag.
+func CLIPreset(agent, model string) (models.CommandConfig, bool) {
+	switch agent {
+	case "claude-code":
+		agent = "claude"
+	case "github-copilot":
+		agent = "copilot"
+	}
+	preset, ok := cliPresets[agent]
+	if !ok {
+		return models.CommandConfig{}, false
+	}
+	cmd := preset.cmd
+	cmd.Args = append([]string(nil), cmd.Args...)
+	if model != "" {
+		cmd.Args = append(cmd.Args, preset.modelFlag, "{{.Model}}")
+	}
+	return cmd, true
+}
+
+// CommandExecutor runs any headless agent CLI described by a CommandConfig,
+// e.g. `claude -p`, `codex exec`, `gemini` or `opencode run`.
+type CommandExecutor struct {
+	cfg     models.CommandConfig
+	model   string
+	timeout time.Duration
+}
+
+// commandVars are the values available to argument templates.
+type commandVars struct {
+	Prompt     string
+	PromptFile string
+	TaskID     string
+	Model      string
+	WorkDir    string
+}
+
+// NewCommandExecutor creates an executor for cfg. A zero timeout uses 30 minutes.
+func NewCommandExecutor(cfg models.CommandConfig, model string, timeout time.Duration) *CommandExecutor {
+	if cfg.PromptMode == "" {
+		cfg.PromptMode = PromptModeStdin
+	}
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+	return &CommandExecutor{cfg: cfg, model: model, timeout: timeout}
+}
+
+func (e *CommandExecutor) Execute(ctx context.Context, prompt string, task *models.Task) (*models.AgentExecutionResult, error) {
+	if e.cfg.Binary == "" {
+		return nil, fmt.Errorf("command executor: binary not configured")
+	}
+
+	fullPrompt := buildStandardPrompt(prompt, task)
+	vars := commandVars{
+		Prompt:  fullPrompt,
+		TaskID:  task.ID,
+		Model:   e.model,
+		WorkDir: e.workDir(task),
+	}
+
+	if e.cfg.PromptMode == PromptModeFile {
+		f, err := os.CreateTemp("", "agentic-prompt-*.md")
+		if err != nil {
+			return nil, fmt.Errorf("failed to write prompt: %w", err)
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(fullPrompt)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write prompt: %w", err)
+		}
+		vars.PromptFile = f.Name()
+	}
+
+	args, err := e.buildArgs(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, e.cfg.Binary, args...)
+	cmd.Dir = vars.WorkDir
+	cmd.Env = e.environ()
+	if e.cfg.PromptMode == PromptModeStdin {
+		cmd.Stdin = strings.NewReader(fullPrompt)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	exitCode := 0
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case runCtx.Err() == context.DeadlineExceeded:
+			return nil, fmt.Errorf("%s timed out after %s", e.cfg.Binary, e.timeout)
+		case errors.As(runErr, &exitErr):
+			exitCode = exitErr.ExitCode()
+		default:
+			return nil, fmt.Errorf("failed to run %s: %w", e.cfg.Binary, runErr)
+		}
+	}
+
+	output := stdout.String()
+	result := &models.AgentExecutionResult{
+		Output:        output,
+		FilesModified: gitModifiedFiles(vars.WorkDir),
+		TokensUsed:    estimateTokens(fullPrompt + output),
+		Stderr:        stderr.String(),
+		ExitCode:      exitCode,
+	}
+	verifyInto(ctx, result, task, vars.WorkDir)
+	if exitCode != 0 {
+		result.ErrorMessage = fmt.Sprintf("%s exited with code %d: %s", e.cfg.Binary, exitCode, lastLine(stderr.String()))
+	}
+	return result, nil
+}
+
+// buildArgs expands the argument templates. In arg and file modes the prompt
+// (or prompt file) is appended when no argument references it.
+func (e *CommandExecutor) buildArgs(vars commandVars) ([]string, error) {
+	var args []string
+	referenced := false
+	for _, raw := range e.cfg.Args {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument template %q: %w", raw, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, vars); err != nil {
+			return nil, fmt.Errorf("invalid argument template %q: %w", raw, err)
+		}
+		if strings.Contains(raw, ".Prompt") {
+			referenced = true
+		}
+		args = append(args, b.String())
+	}
+
+	if !referenced {
+		switch e.cfg.PromptMode {
+		case PromptModeArg:
+			args = append(args, vars.Prompt)
+		case PromptModeFile:
+			args = append(args, vars.PromptFile)
+		}
+	}
+	return args, nil
+}
+
+// workDir picks the configured directory, then the task worktree, then ".".
+func (e *CommandExecutor) workDir(task *models.Task) string {
+	if e.cfg.WorkDir != "" {
+		return e.cfg.WorkDir
+	}
+	return taskWorkDir(task)
+}
+
+// environ returns the process environment plus the configured variables.
+func (e *CommandExecutor) environ() []string {
+	env := os.Environ()
+	keys := make([]string, 0, len(e.cfg.Env))
+	for k := range e.cfg.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+os.ExpandEnv(e.cfg.Env[k]))
+	}
+	return env
+}
+
+// gitModifiedFiles lists files changed against HEAD plus untracked files in
+// dir. It returns nil when dir is not a git work tree.
+func gitModifiedFiles(dir string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, args := range [][]string{
+		{"diff", "--name-only", "HEAD"},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			return nil
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !seen[line] {
+				seen[line] = true
+				files = append(files, line)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
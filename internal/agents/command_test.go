@@ -0,0 +1,149 @@
+package agents
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandExecutor_PromptModes(t *testing.T) {
+	task := &models.Task{ID: "T-7", Acceptance: []string{"works"}}
+
+	tests := []struct {
+		name string
+		cfg  models.CommandConfig
+	}{
+		{"stdin", models.CommandConfig{Binary: "cat"}},
+		{"arg appended", models.CommandConfig{Binary: "echo", PromptMode: PromptModeArg}},
+		{"arg template", models.CommandConfig{Binary: "sh", Args: []string{"-c", `echo "$1"`, "sh", "{{.Prompt}}"}, PromptMode: PromptModeArg}},
+		{"file", models.CommandConfig{Binary: "cat", PromptMode: PromptModeFile}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewCommandExecutor(tt.cfg, "", 0).Execute(context.Background(), "Build the thing", task)
+			require.NoError(t, err)
+			assert.Contains(t, result.Output, "Build the thing")
+			assert.Contains(t, result.Output, "- works")
+			assert.Equal(t, 0, result.ExitCode)
+			// The echoed prompt includes the completion tag instruction
+			assert.True(t, result.Success)
+		})
+	}
+}
+
+func TestCommandExecutor_TemplateVars(t *testing.T) {
+	cfg := models.CommandConfig{
+		Binary: "echo",
+		Args:   []string{"--model={{.Model}}", "--task={{.TaskID}}"},
+	}
+	result, err := NewCommandExecutor(cfg, "sonnet", 0).Execute(context.Background(), "p", &models.Task{ID: "T-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "--model=sonnet --task=T-1\n", result.Output)
+
+	cfg.Args = []string{"{{.Unknown}}"}
+	_, err = NewCommandExecutor(cfg, "", 0).Execute(context.Background(), "p", &models.Task{})
+	assert.Error(t, err)
+}
+
+func TestCommandExecutor_ExitCodeAndStderr(t *testing.T) {
+	cfg := models.CommandConfig{Binary: "sh", Args: []string{"-c", "echo partial; echo boom >&2; exit 3"}}
+	result, err := NewCommandExecutor(cfg, "", 0).Execute(context.Background(), "p", &models.Task{})
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Equal(t, "partial\n", result.Output)
+	assert.Equal(t, "boom\n", result.Stderr)
+	assert.Equal(t, "sh exited with code 3: boom", result.ErrorMessage)
+}
+
+func TestCommandExecutor_EnvAndTimeout(t *testing.T) {
+	t.Setenv("AGENTIC_BASE", "base")
+	cfg := models.CommandConfig{
+		Binary: "sh",
+		Args:   []string{"-c", "echo $AGENT_FLAVOR"},
+		Env:    map[string]string{"AGENT_FLAVOR": "$AGENTIC_BASE-extra"},
+	}
+	result, err := NewCommandExecutor(cfg, "", 0).Execute(context.Background(), "p", &models.Task{})
+	require.NoError(t, err)
+	assert.Equal(t, "base-extra\n", result.Output)
+
+	slow := models.CommandConfig{Binary: "sleep", Args: []string{"5"}}
+	_, err = NewCommandExecutor(slow, "", 50*time.Millisecond).Execute(context.Background(), "p", &models.Task{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestCommandExecutor_DetectsModifiedFilesInWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.email=t@example.com", "-c", "user.name=t"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644))
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	cfg := models.CommandConfig{Binary: "sh", Args: []string{"-c", "echo more >> a.txt; echo new > b.txt; pwd"}}
+	task := &models.Task{ID: "T-1", WorktreePath: dir}
+	result, err := NewCommandExecutor(cfg, "", 0).Execute(context.Background(), "p", task)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "b.txt"}, result.FilesModified)
+}
+
+func TestNewExecutorWithConfig_Command(t *testing.T) {
+	exec := NewExecutorWithConfig("my-cli", &models.AgentConfig{
+		Name:    "my-cli",
+		Type:    "command",
+		Command: &models.CommandConfig{Binary: "my-cli", Args: []string{"run"}},
+	})
+	_, ok := exec.(*CommandExecutor)
+	assert.True(t, ok)
+
+	_, err := NewExecutorWithConfig("my-cli", &models.AgentConfig{Type: "command"}).
+		Execute(context.Background(), "p", &models.Task{})
+	assert.Error(t, err)
+}
+
+func TestCLIPresets(t *testing.T) {
+	for _, agent := range []string{"claude", "claude-code", "copilot", "github-copilot", "codex", "gemini", "opencode"} {
+		preset, ok := CLIPreset(agent, "")
+		require.True(t, ok, agent)
+		assert.NotEmpty(t, preset.Binary, agent)
+	}
+	_, ok := CLIPreset("cursor", "")
+	assert.False(t, ok)
+
+	// Copilot gets the prompt as an argument and writes nothing to disk
+	copilot, ok := NewExecutor("github-copilot").(*CommandExecutor)
+	require.True(t, ok)
+	args, err := copilot.buildArgs(commandVars{Prompt: "do it"})
+	require.NoError(t, err)
+	assert.Equal(t, "copilot", copilot.cfg.Binary)
+	assert.Equal(t, []string{"-p", "do it", "--allow-all-tools"}, args)
+
+	// An override's model and timeout apply to the preset; a command block replaces it
+	claude, ok := NewExecutorWithConfig("claude", &models.AgentConfig{Model: "sonnet", TimeoutSeconds: 60}).(*CommandExecutor)
+	require.True(t, ok)
+	assert.Equal(t, "claude", claude.cfg.Binary)
+	assert.Equal(t, time.Minute, claude.timeout)
+	args, err = claude.buildArgs(commandVars{Model: claude.model})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-p", "--permission-mode", "acceptEdits", "--model", "sonnet"}, args)
+
+	custom, ok := NewExecutorWithConfig("claude", &models.AgentConfig{Command: &models.CommandConfig{Binary: "my-claude"}}).(*CommandExecutor)
+	require.True(t, ok)
+	assert.Equal(t, "my-claude", custom.cfg.Binary)
+}
@@ -0,0 +1,270 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// Acceptance criteria are verified one by one. A criterion can declare how it
+// is checked with a prefix:
+//
+//	cmd: go test ./internal/auth/...      the command must exit 0
+//	file: internal/auth/jwt.go            the file must exist
+//	file: go.mod matches golang-jwt       the file must match the regex
+//	regex: (?i)all tests pass             the agent output must match the regex
+//
+// Any other criterion is judged by the model's JSON verdict, falling back
+// to the <promise>TASK COMPLETE</promise> tag when the model gives none.
+const (
+	MethodCmd     = "cmd"
+	MethodFile    = "file"
+	MethodRegex   = "regex"
+	MethodModel   = "model"
+	MethodPromise = "promise"
+
+	completionTag       = "<promise>TASK COMPLETE</promise>"
+	maxEvidenceBytes    = 2000
+	defaultCriterionCmd = 10 * time.Minute
+)
+
+var verdictPattern = regexp.MustCompile(`(?s)<verdict>(.*?)</verdict>`)
+
+// modelVerdict is one entry of the model's <verdict> JSON.
+type modelVerdict struct {
+	Index     int    `json:"index"` // 1-based position in the acceptance list
+	Criterion string `json:"criterion"`
+	Passed    bool   `json:"passed"`
+	Evidence  string `json:"evidence"`
+}
+
+// CriteriaVerifier checks each acceptance criterion of a task separately.
+type CriteriaVerifier struct {
+	WorkDir    string        // where cmd: runs and file: paths resolve
+	CmdTimeout time.Duration // per cmd: criterion
+}
+
+// NewCriteriaVerifier creates a verifier rooted at workDir ("." if empty).
+func NewCriteriaVerifier(workDir string) *CriteriaVerifier {
+	if workDir == "" {
+		workDir = "."
+	}
+	return &CriteriaVerifier{WorkDir: workDir, CmdTimeout: defaultCriterionCmd}
+}
+
+// Verify returns one result per criterion, in order.
+func (v *CriteriaVerifier) Verify(ctx context.Context, output string, criteria []string) []models.CriterionResult {
+	verdicts := parseVerdicts(output)
+	completed := strings.Contains(output, completionTag)
+
+	results := make([]models.CriterionResult, 0, len(criteria))
+	for i, criterion := range criteria {
+		var r models.CriterionResult
+		method, arg := splitCriterion(criterion)
+		switch method {
+		case MethodCmd:
+			r = v.checkCmd(ctx, arg)
+		case MethodFile:
+			r = v.checkFile(arg)
+		case MethodRegex:
+			r = checkRegex(output, arg)
+		default:
+			if verdict, ok := findVerdict(verdicts, i, criterion); ok {
+				r = models.CriterionResult{Passed: verdict.Passed, Method: MethodModel, Evidence: verdict.Evidence}
+			} else {
+				r = models.CriterionResult{Passed: completed, Method: MethodPromise}
+				if !completed {
+					r.Evidence = "no verdict and no completion tag in output"
+				}
+			}
+		}
+		r.Criterion = criterion
+		results = append(results, r)
+	}
+	return results
+}
+
+// SplitResults separates criteria into met and failed lists.
+func SplitResults(results []models.CriterionResult) (met []string, failed []string) {
+	met, failed = []string{}, []string{}
+	for _, r := range results {
+		if r.Passed {
+			met = append(met, r.Criterion)
+		} else {
+			failed = append(failed, r.Criterion)
+		}
+	}
+	return met, failed
+}
+
+func (v *CriteriaVerifier) checkCmd(ctx context.Context, command string) models.CriterionResult {
+	r := models.CriterionResult{Method: MethodCmd}
+	if command == "" {
+		r.Evidence = "empty command"
+		return r
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, v.CmdTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, "sh", "-c", command)
+	cmd.Dir = v.WorkDir
+	out, err := cmd.CombinedOutput()
+	evidence := tail(string(out), maxEvidenceBytes)
+
+	switch {
+	case err == nil:
+		r.Passed = true
+		r.Evidence = evidence
+	case cmdCtx.Err() == context.DeadlineExceeded:
+		r.Evidence = fmt.Sprintf("timed out after %s\n%s", v.CmdTimeout, evidence)
+	default:
+		code := -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			code = exitErr.ExitCode()
+		}
+		r.Evidence = strings.TrimSpace(fmt.Sprintf("exit code %d\n%s", code, evidence))
+	}
+	return r
+}
+
+func (v *CriteriaVerifier) checkFile(arg string) models.CriterionResult {
+	r := models.CriterionResult{Method: MethodFile}
+	path, pattern := arg, ""
+	if i := strings.Index(arg, " matches "); i >= 0 {
+		path, pattern = strings.TrimSpace(arg[:i]), strings.TrimSpace(arg[i+len(" matches "):])
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(v.WorkDir, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		r.Evidence = fmt.Sprintf("cannot read %s: %v", path, err)
+		return r
+	}
+	if pattern == "" {
+		r.Passed = true
+		r.Evidence = fmt.Sprintf("%s exists", path)
+		return r
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		r.Evidence = fmt.Sprintf("invalid regex %q: %v", pattern, err)
+		return r
+	}
+	if loc := re.FindIndex(data); loc != nil {
+		r.Passed = true
+		r.Evidence = fmt.Sprintf("%s matches: %s", path, firstLine(string(data[loc[0]:loc[1]])))
+	} else {
+		r.Evidence = fmt.Sprintf("%s does not match %q", path, pattern)
+	}
+	return r
+}
+
+func checkRegex(output, pattern string) models.CriterionResult {
+	r := models.CriterionResult{Method: MethodRegex}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		r.Evidence = fmt.Sprintf("invalid regex %q: %v", pattern, err)
+		return r
+	}
+	if m := re.FindString(output); m != "" || re.MatchString(output) {
+		r.Passed = true
+		r.Evidence = fmt.Sprintf("output matches: %s", firstLine(m))
+	} else {
+		r.Evidence = fmt.Sprintf("output does not match %q", pattern)
+	}
+	return r
+}
+
+// splitCriterion returns the check method and its argument, or "" when the
+// criterion is prose.
+func splitCriterion(criterion string) (string, string) {
+	trimmed := strings.TrimSpace(criterion)
+	for _, method := range []string{MethodCmd, MethodFile, MethodRegex} {
+		if strings.HasPrefix(trimmed, method+":") {
+			return method, strings.TrimSpace(trimmed[len(method)+1:])
+		}
+	}
+	return "", trimmed
+}
+
+// parseVerdicts extracts the model's per-criterion verdicts. Both a bare
+// array and {"criteria": [...]} are accepted; the last verdict block wins.
+func parseVerdicts(output string) []modelVerdict {
+	matches := verdictPattern.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	raw := strings.TrimSpace(matches[len(matches)-1][1])
+
+	var list []modelVerdict
+	if err := json.Unmarshal([]byte(raw), &list); err == nil {
+		return list
+	}
+	var wrapped struct {
+		Criteria []modelVerdict `json:"criteria"`
+	}
+	if err := json.Unmarshal([]byte(raw), &wrapped); err == nil {
+		return wrapped.Criteria
+	}
+	return nil
+}
+
+// findVerdict matches a verdict by 1-based index or by criterion text.
+func findVerdict(verdicts []modelVerdict, index int, criterion string) (modelVerdict, bool) {
+	for _, v := range verdicts {
+		if v.Index == index+1 {
+			return v, true
+		}
+	}
+	for _, v := range verdicts {
+		if v.Criterion != "" && strings.EqualFold(strings.TrimSpace(v.Criterion), strings.TrimSpace(criterion)) {
+			return v, true
+		}
+	}
+	return modelVerdict{}, false
+}
+
+// verifyInto runs per-criterion verification for task and records the
+// results, met/failed lists and success on result.
+func verifyInto(ctx context.Context, result *models.AgentExecutionResult, task *models.Task, workDir string) {
+	result.Criteria = NewCriteriaVerifier(workDir).Verify(ctx, result.Output, task.Acceptance)
+	result.CriteriaMet, result.CriteriaFailed = SplitResults(result.Criteria)
+	result.Success = result.ExitCode == 0 && len(result.CriteriaFailed) == 0
+}
+
+// taskWorkDir returns the task's worktree when it exists, else ".".
+func taskWorkDir(task *models.Task) string {
+	if task.WorktreePath != "" {
+		if info, err := os.Stat(task.WorktreePath); err == nil && info.IsDir() {
+			return task.WorktreePath
+		}
+	}
+	return "."
+}
+
+func tail(s string, max int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= max {
+		return s
+	}
+	return "..." + s[len(s)-max:]
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package agents
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCriteriaVerifier_Cmd(t *testing.T) {
+	v := NewCriteriaVerifier(t.TempDir())
+	results := v.Verify(context.Background(), "", []string{
+		"cmd: echo ok",
+		"cmd: echo broken >&2; exit 2",
+	})
+	require.Len(t, results, 2)
+
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, MethodCmd, results[0].Method)
+	assert.Equal(t, "ok", results[0].Evidence)
+
+	assert.False(t, results[1].Passed)
+	assert.Contains(t, results[1].Evidence, "exit code 2")
+	assert.Contains(t, results[1].Evidence, "broken")
+}
+
+func TestCriteriaVerifier_FileAndRegex(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module x\nrequire github.com/golang-jwt/jwt v5\n"), 0644))
+
+	v := NewCriteriaVerifier(dir)
+	results := v.Verify(context.Background(), "All 12 tests passed", []string{
+		"file: go.mod",
+		"file: missing.go",
+		"file: go.mod matches golang-jwt/jwt",
+		"file: go.mod matches ^nope",
+		"regex: \\d+ tests passed",
+		"regex: FAIL",
+	})
+
+	passed := []bool{}
+	for _, r := range results {
+		passed = append(passed, r.Passed)
+	}
+	assert.Equal(t, []bool{true, false, true, false, true, false}, passed)
+	assert.Equal(t, MethodFile, results[0].Method)
+	assert.Equal(t, MethodRegex, results[4].Method)
+	assert.Equal(t, "output matches: 12 tests passed", results[4].Evidence)
+}
+
+func TestCriteriaVerifier_ModelVerdict(t *testing.T) {
+	output := `Implemented.
+<verdict>[
+  {"index": 1, "passed": true, "evidence": "handler added"},
+  {"criterion": "returns 404 for unknown ids", "passed": false, "evidence": "not handled yet"}
+]</verdict>
+<promise>TASK COMPLETE</promise>`
+
+	results := NewCriteriaVerifier("").Verify(context.Background(), output, []string{
+		"endpoint exists",
+		"Returns 404 for unknown IDs",
+		"docs updated",
+	})
+	require.Len(t, results, 3)
+
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, MethodModel, results[0].Method)
+	assert.Equal(t, "handler added", results[0].Evidence)
+
+	// Matched by text, case-insensitively; the verdict overrides the promise tag
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "not handled yet", results[1].Evidence)
+
+	// No verdict: falls back to the completion tag
+	assert.True(t, results[2].Passed)
+	assert.Equal(t, MethodPromise, results[2].Method)
+}
+
+func TestCriteriaVerifier_WrappedVerdictAndCmdOverride(t *testing.T) {
+	// The model claims the command passes, but commands are always run
+	output := `<verdict>{"criteria": [{"index": 1, "passed": true}, {"index": 2, "passed": true}]}</verdict>`
+	results := NewCriteriaVerifier("").Verify(context.Background(), output, []string{"cmd: exit 1", "prose"})
+
+	assert.False(t, results[0].Passed)
+	assert.Equal(t, MethodCmd, results[0].Method)
+	assert.True(t, results[1].Passed)
+	assert.Equal(t, MethodModel, results[1].Method)
+}
+
+func TestSplitResults(t *testing.T) {
+	met, failed := SplitResults(NewCriteriaVerifier("").Verify(context.Background(), "", []string{"cmd: true", "cmd: false"}))
+	assert.Equal(t, []string{"cmd: true"}, met)
+	assert.Equal(t, []string{"cmd: false"}, failed)
+}
@@ -3,6 +3,7 @@ package agents
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/javierbenavides/agentic-agent/pkg/models"
 )
@@ -15,29 +16,78 @@ type executor struct {
 	agentType string
 }
 
+// NewExecutor creates the executor for an agent name. Agents with a
+// headless CLI (claude, copilot, codex, gemini, opencode) run through the
+// command executor with their preset (see CLIPreset).
 func NewExecutor(agentType string) Executor {
+	if preset, ok := CLIPreset(agentType, ""); ok {
+		return NewCommandExecutor(preset, "", 0)
+	}
 	switch agentType {
 	case "mock":
 		return &executor{agentType: "mock"}
-	case "claude-code", "claude":
+	case "anthropic":
 		return NewClaudeExecutor("", "")
-	case "copilot", "github-copilot":
-		return NewCopilotExecutor("")
-	case "gemini":
-		return NewGeminiExecutor("", "")
 	case "cursor":
 		return NewCursorExecutor("")
-	case "codex", "openai":
+	case "openai":
 		return NewCodexExecutor("", "")
 	case "antigravity":
 		return NewAntigravityExecutor("")
-	case "opencode":
-		return NewOpenCodeExecutor("")
 	default:
 		return &executor{agentType: agentType}
 	}
 }
 
+// NewExecutorWithConfig creates an executor using the agent's override from
+// agnostic-agent.yaml (nil for none). The override's Type selects the
+// executor, so a custom-named agent can point at any OpenAI-compatible server:
+//
+//	agents:
+//	  overrides:
+//	    - name: local-llama
+//	      type: openai
+//	      base_url: http://localhost:8080/v1
+//	      model: qwen2.5-coder
+//
+// Type "command" runs any headless agent CLI described by the override's
+// command block (see CommandConfig); a command block on a CLI agent replaces
+// its preset, and the override's model and timeout apply to the preset.
+func NewExecutorWithConfig(agentType string, agentCfg *models.AgentConfig) Executor {
+	if agentCfg == nil {
+		return NewExecutor(agentType)
+	}
+	if agentCfg.Type != "" {
+		agentType = agentCfg.Type
+	}
+
+	if agentType == "command" || agentCfg.Command != nil {
+		if agentCfg.Command == nil {
+			return &executor{agentType: "command"}
+		}
+		return NewCommandExecutor(*agentCfg.Command, agentCfg.Model, time.Duration(agentCfg.TimeoutSeconds)*time.Second)
+	}
+
+	if preset, ok := CLIPreset(agentType, agentCfg.Model); ok {
+		return NewCommandExecutor(preset, agentCfg.Model, time.Duration(agentCfg.TimeoutSeconds)*time.Second)
+	}
+
+	switch agentType {
+	case "openai":
+		return NewCodexExecutorWithConfig(OpenAIConfig{
+			BaseURL:    agentCfg.BaseURL,
+			Model:      agentCfg.Model,
+			APIKeyEnv:  agentCfg.APIKeyEnv,
+			Timeout:    time.Duration(agentCfg.TimeoutSeconds) * time.Second,
+			MaxRetries: agentCfg.MaxRetries,
+		})
+	case "anthropic":
+		return NewClaudeExecutor("", agentCfg.Model)
+	default:
+		return NewExecutor(agentType)
+	}
+}
+
 func (e *executor) Execute(ctx context.Context, prompt string, task *models.Task) (*models.AgentExecutionResult, error) {
 	// Mock implementation for testing
 	if e.agentType == "mock" {
@@ -49,5 +99,9 @@ func (e *executor) Execute(ctx context.Context, prompt string, task *models.Task
 		}, nil
 	}
 
+	if e.agentType == "command" {
+		return nil, fmt.Errorf("agent type command requires a command block in its agents override")
+	}
+
 	return nil, fmt.Errorf("unsupported agent type: %s", e.agentType)
 }
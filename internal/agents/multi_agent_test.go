@@ -2,6 +2,8 @@ package agents
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"testing"
 
 	"github.com/javierbenavides/agentic-agent/pkg/models"
@@ -13,16 +15,17 @@ func TestNewExecutor_AllAgents(t *testing.T) {
 		agentType string
 		wantType  string
 	}{
-		{"Claude", "claude", "*agents.ClaudeExecutor"},
-		{"Claude Code", "claude-code", "*agents.ClaudeExecutor"},
-		{"Copilot", "copilot", "*agents.CopilotExecutor"},
-		{"GitHub Copilot", "github-copilot", "*agents.CopilotExecutor"},
-		{"Gemini", "gemini", "*agents.GeminiExecutor"},
+		{"Claude", "claude", "*agents.CommandExecutor"},
+		{"Claude Code", "claude-code", "*agents.CommandExecutor"},
+		{"Anthropic", "anthropic", "*agents.ClaudeExecutor"},
+		{"Copilot", "copilot", "*agents.CommandExecutor"},
+		{"GitHub Copilot", "github-copilot", "*agents.CommandExecutor"},
+		{"Gemini", "gemini", "*agents.CommandExecutor"},
 		{"Cursor", "cursor", "*agents.CursorExecutor"},
-		{"Codex", "codex", "*agents.CodexExecutor"},
+		{"Codex", "codex", "*agents.CommandExecutor"},
 		{"OpenAI", "openai", "*agents.CodexExecutor"},
 		{"Antigravity", "antigravity", "*agents.AntigravityExecutor"},
-		{"OpenCode", "opencode", "*agents.OpenCodeExecutor"},
+		{"OpenCode", "opencode", "*agents.CommandExecutor"},
 		{"Mock", "mock", "*agents.executor"},
 		{"Unknown", "unknown", "*agents.executor"},
 	}
@@ -30,60 +33,29 @@ func TestNewExecutor_AllAgents(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			executor := NewExecutor(tt.agentType)
-			if executor == nil {
-				t.Error("Expected executor, got nil")
+			if got := fmt.Sprintf("%T", executor); got != tt.wantType {
+				t.Errorf("Expected %s, got %s", tt.wantType, got)
 			}
 		})
 	}
 }
 
-func TestCopilotExecutor_Execute(t *testing.T) {
-	executor := NewCopilotExecutor("")
-	task := &models.Task{
-		ID:    "TASK-123",
-		Title: "Test task",
-		Acceptance: []string{
-			"Must complete successfully",
-		},
-	}
-
-	result, err := executor.Execute(context.Background(), "Test prompt", task)
-
-	// Copilot executor may fail if gh cli not available, but should not panic
+// chdirTemp runs the rest of the test in a temporary directory, so
+// executors that write prompt files under .agentic leave the package alone.
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	origDir, err := os.Getwd()
 	if err != nil {
-		t.Logf("Copilot execution failed (expected if gh cli not installed): %v", err)
-	}
-
-	if result != nil && result.Output == "" {
-		t.Error("Expected output or error, got empty result")
-	}
-}
-
-func TestGeminiExecutor_Execute(t *testing.T) {
-	executor := NewGeminiExecutor("", "")
-	task := &models.Task{
-		ID:    "TASK-123",
-		Title: "Test task",
-		Acceptance: []string{
-			"Must complete successfully",
-		},
-	}
-
-	result, err := executor.Execute(context.Background(), "Test prompt", task)
-	if err != nil {
-		t.Fatalf("Expected no error, got %v", err)
-	}
-
-	// Should return placeholder until implemented
-	if result.Success {
-		t.Error("Expected placeholder response to be not successful")
+		t.Fatal(err)
 	}
-	if result.ErrorMessage == "" {
-		t.Error("Expected error message for unimplemented executor")
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
 	}
+	t.Cleanup(func() { os.Chdir(origDir) })
 }
 
 func TestCursorExecutor_Execute(t *testing.T) {
+	chdirTemp(t)
 	executor := NewCursorExecutor("")
 	task := &models.Task{
 		ID:    "TASK-123",
@@ -105,6 +77,7 @@ func TestCursorExecutor_Execute(t *testing.T) {
 }
 
 func TestCodexExecutor_Execute(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
 	executor := NewCodexExecutor("", "")
 	task := &models.Task{
 		ID:    "TASK-123",
@@ -114,14 +87,10 @@ func TestCodexExecutor_Execute(t *testing.T) {
 		},
 	}
 
-	result, err := executor.Execute(context.Background(), "Test prompt", task)
-	if err != nil {
-		t.Fatalf("Expected no error, got %v", err)
-	}
-
-	// Should return placeholder until implemented
-	if result.Success {
-		t.Error("Expected placeholder response to be not successful")
+	// The hosted API needs a key; without one no request is made
+	_, err := executor.Execute(context.Background(), "Test prompt", task)
+	if err == nil {
+		t.Fatal("Expected missing API key error")
 	}
 }
 
@@ -146,28 +115,7 @@ func TestAntigravityExecutor_Execute(t *testing.T) {
 	}
 }
 
-func TestOpenCodeExecutor_Execute(t *testing.T) {
-	executor := NewOpenCodeExecutor("")
-	task := &models.Task{
-		ID:    "TASK-123",
-		Title: "Test task",
-		Acceptance: []string{
-			"Must complete successfully",
-		},
-	}
-
-	result, err := executor.Execute(context.Background(), "Test prompt", task)
-	if err != nil {
-		t.Fatalf("Expected no error, got %v", err)
-	}
-
-	// Should return placeholder until implemented
-	if result.Success {
-		t.Error("Expected placeholder response to be not successful")
-	}
-}
-
-func TestCheckCriteria(t *testing.T) {
+func TestCriteriaVerifier_CompletionSignal(t *testing.T) {
 	tests := []struct {
 		name           string
 		output         string
@@ -193,7 +141,7 @@ func TestCheckCriteria(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			met, failed := checkCriteria(tt.output, tt.criteria)
+			met, failed := SplitResults(NewCriteriaVerifier(t.TempDir()).Verify(context.Background(), tt.output, tt.criteria))
 			if len(met) != tt.wantMet {
 				t.Errorf("Expected %d met criteria, got %d", tt.wantMet, len(met))
 			}
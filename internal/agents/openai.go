@@ -0,0 +1,234 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+const (
+	DefaultOpenAIBaseURL   = "https://api.openai.com/v1"
+	DefaultOpenAIAPIKeyEnv = "OPENAI_API_KEY"
+	defaultOpenAITimeout   = 120 * time.Second
+	defaultOpenAIRetries   = 2
+)
+
+// OpenAIConfig configures a client for any OpenAI-compatible
+// chat-completions endpoint: OpenAI itself, or local servers such as
+// llama.cpp, vLLM and Ollama's /v1 endpoint.
+type OpenAIConfig struct {
+	BaseURL    string        // defaults to DefaultOpenAIBaseURL
+	Model      string        // defaults to gpt-4
+	APIKey     string        // takes precedence over APIKeyEnv
+	APIKeyEnv  string        // defaults to DefaultOpenAIAPIKeyEnv
+	Timeout    time.Duration // per request; defaults to 120s
+	MaxRetries int           // retries on network errors, 429 and 5xx; defaults to 2, negative disables
+	HTTPClient *http.Client  // optional; Timeout is ignored when set
+}
+
+// CodexExecutor executes tasks through an OpenAI-compatible chat-completions API
+type CodexExecutor struct {
+	baseURL    string
+	apiKey     string
+	apiKeyEnv  string
+	model      string
+	maxRetries int
+	client     *http.Client
+	retryDelay time.Duration
+}
+
+func NewCodexExecutor(apiKey, model string) *CodexExecutor {
+	return NewCodexExecutorWithConfig(OpenAIConfig{APIKey: apiKey, Model: model})
+}
+
+// NewCodexExecutorWithConfig creates an executor for the endpoint described by cfg.
+func NewCodexExecutorWithConfig(cfg OpenAIConfig) *CodexExecutor {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultOpenAIBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = "gpt-4"
+	}
+	if cfg.APIKeyEnv == "" {
+		cfg.APIKeyEnv = DefaultOpenAIAPIKeyEnv
+	}
+	if cfg.APIKey == "" {
+		cfg.APIKey = os.Getenv(cfg.APIKeyEnv)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultOpenAITimeout
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultOpenAIRetries
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	return &CodexExecutor{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:     cfg.APIKey,
+		apiKeyEnv:  cfg.APIKeyEnv,
+		model:      cfg.Model,
+		maxRetries: cfg.MaxRetries,
+		client:     client,
+		retryDelay: 500 * time.Millisecond,
+	}
+}
+
+// chatRequest and chatResponse cover the subset of the chat-completions
+// schema that compatible servers agree on.
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (e *CodexExecutor) Execute(ctx context.Context, prompt string, task *models.Task) (*models.AgentExecutionResult, error) {
+	// Only the hosted API requires a key; local servers usually accept none
+	if e.apiKey == "" && e.baseURL == DefaultOpenAIBaseURL {
+		return nil, fmt.Errorf("openai api key not set (export %s or configure api_key_env)", e.apiKeyEnv)
+	}
+
+	fullPrompt := buildStandardPrompt(prompt, task)
+	body, err := json.Marshal(chatRequest{
+		Model:    e.model,
+		Messages: []chatMessage{{Role: "user", Content: fullPrompt}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	resp, err := e.send(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("openai api error: response has no choices")
+	}
+
+	output := resp.Choices[0].Message.Content
+	tokens := resp.Usage.TotalTokens
+	if tokens == 0 {
+		// Some local servers omit usage
+		tokens = estimateTokens(fullPrompt + output)
+	}
+
+	result := &models.AgentExecutionResult{
+		Output:     output,
+		TokensUsed: tokens,
+	}
+	verifyInto(ctx, result, task, taskWorkDir(task))
+	return result, nil
+}
+
+// send posts the request, retrying transient failures with exponential backoff.
+func (e *CodexExecutor) send(ctx context.Context, body []byte) (*chatResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt <= e.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := e.retryDelay << (attempt - 1)
+			var ra *retryAfterError
+			if errors.As(lastErr, &ra) && ra.after > 0 {
+				delay = ra.after
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		resp, retry, err := e.sendOnce(ctx, body)
+		if err == nil {
+			return resp, nil
+		}
+		if !retry || ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("openai api error after %d attempts: %w", e.maxRetries+1, lastErr)
+}
+
+// retryAfterError carries the server's Retry-After hint.
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+func (r *retryAfterError) Error() string { return r.err.Error() }
+func (r *retryAfterError) Unwrap() error { return r.err }
+
+// sendOnce performs a single request and reports whether a failure is retryable.
+func (e *CodexExecutor) sendOnce(ctx context.Context, body []byte) (*chatResponse, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	httpResp, err := e.client.Do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("openai api request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var resp chatResponse
+	decodeErr := json.Unmarshal(data, &resp)
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(data))
+		if decodeErr == nil && resp.Error != nil && resp.Error.Message != "" {
+			msg = resp.Error.Message
+		}
+		err := fmt.Errorf("openai api error: status %d: %s", httpResp.StatusCode, msg)
+		retry := httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode >= 500
+		if secs, convErr := strconv.Atoi(httpResp.Header.Get("Retry-After")); convErr == nil && retry {
+			return nil, true, &retryAfterError{err: err, after: time.Duration(secs) * time.Second}
+		}
+		return nil, retry, err
+	}
+	if decodeErr != nil {
+		return nil, false, fmt.Errorf("failed to decode response: %w", decodeErr)
+	}
+	return &resp, false, nil
+}
@@ -0,0 +1,167 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chatCompletion(content string, tokens int) map[string]interface{} {
+	return map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"message": map[string]string{"role": "assistant", "content": content}},
+		},
+		"usage": map[string]int{"total_tokens": tokens},
+	}
+}
+
+func TestCodexExecutor_ChatCompletion(t *testing.T) {
+	var got chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		json.NewEncoder(w).Encode(chatCompletion("Done <promise>TASK COMPLETE</promise>", 42))
+	}))
+	defer server.Close()
+
+	executor := NewCodexExecutorWithConfig(OpenAIConfig{
+		BaseURL: server.URL + "/v1/",
+		Model:   "qwen2.5-coder",
+		APIKey:  "sk-test",
+	})
+	task := &models.Task{ID: "T-1", Acceptance: []string{"Tests pass"}}
+
+	result, err := executor.Execute(context.Background(), "Implement it", task)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"Tests pass"}, result.CriteriaMet)
+	assert.Equal(t, 42, result.TokensUsed)
+
+	assert.Equal(t, "qwen2.5-coder", got.Model)
+	require.Len(t, got.Messages, 1)
+	assert.Equal(t, "user", got.Messages[0].Role)
+	assert.Contains(t, got.Messages[0].Content, "Implement it")
+	assert.Contains(t, got.Messages[0].Content, "- Tests pass")
+}
+
+func TestCodexExecutor_LocalServerWithoutKey(t *testing.T) {
+	t.Setenv("LOCAL_LLM_KEY", "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		// No usage block, like some local servers
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"content": "not yet"}}},
+		})
+	}))
+	defer server.Close()
+
+	executor := NewCodexExecutorWithConfig(OpenAIConfig{BaseURL: server.URL, APIKeyEnv: "LOCAL_LLM_KEY"})
+	result, err := executor.Execute(context.Background(), "prompt", &models.Task{Acceptance: []string{"x"}})
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, []string{"x"}, result.CriteriaFailed)
+	assert.Greater(t, result.TokensUsed, 0)
+}
+
+func TestCodexExecutor_RetriesTransientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			json.NewEncoder(w).Encode(chatCompletion("ok", 1))
+		}
+	}))
+	defer server.Close()
+
+	executor := NewCodexExecutorWithConfig(OpenAIConfig{BaseURL: server.URL, MaxRetries: 2})
+	executor.retryDelay = time.Millisecond
+
+	result, err := executor.Execute(context.Background(), "prompt", &models.Task{})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result.Output)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCodexExecutor_GivesUpAfterRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	executor := NewCodexExecutorWithConfig(OpenAIConfig{BaseURL: server.URL, MaxRetries: 1})
+	executor.retryDelay = time.Millisecond
+
+	_, err := executor.Execute(context.Background(), "prompt", &models.Task{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 2 attempts")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCodexExecutor_ClientErrorNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+	}))
+	defer server.Close()
+
+	executor := NewCodexExecutorWithConfig(OpenAIConfig{BaseURL: server.URL})
+	_, err := executor.Execute(context.Background(), "prompt", &models.Task{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401: invalid api key")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCodexExecutor_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	executor := NewCodexExecutorWithConfig(OpenAIConfig{BaseURL: server.URL, Timeout: 20 * time.Millisecond, MaxRetries: -1})
+	_, err := executor.Execute(context.Background(), "prompt", &models.Task{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "request failed")
+}
+
+func TestNewExecutorWithConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(chatCompletion("hi", 1))
+	}))
+	defer server.Close()
+
+	exec := NewExecutorWithConfig("local-llama", &models.AgentConfig{
+		Name:    "local-llama",
+		Type:    "openai",
+		BaseURL: server.URL,
+		Model:   "llama3",
+	})
+	codex, ok := exec.(*CodexExecutor)
+	require.True(t, ok)
+	assert.Equal(t, "llama3", codex.model)
+	assert.Equal(t, server.URL, codex.baseURL)
+
+	// Without a type override the agent name decides
+	_, ok = NewExecutorWithConfig("claude", &models.AgentConfig{Name: "claude"}).(*CommandExecutor)
+	assert.True(t, ok)
+	_, ok = NewExecutorWithConfig("claude", &models.AgentConfig{Name: "claude", Type: "anthropic"}).(*ClaudeExecutor)
+	assert.True(t, ok)
+	_, ok = NewExecutorWithConfig("mock", nil).(*executor)
+	assert.True(t, ok)
+}
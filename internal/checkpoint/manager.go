@@ -12,17 +12,18 @@ import (
 
 // Checkpoint represents a saved state during task execution
 type Checkpoint struct {
-	TaskID        string    `json:"task_id"`
-	Iteration     int       `json:"iteration"`
-	TokensUsed    int       `json:"tokens_used"`
-	CreatedAt     time.Time `json:"created_at"`
-	Agent         string    `json:"agent"`
-	Output        string    `json:"output"`
-	CriteriaMet   []string  `json:"criteria_met"`
-	CriteriaLeft  []string  `json:"criteria_left"`
-	FilesModified []string  `json:"files_modified"`
-	Learnings     []string  `json:"learnings"`
-	Notes         string    `json:"notes"`
+	TaskID        string                   `json:"task_id"`
+	Iteration     int                      `json:"iteration"`
+	TokensUsed    int                      `json:"tokens_used"`
+	CreatedAt     time.Time                `json:"created_at"`
+	Agent         string                   `json:"agent"`
+	Output        string                   `json:"output"`
+	CriteriaMet   []string                 `json:"criteria_met"`
+	CriteriaLeft  []string                 `json:"criteria_left"`
+	Evidence      []models.CriterionResult `json:"evidence,omitempty"` // per-criterion verification results
+	FilesModified []string                 `json:"files_modified"`
+	Learnings     []string                 `json:"learnings"`
+	Notes         string                   `json:"notes"`
 }
 
 // Manager handles checkpoint creation and retrieval
@@ -218,6 +219,7 @@ func CreateFromResult(taskID string, iteration int, agent string, result *models
 		Output:        result.Output,
 		CriteriaMet:   result.CriteriaMet,
 		CriteriaLeft:  result.CriteriaFailed,
+		Evidence:      result.Criteria,
 		FilesModified: result.FilesModified,
 		Notes:         fmt.Sprintf("Iteration %d: %d/%d criteria met", iteration, len(result.CriteriaMet), len(task.Acceptance)),
 	}
@@ -245,6 +245,34 @@ func TestCreateFromResult(t *testing.T) {
 	}
 }
 
+func TestCheckpointManager_PersistsEvidence(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	result := &models.AgentExecutionResult{
+		CriteriaMet:    []string{"cmd: go test ./..."},
+		CriteriaFailed: []string{"file: README.md"},
+		Criteria: []models.CriterionResult{
+			{Criterion: "cmd: go test ./...", Passed: true, Method: "cmd", Evidence: "ok"},
+			{Criterion: "file: README.md", Passed: false, Method: "file", Evidence: "cannot read README.md"},
+		},
+	}
+	task := &models.Task{ID: "TASK-001", Acceptance: []string{"cmd: go test ./...", "file: README.md"}}
+
+	if err := manager.Save(CreateFromResult("TASK-001", 1, "claude-code", result, task)); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+	loaded, err := manager.Load("TASK-001")
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+
+	if len(loaded.Evidence) != 2 {
+		t.Fatalf("Expected 2 evidence entries, got %d", len(loaded.Evidence))
+	}
+	if loaded.Evidence[1].Passed || loaded.Evidence[1].Evidence != "cannot read README.md" {
+		t.Errorf("Unexpected evidence: %+v", loaded.Evidence[1])
+	}
+}
+
 func TestCheckpointManager_FileStructure(t *testing.T) {
 	tmpDir := t.TempDir()
 	manager := NewManager(tmpDir)
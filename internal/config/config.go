@@ -92,9 +92,27 @@ func GetAgentConfig(cfg *models.Config, agentName string) *models.AgentConfig {
 			result.SkillPacks = override.SkillPacks
 			result.ExtraRules = override.ExtraRules
 			result.AutoSetup = override.AutoSetup
+			result.Type = override.Type
+			result.BaseURL = override.BaseURL
+			result.APIKeyEnv = override.APIKeyEnv
+			result.TimeoutSeconds = override.TimeoutSeconds
+			result.MaxRetries = override.MaxRetries
+			result.Command = override.Command
 			break
 		}
 	}
 
 	return result
 }
+
+// GetAgentOverride returns the override declared for agentName, or nil.
+// Unlike GetAgentConfig it does not fall back to the shared defaults, so an
+// executor keeps its own default model unless the override names one.
+func GetAgentOverride(cfg *models.Config, agentName string) *models.AgentConfig {
+	for i := range cfg.Agents.Overrides {
+		if cfg.Agents.Overrides[i].Name == agentName {
+			return &cfg.Agents.Overrides[i]
+		}
+	}
+	return nil
+}
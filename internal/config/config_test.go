@@ -72,3 +72,32 @@ func TestLoadConfig_EmptyPath(t *testing.T) {
 	_, err := LoadConfig("")
 	assert.Error(t, err)
 }
+
+func TestGetAgentConfig_ExecutorOverrides(t *testing.T) {
+	cfg := &models.Config{}
+	cfg.Agents.Defaults.Model = "default-model"
+	cfg.Agents.Overrides = []models.AgentConfig{{
+		Name:           "local-llama",
+		Type:           "openai",
+		BaseURL:        "http://localhost:11434/v1",
+		Model:          "qwen2.5-coder",
+		APIKeyEnv:      "LOCAL_LLM_KEY",
+		TimeoutSeconds: 300,
+		MaxRetries:     3,
+	}}
+
+	agentCfg := GetAgentConfig(cfg, "local-llama")
+	assert.Equal(t, "openai", agentCfg.Type)
+	assert.Equal(t, "http://localhost:11434/v1", agentCfg.BaseURL)
+	assert.Equal(t, "qwen2.5-coder", agentCfg.Model)
+	assert.Equal(t, "LOCAL_LLM_KEY", agentCfg.APIKeyEnv)
+	assert.Equal(t, 300, agentCfg.TimeoutSeconds)
+	assert.Equal(t, 3, agentCfg.MaxRetries)
+
+	other := GetAgentConfig(cfg, "claude")
+	assert.Empty(t, other.Type)
+	assert.Equal(t, "default-model", other.Model)
+
+	assert.Equal(t, "http://localhost:11434/v1", GetAgentOverride(cfg, "local-llama").BaseURL)
+	assert.Nil(t, GetAgentOverride(cfg, "claude"))
+}
@@ -0,0 +1,281 @@
+package context
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sourceExtensions are the files the generator analyzes and lists as key files.
+var sourceExtensions = map[string]bool{
+	".go": true,
+	".ts": true, ".tsx": true, ".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+	".py": true,
+}
+
+// symbolKind groups exported symbols into responsibility lines.
+type symbolKind int
+
+const (
+	kindInterface symbolKind = iota
+	kindType
+	kindClass
+	kindFunction
+	kindConstant
+	kindVariable
+	kindExport // re-exports and CommonJS exports whose kind is unknown
+)
+
+// symbolKindLabels are the responsibility line labels, in output order.
+var symbolKindLabels = []string{"Interfaces", "Types", "Classes", "Functions", "Constants", "Variables", "Exports"}
+
+// packageLabel prefixes the Go package responsibility line.
+const packageLabel = "Package"
+
+const (
+	maxSymbolsPerLine = 12
+	maxMethodsPerType = 6
+)
+
+type symbol struct {
+	name    string
+	kind    symbolKind
+	methods []string // Go methods, attached after all files are read
+}
+
+// ImportRef is one import statement of a source file.
+type ImportRef struct {
+	Path string // module path as written: "net/http", "../models", ".tokens"
+	Line int
+}
+
+// FileImports lists the imports of a source file with their line numbers.
+// Files in other languages have none.
+func FileImports(path string) ([]ImportRef, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filepath.Ext(path) {
+	case ".go":
+		fset := token.NewFileSet()
+		f, _ := parser.ParseFile(fset, path, src, parser.ImportsOnly)
+		if f == nil {
+			return nil, nil
+		}
+		var refs []ImportRef
+		for _, imp := range f.Imports {
+			if p, err := strconv.Unquote(imp.Path.Value); err == nil {
+				refs = append(refs, ImportRef{Path: p, Line: fset.Position(imp.Pos()).Line})
+			}
+		}
+		return refs, nil
+	case ".py":
+		return pythonImports(maskSource(string(src), true)), nil
+	}
+	if IsSourceFile(path) {
+		return scriptImports(maskSource(string(src), false)), nil
+	}
+	return nil, nil
+}
+
+// fileAnalysis is what the language analyzers extract from one source file.
+type fileAnalysis struct {
+	name    string
+	pkg     string   // Go package clause
+	imports []string // dependencies outside the directory
+	local   []string // other files in the directory this file uses
+	symbols []symbol
+}
+
+// directoryAnalysis is the aggregate of every source file in a directory.
+type directoryAnalysis struct {
+	files []*fileAnalysis
+}
+
+// analyzeDirectory reads the non-test source files of dir with the analyzer
+// for their language.
+func analyzeDirectory(dir string) (*directoryAnalysis, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var goFiles, otherFiles []string
+	names := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsDir() || !sourceExtensions[filepath.Ext(e.Name())] || IsTestFile(e.Name()) {
+			continue
+		}
+		names[e.Name()] = true
+		if filepath.Ext(e.Name()) == ".go" {
+			goFiles = append(goFiles, e.Name())
+		} else {
+			otherFiles = append(otherFiles, e.Name())
+		}
+	}
+
+	a := &directoryAnalysis{}
+	a.files = append(a.files, analyzeGoFiles(dir, goFiles)...)
+	for _, name := range otherFiles {
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		switch filepath.Ext(name) {
+		case ".py":
+			a.files = append(a.files, analyzePython(dir, name, string(src), names))
+		default:
+			a.files = append(a.files, analyzeScript(dir, name, string(src), names))
+		}
+	}
+	sort.Slice(a.files, func(i, j int) bool { return a.files[i].name < a.files[j].name })
+	return a, nil
+}
+
+// IsSourceFile reports whether name is a Go, TypeScript, JavaScript or
+// Python source file.
+func IsSourceFile(name string) bool {
+	return sourceExtensions[filepath.Ext(name)]
+}
+
+// IsTestFile reports whether name follows a test naming convention of its
+// language. Tests are not part of a directory's public surface.
+func IsTestFile(name string) bool {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	switch filepath.Ext(name) {
+	case ".go":
+		return strings.HasSuffix(base, "_test")
+	case ".py":
+		return strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test") || base == "conftest"
+	}
+	return strings.HasSuffix(base, ".test") || strings.HasSuffix(base, ".spec")
+}
+
+// keyFiles ranks files by fan-in: the number of other files in the
+// directory that use them. Ties keep alphabetical order.
+func (a *directoryAnalysis) keyFiles() []string {
+	fanIn := make(map[string]int)
+	for _, f := range a.files {
+		for _, target := range unique(f.local) {
+			if target != f.name {
+				fanIn[target]++
+			}
+		}
+	}
+
+	files := make([]string, 0, len(a.files))
+	for _, f := range a.files {
+		files = append(files, f.name)
+	}
+	sort.SliceStable(files, func(i, j int) bool { return fanIn[files[i]] > fanIn[files[j]] })
+	return files
+}
+
+// dependencies lists imports in sorted order, so regenerating a context
+// only changes it when the imports do.
+func (a *directoryAnalysis) dependencies() []string {
+	var deps []string
+	for _, f := range a.files {
+		deps = append(deps, f.imports...)
+	}
+	deps = unique(deps)
+	sort.Strings(deps)
+	return deps
+}
+
+// responsibilities renders the exported surface as one line per symbol
+// kind, e.g. "Interfaces: Store" and "Types: Manager (Load, Save)".
+func (a *directoryAnalysis) responsibilities() []string {
+	var lines []string
+
+	var pkgs []string
+	for _, f := range a.files {
+		if f.pkg != "" {
+			pkgs = append(pkgs, f.pkg)
+		}
+	}
+	if pkgs = unique(pkgs); len(pkgs) > 0 {
+		lines = append(lines, packageLabel+": "+strings.Join(pkgs, ", "))
+	}
+
+	byKind := make([][]string, len(symbolKindLabels))
+	seen := make(map[string]bool)
+	for _, f := range a.files {
+		for _, s := range f.symbols {
+			if seen[s.name] {
+				continue
+			}
+			seen[s.name] = true
+			entry := s.name
+			if len(s.methods) > 0 {
+				entry += " (" + summarizeNames(s.methods, maxMethodsPerType) + ")"
+			}
+			byKind[s.kind] = append(byKind[s.kind], entry)
+		}
+	}
+	for kind, names := range byKind {
+		if len(names) > 0 {
+			lines = append(lines, symbolKindLabels[kind]+": "+summarizeNames(names, maxSymbolsPerLine))
+		}
+	}
+	return lines
+}
+
+// summarizeNames joins names, eliding all but the first max.
+func summarizeNames(names []string, max int) string {
+	if len(names) <= max {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(names[:max], ", "), len(names)-max)
+}
+
+// isGeneratedResponsibility reports whether r is a line written by the
+// generator, including the single "Exported symbols" line of older files.
+func isGeneratedResponsibility(r string) bool {
+	if strings.HasPrefix(r, exportedSymbolsPrefix) || strings.HasPrefix(r, packageLabel+": ") {
+		return true
+	}
+	for _, label := range symbolKindLabels {
+		if strings.HasPrefix(r, label+": ") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveLocal maps a relative module path (already joined with dir) to a
+// file in dir, trying the given extensions. It returns the file name and
+// true when the module is part of the directory itself.
+func resolveLocal(dir, resolved string, files map[string]bool, exts []string) (string, bool) {
+	if filepath.Clean(filepath.Dir(resolved)) != filepath.Clean(dir) {
+		return "", false
+	}
+	base := filepath.Base(resolved)
+	if files[base] {
+		return base, true
+	}
+	for _, ext := range exts {
+		if files[base+ext] {
+			return base + ext, true
+		}
+	}
+	return "", false
+}
+
+// moduleDir turns a relative module path into the directory holding it, so
+// "src/models/post" becomes "src/models" when post.ts exists there.
+func moduleDir(resolved string, exts []string) string {
+	for _, ext := range append([]string{""}, exts...) {
+		if info, err := os.Stat(resolved + ext); err == nil && !info.IsDir() {
+			return filepath.ToSlash(filepath.Dir(resolved))
+		}
+	}
+	return filepath.ToSlash(resolved)
+}
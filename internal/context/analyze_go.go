@@ -0,0 +1,153 @@
+package context
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// analyzeGoFiles parses the Go files of a directory together, so methods
+// can be attached to types declared in other files and each file's uses of
+// package-level names can be traced to the file declaring them.
+func analyzeGoFiles(dir string, names []string) []*fileAnalysis {
+	fset := token.NewFileSet()
+
+	var results []*fileAnalysis
+	var parsed []*ast.File
+	declaredIn := make(map[string]string) // package-level name -> file
+	types := make(map[string]*symbol)
+	methods := make(map[string][]string) // receiver type -> exported methods
+
+	for _, name := range names {
+		// A file with syntax errors still yields a partial AST worth reading.
+		f, _ := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if f == nil || f.Name == nil {
+			continue
+		}
+
+		fa := &fileAnalysis{name: name, pkg: f.Name.Name}
+		for _, imp := range f.Imports {
+			if path, err := strconv.Unquote(imp.Path.Value); err == nil && !isStdlibImport(path) {
+				fa.imports = append(fa.imports, path)
+			}
+		}
+
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					declare(declaredIn, d.Name.Name, name)
+					if d.Name.IsExported() {
+						fa.symbols = append(fa.symbols, symbol{name: d.Name.Name, kind: kindFunction})
+					}
+					continue
+				}
+				if recv := receiverType(d.Recv); recv != "" && ast.IsExported(recv) && d.Name.IsExported() {
+					methods[recv] = append(methods[recv], d.Name.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						declare(declaredIn, s.Name.Name, name)
+						if !s.Name.IsExported() {
+							continue
+						}
+						kind := kindType
+						if _, ok := s.Type.(*ast.InterfaceType); ok {
+							kind = kindInterface
+						}
+						fa.symbols = append(fa.symbols, symbol{name: s.Name.Name, kind: kind})
+					case *ast.ValueSpec:
+						kind := kindVariable
+						if d.Tok == token.CONST {
+							kind = kindConstant
+						}
+						for _, id := range s.Names {
+							declare(declaredIn, id.Name, name)
+							if id.IsExported() {
+								fa.symbols = append(fa.symbols, symbol{name: id.Name, kind: kind})
+							}
+						}
+					}
+				}
+			}
+		}
+
+		results = append(results, fa)
+		parsed = append(parsed, f)
+	}
+
+	for _, fa := range results {
+		for i := range fa.symbols {
+			if fa.symbols[i].kind == kindType || fa.symbols[i].kind == kindInterface {
+				types[fa.symbols[i].name] = &fa.symbols[i]
+			}
+		}
+	}
+	for recv, ms := range methods {
+		if t, ok := types[recv]; ok {
+			t.methods = ms
+		}
+	}
+
+	for i, f := range parsed {
+		fa := results[i]
+		ast.Inspect(f, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok {
+				if owner, ok := declaredIn[id.Name]; ok && owner != fa.name {
+					fa.local = append(fa.local, owner)
+				}
+			}
+			return true
+		})
+	}
+	return results
+}
+
+// declare records the file declaring a package-level name. The blank
+// identifier and names redeclared under other build tags keep the first file.
+func declare(declaredIn map[string]string, name, file string) {
+	if name == "_" {
+		return
+	}
+	if _, ok := declaredIn[name]; !ok {
+		declaredIn[name] = file
+	}
+}
+
+// receiverType returns the base type name of a method receiver, without
+// pointer or type parameters.
+func receiverType(recv *ast.FieldList) string {
+	if recv == nil || len(recv.List) == 0 {
+		return ""
+	}
+	expr := recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// isStdlibImport reports whether a Go import path belongs to the standard
+// library (or is cgo's "C"): module paths start with a domain, so their
+// first element contains a dot.
+func isStdlibImport(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,354 @@
+package context
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// scriptExtensions are tried, in order, when resolving an extensionless
+// JS/TS module path.
+var scriptExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"}
+
+var pythonExtensions = []string{".py"}
+
+// The scanners below run on source whose comments and multi-line string
+// bodies have been blanked out (see maskSource), so patterns anchored at
+// line starts only match real statements.
+var (
+	jsImportFromPattern = regexp.MustCompile(`(?m)^[ \t]*(?:import|export)\b[^;'"` + "`" + `]*?\bfrom\s*['"]([^'"\n]+)['"]`)
+	jsBareImportPattern = regexp.MustCompile(`(?m)^[ \t]*import\s*['"]([^'"\n]+)['"]`)
+	jsCallImportPattern = regexp.MustCompile(`\b(?:require|import)\s*\(\s*['"]([^'"\n]+)['"]\s*\)`)
+
+	jsExportPatterns = []struct {
+		re   *regexp.Regexp
+		kind symbolKind
+	}{
+		{regexp.MustCompile(`(?m)^[ \t]*export\s+(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?class\s+([A-Za-z_$][\w$]*)`), kindClass},
+		{regexp.MustCompile(`(?m)^[ \t]*export\s+(?:default\s+)?(?:declare\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)`), kindFunction},
+		{regexp.MustCompile(`(?m)^[ \t]*export\s+(?:declare\s+)?interface\s+([A-Za-z_$][\w$]*)`), kindInterface},
+		{regexp.MustCompile(`(?m)^[ \t]*export\s+(?:declare\s+)?type\s+([A-Za-z_$][\w$]*)`), kindType},
+		{regexp.MustCompile(`(?m)^[ \t]*export\s+(?:declare\s+)?(?:const\s+)?enum\s+([A-Za-z_$][\w$]*)`), kindType},
+		{regexp.MustCompile(`(?m)^[ \t]*export\s+(?:declare\s+)?const\s+([A-Za-z_$][\w$]*)\s*[=:]`), kindConstant},
+		{regexp.MustCompile(`(?m)^[ \t]*export\s+(?:declare\s+)?(?:let|var)\s+([A-Za-z_$][\w$]*)`), kindVariable},
+		{regexp.MustCompile(`(?m)^[ \t]*(?:module\.)?exports\.([A-Za-z_$][\w$]*)\s*=`), kindExport},
+	}
+	jsExportListPattern    = regexp.MustCompile(`(?m)^[ \t]*export\s+(?:type\s+)?\{([^}]*)\}`)
+	jsExportDefaultPattern = regexp.MustCompile(`(?m)^[ \t]*export\s+default\s+([^\n]*)`)
+	jsModuleExportsPattern = regexp.MustCompile(`(?m)^[ \t]*module\.exports\s*=\s*(\{[^}]*\}|[A-Za-z_$][\w$]*)`)
+	jsIdentifierPattern    = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+	jsNamedDeclarationPattern = regexp.MustCompile(`^(?:abstract\s+)?class\s+[A-Za-z_$]|^(?:async\s+)?function\s*\*?\s*[A-Za-z_$]`)
+
+	pyImportPattern     = regexp.MustCompile(`(?m)^[ \t]*import[ \t]+([^\n]+)`)
+	pyFromImportPattern = regexp.MustCompile(`(?m)^[ \t]*from[ \t]+(\.*[\w.]*)[ \t]+import[ \t]+(\([^)]*\)|[^\n]+)`)
+	pyDefPattern        = regexp.MustCompile(`(?m)^(?:async[ \t]+)?def[ \t]+([A-Za-z_]\w*)`)
+	pyClassPattern      = regexp.MustCompile(`(?m)^class[ \t]+([A-Za-z_]\w*)`)
+	pyConstantPattern   = regexp.MustCompile(`(?m)^([A-Z][A-Z0-9_]*)[ \t]*(?::[^=\n]*)?=[^=]`)
+	pyAllPattern        = regexp.MustCompile(`(?m)^__all__[ \t]*(?::[^=\n]*)?\+?=[ \t]*[\[(]([^\])]*)[\])]`)
+	quotedNamePattern   = regexp.MustCompile(`['"]([^'"]+)['"]`)
+)
+
+// positioned orders symbols found by separate patterns by source offset.
+type positioned struct {
+	pos int
+	sym symbol
+}
+
+// analyzeScript scans a JavaScript or TypeScript file for imports and
+// exported symbols. Relative imports of files in the same directory become
+// local references; other relative imports are reported as the directory
+// they point to.
+func analyzeScript(dir, name, src string, files map[string]bool) *fileAnalysis {
+	code := maskSource(src, false)
+	fa := &fileAnalysis{name: name}
+
+	for _, ref := range scriptImports(code) {
+		spec := ref.Path
+		if !strings.HasPrefix(spec, "./") && !strings.HasPrefix(spec, "../") {
+			fa.imports = append(fa.imports, spec)
+			continue
+		}
+		resolved := filepath.Join(dir, spec)
+		if local, ok := resolveLocal(dir, resolved, files, scriptExtensions); ok {
+			fa.local = append(fa.local, local)
+			continue
+		}
+		if filepath.Clean(resolved) != filepath.Clean(dir) {
+			fa.imports = append(fa.imports, moduleDir(resolved, scriptExtensions))
+		}
+	}
+
+	var found []positioned
+	for _, p := range jsExportPatterns {
+		for _, m := range p.re.FindAllStringSubmatchIndex(code, -1) {
+			found = append(found, positioned{m[2], symbol{name: code[m[2]:m[3]], kind: p.kind}})
+		}
+	}
+	for _, m := range jsExportListPattern.FindAllStringSubmatchIndex(code, -1) {
+		for _, name := range exportListNames(code[m[2]:m[3]]) {
+			found = append(found, positioned{m[2], symbol{name: name, kind: kindExport}})
+		}
+	}
+	for _, m := range jsModuleExportsPattern.FindAllStringSubmatchIndex(code, -1) {
+		for _, name := range exportListNames(strings.Trim(code[m[2]:m[3]], "{}")) {
+			found = append(found, positioned{m[2], symbol{name: name, kind: kindExport}})
+		}
+	}
+	for _, m := range jsExportDefaultPattern.FindAllStringSubmatchIndex(code, -1) {
+		rest := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(code[m[2]:m[3]]), ";"))
+		switch {
+		case jsNamedDeclarationPattern.MatchString(rest):
+			// Named declarations are picked up by jsExportPatterns.
+		case jsIdentifierPattern.MatchString(rest):
+			found = append(found, positioned{m[2], symbol{name: rest, kind: kindExport}})
+		default:
+			found = append(found, positioned{m[2], symbol{name: "default", kind: kindExport}})
+		}
+	}
+
+	fa.symbols = orderSymbols(found)
+	return fa
+}
+
+// exportListNames reads "a, b as c, type D" into the exported names a, c
+// and D, and the keys of a CommonJS object literal "{ a, b: impl }".
+func exportListNames(list string) []string {
+	var names []string
+	for _, part := range strings.Split(list, ",") {
+		var name string
+		if key, _, ok := strings.Cut(part, ":"); ok {
+			name = strings.TrimSpace(key)
+		} else {
+			fields := strings.Fields(part)
+			if len(fields) > 1 && fields[0] == "type" {
+				fields = fields[1:]
+			}
+			switch {
+			case len(fields) == 0:
+				continue
+			case len(fields) >= 3 && fields[len(fields)-2] == "as":
+				name = fields[len(fields)-1]
+			default:
+				name = fields[0]
+			}
+		}
+		if jsIdentifierPattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// analyzePython scans a Python module for imports and its public names: the
+// __all__ list when there is one, otherwise top-level functions, classes and
+// UPPER_CASE constants not starting with an underscore.
+func analyzePython(dir, name, src string, files map[string]bool) *fileAnalysis {
+	code := maskSource(src, true)
+	fa := &fileAnalysis{name: name}
+
+	for _, ref := range pythonImports(code) {
+		fa.addPythonImport(dir, ref.Path, files)
+	}
+
+	var found []positioned
+	for _, p := range []struct {
+		re   *regexp.Regexp
+		kind symbolKind
+	}{{pyDefPattern, kindFunction}, {pyClassPattern, kindClass}, {pyConstantPattern, kindConstant}} {
+		for _, m := range p.re.FindAllStringSubmatchIndex(code, -1) {
+			found = append(found, positioned{m[2], symbol{name: code[m[2]:m[3]], kind: p.kind}})
+		}
+	}
+	defined := orderSymbols(found)
+
+	// __all__ is matched on the original source, since its names are strings.
+	var all []string
+	for _, m := range pyAllPattern.FindAllStringSubmatch(src, -1) {
+		for _, q := range quotedNamePattern.FindAllStringSubmatch(m[1], -1) {
+			all = append(all, q[1])
+		}
+	}
+	if len(all) == 0 {
+		for _, s := range defined {
+			if !strings.HasPrefix(s.name, "_") {
+				fa.symbols = append(fa.symbols, s)
+			}
+		}
+		return fa
+	}
+	kinds := make(map[string]symbolKind)
+	for _, s := range defined {
+		kinds[s.name] = s.kind
+	}
+	for _, n := range unique(all) {
+		kind, ok := kinds[n]
+		if !ok {
+			kind = kindExport
+		}
+		fa.symbols = append(fa.symbols, symbol{name: n, kind: kind})
+	}
+	return fa
+}
+
+// scriptImports returns the module specifiers imported by masked JS/TS
+// code: import and export-from statements, require() and import().
+func scriptImports(code string) []ImportRef {
+	var refs []ImportRef
+	for _, re := range []*regexp.Regexp{jsImportFromPattern, jsBareImportPattern, jsCallImportPattern} {
+		for _, m := range re.FindAllStringSubmatchIndex(code, -1) {
+			refs = append(refs, ImportRef{Path: code[m[2]:m[3]], Line: lineAt(code, m[2])})
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].Line < refs[j].Line })
+	return refs
+}
+
+// pythonImports returns the dotted modules imported by masked Python code.
+// "from . import a" yields ".a", since a is a sibling module.
+func pythonImports(code string) []ImportRef {
+	var refs []ImportRef
+	for _, m := range pyImportPattern.FindAllStringSubmatchIndex(code, -1) {
+		for _, part := range strings.Split(code[m[2]:m[3]], ",") {
+			if fields := strings.Fields(part); len(fields) > 0 {
+				refs = append(refs, ImportRef{Path: fields[0], Line: lineAt(code, m[0])})
+			}
+		}
+	}
+	for _, m := range pyFromImportPattern.FindAllStringSubmatchIndex(code, -1) {
+		module, line := code[m[2]:m[3]], lineAt(code, m[2])
+		if strings.Trim(module, ".") != "" {
+			refs = append(refs, ImportRef{Path: module, Line: line})
+			continue
+		}
+		for _, part := range strings.Split(strings.Trim(code[m[4]:m[5]], "()"), ",") {
+			if fields := strings.Fields(part); len(fields) > 0 {
+				refs = append(refs, ImportRef{Path: module + fields[0], Line: line})
+			}
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].Line < refs[j].Line })
+	return refs
+}
+
+// lineAt returns the 1-based line of offset pos.
+func lineAt(code string, pos int) int {
+	return 1 + strings.Count(code[:pos], "\n")
+}
+
+// addPythonImport records a dotted module path. Relative modules and
+// top-level modules that are files of dir are local references; other
+// relative modules are reported as the directory they live in.
+func (fa *fileAnalysis) addPythonImport(dir, module string, files map[string]bool) {
+	dots := len(module) - len(strings.TrimLeft(module, "."))
+	parts := strings.Split(strings.TrimLeft(module, "."), ".")
+
+	if dots == 0 {
+		if files[parts[0]+".py"] {
+			fa.local = append(fa.local, parts[0]+".py")
+			return
+		}
+		fa.imports = append(fa.imports, module)
+		return
+	}
+
+	base := dir
+	for i := 1; i < dots; i++ {
+		base = filepath.Join(base, "..")
+	}
+	resolved := filepath.Join(append([]string{base}, parts...)...)
+	if dots == 1 {
+		if local, ok := resolveLocal(dir, filepath.Join(dir, parts[0]), files, pythonExtensions); ok {
+			fa.local = append(fa.local, local)
+			return
+		}
+	}
+	fa.imports = append(fa.imports, moduleDir(resolved, pythonExtensions))
+}
+
+func orderSymbols(found []positioned) []symbol {
+	sort.SliceStable(found, func(i, j int) bool { return found[i].pos < found[j].pos })
+	symbols := make([]symbol, 0, len(found))
+	seen := make(map[string]bool)
+	for _, f := range found {
+		if !seen[f.sym.name] {
+			seen[f.sym.name] = true
+			symbols = append(symbols, f.sym)
+		}
+	}
+	return symbols
+}
+
+// maskSource blanks out comments and the bodies of multi-line strings
+// (JS template literals, Python triple-quoted strings), keeping offsets and
+// line breaks, so line-anchored patterns only see code. Single-line string
+// literals are kept because import paths live in them. python selects '#'
+// comments and triple quotes instead of '//' and '/* */' comments and
+// backticks.
+func maskSource(src string, python bool) string {
+	out := []byte(src)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(out); i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case python && c == '#', !python && strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			blank(i, i+end)
+			i += end
+		case !python && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			blank(i, i+2+end+2)
+			i += 2 + end + 2
+		case python && (strings.HasPrefix(src[i:], `"""`) || strings.HasPrefix(src[i:], `'''`)):
+			quote := src[i : i+3]
+			end := strings.Index(src[i+3:], quote)
+			if end < 0 {
+				end = len(src) - i - 3
+			}
+			blank(i+3, i+3+end)
+			i += 3 + end + 3
+		case !python && c == '`':
+			end := closingQuote(src, i+1, '`', true)
+			blank(i+1, end)
+			i = end + 1
+		case c == '"' || c == '\'':
+			i = closingQuote(src, i+1, c, false) + 1
+		default:
+			i++
+		}
+	}
+	return string(out)
+}
+
+// closingQuote returns the index of the quote ending a string literal that
+// starts at from, honouring backslash escapes. Single-line literals also end
+// at a line break.
+func closingQuote(src string, from int, quote byte, multiline bool) int {
+	for i := from; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		case '\n':
+			if !multiline {
+				return i
+			}
+		}
+	}
+	return len(src)
+}
@@ -0,0 +1,210 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestGenerateContext_Go(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"store.go": `// Package store persists widgets.
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMissing is returned for unknown widgets, "quoted" in docs.
+var ErrMissing = errors.New("missing: \"widget\"")
+
+const MaxWidgets = 10
+const internalLimit = 3
+
+type Store interface {
+	Get(id string) (*Widget, error)
+}
+
+type Widget struct{ ID string }
+
+func (w *Widget) String() string { return fmt.Sprint(w.ID) }
+func (w Widget) validate() bool  { return w.ID != "" }
+`,
+		"memory.go": `package store
+
+import "sync"
+
+type Memory[K comparable] struct {
+	mu sync.Mutex
+	items map[K]*Widget
+}
+
+func NewMemory() *Memory[string] { return &Memory[string]{} }
+
+func (m *Memory[K]) Get(id K) (*Widget, error) {
+	if id == *new(K) {
+		return nil, ErrMissing
+	}
+	return nil, nil
+}
+`,
+		"cache.go": `package store
+
+import (
+	"fmt"
+
+	"example.com/metrics"
+	"github.com/acme/log"
+)
+
+func Warm(s Store) { fmt.Println(s, MaxWidgets); metrics.Inc(); log.Print() }
+`,
+		"store_test.go": `package store
+
+import "testing"
+
+func TestNothing(t *testing.T) {}
+`,
+		"notes.txt": "not source",
+	})
+
+	ctx, err := GenerateContext(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Package: store",
+		"Interfaces: Store",
+		"Types: Memory (Get), Widget (String)",
+		"Functions: Warm, NewMemory",
+		"Constants: MaxWidgets",
+		"Variables: ErrMissing",
+	}, ctx.Responsibilities)
+
+	// Only module imports are dependencies: no stdlib, no other string literals
+	assert.Equal(t, []string{"example.com/metrics", "github.com/acme/log"}, ctx.Dependencies)
+
+	// store.go declares what both other files use; tests are not key files
+	assert.Equal(t, []string{"store.go", "cache.go", "memory.go"}, ctx.KeyFiles)
+	assert.Equal(t, "Contains 3 source files. Implements functionality related to "+filepath.Base(dir)+".", ctx.Purpose)
+}
+
+func TestGenerateContext_TypeScript(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "src", "api")
+	writeFiles(t, root, map[string]string{
+		"src/models/post.ts": "export interface Post { id: string }\n",
+		"src/api/index.ts": `import { Router } from 'express';
+import type { Post } from '../models/post';
+import { handler } from "./handlers";
+export * from './routes';
+export { handler as default2, type Options } from './handlers';
+`,
+		"src/api/handlers.ts": `// import { fake } from 'commented-out';
+import * as db from "../db";
+const help = ` + "`\nimport { notReal } from 'template'\n`" + `;
+/* export function hidden() {} */
+export async function handler(req: Request) {}
+export const TIMEOUT = 30;
+export let counter = 0;
+export const enum Mode { A }
+export type Options = { debug: boolean };
+export default class Api {}
+`,
+		"src/api/routes.js": `const express = require('express');
+const { handler } = require('./handlers.ts');
+function list() {}
+module.exports = { list, create: list };
+`,
+		"src/api/handlers.test.ts": "import { handler } from './handlers';\n",
+	})
+
+	ctx, err := GenerateContext(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Types: Mode, Options",
+		"Classes: Api",
+		"Functions: handler",
+		"Constants: TIMEOUT",
+		"Variables: counter",
+		"Exports: default2, list, create",
+	}, ctx.Responsibilities)
+	assert.Equal(t, []string{filepath.ToSlash(filepath.Join(root, "src", "db")), filepath.ToSlash(filepath.Join(root, "src", "models")), "express"}, ctx.Dependencies)
+	assert.Equal(t, []string{"handlers.ts", "routes.js", "index.ts"}, ctx.KeyFiles)
+}
+
+func TestGenerateContext_Python(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "app", "services")
+	writeFiles(t, root, map[string]string{
+		"app/models.py": "class User: pass\n",
+		"app/services/auth.py": `"""Auth service.
+
+import fake_module
+"""
+import os, json as j
+from typing import (
+    Optional,
+    List,
+)
+from .. import models
+from .tokens import sign  # from secret import nothing
+from . import cache
+
+MAX_ATTEMPTS = 3
+_PRIVATE = 1
+
+class AuthService:
+    def login(self): pass
+
+def authenticate(user): pass
+
+def _helper(): pass
+`,
+		"app/services/tokens.py": `__all__ = ["sign", "VERSION"]
+
+VERSION = "1"
+
+def sign(data): pass
+
+def verify(data): pass
+`,
+		"app/services/cache.py":     "import tokens\n\nasync def get(key): pass\n",
+		"app/services/test_auth.py": "from .auth import authenticate\n",
+	})
+
+	ctx, err := GenerateContext(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Classes: AuthService",
+		"Functions: authenticate, get, sign",
+		"Constants: MAX_ATTEMPTS, VERSION",
+	}, ctx.Responsibilities)
+	assert.Equal(t, []string{filepath.ToSlash(filepath.Join(root, "app")), "json", "os", "typing"}, ctx.Dependencies)
+	assert.Equal(t, []string{"tokens.py", "cache.py", "auth.py"}, ctx.KeyFiles)
+}
+
+func TestMaskSource(t *testing.T) {
+	src := "a // c 'x'\nb /* m\nm */ 'k // not comment'\n`t\nt`"
+	masked := maskSource(src, false)
+	assert.Len(t, masked, len(src))
+	assert.Equal(t, "a         \nb     \n     'k // not comment'\n` \n `", masked)
+
+	py := "x = '#' # c\n'''doc\nimport y'''\n"
+	assert.Equal(t, "x = '#'    \n'''   \n        '''\n", maskSource(py, true))
+}
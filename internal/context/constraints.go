@@ -0,0 +1,140 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// ConstraintKind is a machine-checkable rule written in a context.md
+// "Must Do" or "Cannot Do" item.
+type ConstraintKind string
+
+const (
+	// ForbidImport rejects imports of a module path or anything below it.
+	ForbidImport ConstraintKind = "forbid-import"
+	// ForbidPattern rejects source lines matching a regular expression.
+	ForbidPattern ConstraintKind = "forbid-pattern"
+	// RequireFile requires a file, or a glob match, in the directory.
+	RequireFile ConstraintKind = "require-file"
+	// RequireTest requires a test file next to each source file matching a
+	// glob, or every source file when no glob is given.
+	RequireTest ConstraintKind = "require-test"
+)
+
+var constraintKinds = []ConstraintKind{ForbidImport, ForbidPattern, RequireFile, RequireTest}
+
+// backtickPattern finds inline code spans, where directives may be written
+// after prose: "No raw SQL `forbid-import: database/sql`".
+var backtickPattern = regexp.MustCompile("`([^`]+)`")
+
+// Constraint is one directive parsed from a directory context.
+type Constraint struct {
+	Kind    ConstraintKind
+	Value   string         // module path, pattern source or glob
+	Pattern *regexp.Regexp // compiled Value of a ForbidPattern
+	Item    string         // the list item it came from, for reporting
+}
+
+// ParseConstraints extracts the directives from the Must Do and Cannot Do
+// items of ctx. An item holds at most one directive, either as the whole
+// item or in an inline code span; items without one are guidance for
+// humans and agents only. Malformed directives are returned as errors
+// alongside the valid constraints.
+func ParseConstraints(ctx *models.DirectoryContext) ([]Constraint, []error) {
+	var constraints []Constraint
+	var errs []error
+	for _, item := range append(append([]string{}, ctx.MustDo...), ctx.CannotDo...) {
+		c, ok, err := parseConstraint(item)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			constraints = append(constraints, c)
+		}
+	}
+	return constraints, errs
+}
+
+func parseConstraint(item string) (Constraint, bool, error) {
+	candidates := []string{item}
+	for _, m := range backtickPattern.FindAllStringSubmatch(item, -1) {
+		candidates = append(candidates, m[1])
+	}
+
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		for _, kind := range constraintKinds {
+			rest, ok := strings.CutPrefix(candidate, string(kind))
+			if !ok || (rest != "" && !strings.HasPrefix(rest, ":")) {
+				continue
+			}
+			c := Constraint{Kind: kind, Value: strings.TrimSpace(strings.TrimPrefix(rest, ":")), Item: item}
+			return c, true, c.compile()
+		}
+	}
+	return Constraint{}, false, nil
+}
+
+func (c *Constraint) compile() error {
+	switch c.Kind {
+	case ForbidPattern:
+		if c.Value == "" {
+			return fmt.Errorf("%s needs a pattern: %q", c.Kind, c.Item)
+		}
+		re, err := regexp.Compile(c.Value)
+		if err != nil {
+			return fmt.Errorf("invalid %s in %q: %w", c.Kind, c.Item, err)
+		}
+		c.Pattern = re
+	case ForbidImport, RequireFile:
+		if c.Value == "" {
+			return fmt.Errorf("%s needs a value: %q", c.Kind, c.Item)
+		}
+		fallthrough
+	case RequireTest:
+		if _, err := filepath.Match(c.Value, ""); err != nil {
+			return fmt.Errorf("invalid %s glob in %q: %w", c.Kind, c.Item, err)
+		}
+	}
+	return nil
+}
+
+// MatchesImport reports whether a ForbidImport constraint covers the
+// import path: the path itself, anything below it ("database/sql/driver",
+// or "sqlalchemy.orm" for Python), or anything matching it as a glob
+// ("github.com/aws/*").
+func (c Constraint) MatchesImport(path string) bool {
+	value := strings.TrimSuffix(c.Value, "/")
+	if path == value || strings.HasPrefix(path, value+"/") {
+		return true
+	}
+	if !strings.Contains(path, "/") && strings.HasPrefix(path, value+".") {
+		return true
+	}
+	ok, _ := filepath.Match(value, path)
+	return ok
+}
+
+// TestFileCandidates returns the conventional test file names for a source
+// file, any one of which satisfies RequireTest.
+func TestFileCandidates(name string) []string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	switch ext {
+	case ".go":
+		return []string{base + "_test.go"}
+	case ".py":
+		return []string{"test_" + name, base + "_test.py", filepath.Join("tests", "test_"+name)}
+	}
+	var names []string
+	for _, suffix := range []string{".test", ".spec"} {
+		names = append(names, base+suffix+ext)
+		names = append(names, filepath.Join("__tests__", base+suffix+ext))
+	}
+	return names
+}
@@ -0,0 +1,58 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConstraints(t *testing.T) {
+	constraints, errs := ParseConstraints(&models.DirectoryContext{
+		MustDo: []string{
+			"require-test",
+			"Keep a changelog `require-file: CHANGELOG.md`",
+			"require-tests for everything", // not a directive
+		},
+		CannotDo: []string{
+			"No ORM `forbid-import: gorm.io/gorm`",
+			"forbid-pattern: panic\\(",
+			"`forbid-pattern: [`",
+			"forbid-import:",
+		},
+	})
+
+	require.Len(t, constraints, 4)
+	assert.Equal(t, Constraint{Kind: RequireTest, Item: "require-test"}, constraints[0])
+	assert.Equal(t, RequireFile, constraints[1].Kind)
+	assert.Equal(t, "CHANGELOG.md", constraints[1].Value)
+	assert.Equal(t, ForbidImport, constraints[2].Kind)
+	assert.Equal(t, "gorm.io/gorm", constraints[2].Value)
+	assert.Equal(t, ForbidPattern, constraints[3].Kind)
+	assert.True(t, constraints[3].Pattern.MatchString("panic(err)"))
+
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "invalid forbid-pattern")
+	assert.Contains(t, errs[1].Error(), "forbid-import needs a value")
+}
+
+func TestConstraint_MatchesImport(t *testing.T) {
+	cases := []struct {
+		value, path string
+		want        bool
+	}{
+		{"database/sql", "database/sql", true},
+		{"database/sql", "database/sql/driver", true},
+		{"database/sql/", "database/sql/driver", true},
+		{"database/sql", "database/sqlx", false},
+		{"github.com/aws/*", "github.com/aws/aws-sdk-go", true},
+		{"sqlalchemy", "sqlalchemy.orm", true},
+		{"github", "github.com/acme/x", false},
+		{"src/db", "src/db/query", true},
+	}
+	for _, tc := range cases {
+		c := Constraint{Kind: ForbidImport, Value: tc.value}
+		assert.Equal(t, tc.want, c.MatchesImport(tc.path), "%s vs %s", tc.value, tc.path)
+	}
+}
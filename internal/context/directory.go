@@ -1,7 +1,6 @@
 package context
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -24,19 +23,40 @@ func (dcm *DirectoryContextManager) LoadContext(dir string) (*models.DirectoryCo
 		return nil, err
 	}
 
-	// Mock parsing for MVP
-	return &models.DirectoryContext{
-		Path:    dir,
-		Purpose: string(data), // Just dump all content here for now
-	}, nil
+	return ParseContextDocument(data).Context(dir), nil
 }
 
+// SaveContext writes ctx to dir/context.md. An existing file is rewritten
+// in place: sections whose values did not change, hand-written prose and
+// sections the parser does not know are kept as they were.
 func (dcm *DirectoryContextManager) SaveContext(dir string, ctx *models.DirectoryContext) error {
-	content := fmt.Sprintf("# Context for %s\n\n## Purpose\n%s\n\n## Responsibilities\n%s\n\n## Dependencies\n%s\n",
-		ctx.Path, ctx.Purpose, strings.Join(ctx.Responsibilities, "\n- "), strings.Join(ctx.Dependencies, "\n- "))
-
 	path := filepath.Join(dir, "context.md")
-	return os.WriteFile(path, []byte(content), 0644)
+
+	doc := NewContextDocument(ctx.Path)
+	if data, err := os.ReadFile(path); err == nil {
+		doc = ParseContextDocument(data)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	doc.SetContext(ctx)
+	return os.WriteFile(path, doc.Bytes(), 0644)
+}
+
+// UpdateContext merges freshly generated facts into dir/context.md without
+// overwriting what humans wrote; see MergeContext. A missing file is
+// created from generated.
+func (dcm *DirectoryContextManager) UpdateContext(dir string, generated *models.DirectoryContext) (*models.DirectoryContext, error) {
+	existing, err := dcm.LoadContext(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		return generated, dcm.SaveContext(dir, generated)
+	}
+
+	merged := MergeContext(existing, generated)
+	return merged, dcm.SaveContext(dir, merged)
 }
 
 func (dcm *DirectoryContextManager) FindContextDirs(root string) ([]string, error) {
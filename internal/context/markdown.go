@@ -0,0 +1,432 @@
+package context
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// Fields of models.DirectoryContext that map onto context.md sections.
+const (
+	fieldPurpose           = "purpose"
+	fieldResponsibilities  = "responsibilities"
+	fieldLocalArchitecture = "local_architecture"
+	fieldDependencies      = "dependencies"
+	fieldMustDo            = "must_do"
+	fieldCannotDo          = "cannot_do"
+	fieldKeyFiles          = "key_files"
+)
+
+// contextSections lists the sections written for a new file, in order.
+var contextSections = []struct{ field, heading string }{
+	{fieldPurpose, "Purpose"},
+	{fieldResponsibilities, "Responsibilities"},
+	{fieldLocalArchitecture, "Local Architecture"},
+	{fieldDependencies, "Dependencies"},
+	{fieldMustDo, "Must Do"},
+	{fieldCannotDo, "Cannot Do"},
+	{fieldKeyFiles, "Key Files"},
+}
+
+// sectionAliases maps normalized headings, including the variants used in
+// the docs and templates, to fields. Other headings are kept as unknown
+// sections.
+var sectionAliases = map[string]string{
+	"purpose":            fieldPurpose,
+	"overview":           fieldPurpose,
+	"responsibilities":   fieldResponsibilities,
+	"responsibility":     fieldResponsibilities,
+	"local architecture": fieldLocalArchitecture,
+	"architecture":       fieldLocalArchitecture,
+	"architectural role": fieldLocalArchitecture,
+	"patterns used":      fieldLocalArchitecture,
+	"patterns":           fieldLocalArchitecture,
+	"dependencies":       fieldDependencies,
+	"dependency rules":   fieldDependencies,
+	"must do":            fieldMustDo,
+	"cannot do":          fieldCannotDo,
+	"you cannot do":      fieldCannotDo,
+	"must not do":        fieldCannotDo,
+	"key files":          fieldKeyFiles,
+}
+
+// noItems marks a list section that keeps only prose, so its paragraphs
+// are not read back as one item per line.
+const noItems = "<!-- no items -->"
+
+var (
+	updatedPattern = regexp.MustCompile(`<!--\s*updated:\s*(\S+)\s*-->`)
+	bulletPattern  = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+)
+
+// ContextDocument is a parsed context.md. It remembers the original text of
+// every section so that rewriting it only touches sections whose values
+// changed; the title, preamble, hand-written prose and unknown sections are
+// written back as they were.
+type ContextDocument struct {
+	head     string // title and preamble, up to the first "## " heading
+	sections []*contextSection
+}
+
+type contextSection struct {
+	heading string // the "## ..." line
+	body    string // raw text up to the next section
+	field   string // "" for unknown sections
+}
+
+// ParseContextDocument splits context.md into its "## " sections. Headings
+// inside fenced code blocks are ignored.
+func ParseContextDocument(data []byte) *ContextDocument {
+	doc := &ContextDocument{}
+	var current *contextSection
+	var buf strings.Builder
+	inFence := false
+
+	flush := func() {
+		if current == nil {
+			doc.head = buf.String()
+		} else {
+			current.body = buf.String()
+			doc.sections = append(doc.sections, current)
+		}
+		buf.Reset()
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "## ") {
+			flush()
+			heading := strings.TrimRight(line, "\r\n")
+			current = &contextSection{heading: heading, field: sectionAliases[normalizeHeading(heading[3:])]}
+			continue
+		}
+		buf.WriteString(line)
+	}
+	flush()
+	return doc
+}
+
+// NewContextDocument starts an empty context.md for dir.
+func NewContextDocument(dir string) *ContextDocument {
+	return &ContextDocument{head: fmt.Sprintf("# Context for %s\n\n", dir)}
+}
+
+// Context returns the directory context described by the document. When no
+// section maps to Purpose, the preamble prose stands in for it.
+func (d *ContextDocument) Context(dir string) *models.DirectoryContext {
+	ctx := &models.DirectoryContext{Path: dir}
+	for _, s := range d.sections {
+		switch s.field {
+		case "":
+		case fieldPurpose:
+			if purpose := strings.TrimSpace(s.body); purpose != "" {
+				if ctx.Purpose != "" {
+					ctx.Purpose += "\n\n"
+				}
+				ctx.Purpose += purpose
+			}
+		default:
+			items, _ := parseListBody(s.body)
+			list := listField(ctx, s.field)
+			*list = append(*list, items...)
+		}
+	}
+	if !d.hasField(fieldPurpose) {
+		ctx.Purpose = d.preamble()
+	}
+	if m := updatedPattern.FindStringSubmatch(d.head); m != nil {
+		if t, err := time.Parse(time.RFC3339, m[1]); err == nil {
+			ctx.Updated = t
+		}
+	}
+	return ctx
+}
+
+// SetContext writes ctx into the document. Sections whose value is
+// unchanged keep their original text; changed sections are re-rendered with
+// their prose paragraphs kept; new fields are appended as new sections. A
+// field's list is written to its first section, so later sections with the
+// same heading, and sections of an emptied field, keep only their prose
+// (marked with noItems) and are removed when they have none.
+func (d *ContextDocument) SetContext(ctx *models.DirectoryContext) {
+	current := d.Context(ctx.Path)
+
+	for _, spec := range contextSections {
+		if fieldEqual(current, ctx, spec.field) {
+			continue
+		}
+		if spec.field == fieldPurpose && !d.hasField(fieldPurpose) && ctx.Purpose == "" {
+			continue
+		}
+
+		var kept []*contextSection
+		written := false
+		for _, s := range d.sections {
+			if s.field != spec.field {
+				kept = append(kept, s)
+				continue
+			}
+			switch {
+			case !written && !fieldEmpty(ctx, spec.field):
+				s.body = renderBody(s.body, ctx, spec.field)
+				written = true
+			case spec.field == fieldPurpose:
+				continue // merged into the first section or removed
+			default:
+				// The items moved to the first section or were removed
+				if s.body = renderProse(s.body); s.body == "" {
+					continue
+				}
+			}
+			kept = append(kept, s)
+		}
+		d.sections = kept
+
+		if !written && !fieldEmpty(ctx, spec.field) {
+			d.appendSection(&contextSection{
+				heading: "## " + spec.heading,
+				body:    renderBody("", ctx, spec.field),
+				field:   spec.field,
+			})
+		}
+	}
+
+	d.setUpdated(ctx.Updated)
+}
+
+// Bytes renders the document.
+func (d *ContextDocument) Bytes() []byte {
+	var b strings.Builder
+	b.WriteString(d.head)
+	for _, s := range d.sections {
+		b.WriteString(s.heading + "\n")
+		b.WriteString(s.body)
+	}
+	return []byte(b.String())
+}
+
+func (d *ContextDocument) hasField(field string) bool {
+	for _, s := range d.sections {
+		if s.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// preamble returns the prose between the title and the first section.
+func (d *ContextDocument) preamble() string {
+	var lines []string
+	for _, line := range strings.Split(d.head, "\n") {
+		if strings.HasPrefix(line, "# ") || updatedPattern.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func (d *ContextDocument) appendSection(s *contextSection) {
+	if n := len(d.sections); n > 0 {
+		d.sections[n-1].body = ensureBlankLine(d.sections[n-1].body)
+	} else {
+		d.head = ensureBlankLine(d.head)
+	}
+	d.sections = append(d.sections, s)
+}
+
+// setUpdated records the timestamp as an HTML comment below the title.
+func (d *ContextDocument) setUpdated(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	comment := fmt.Sprintf("<!-- updated: %s -->", t.UTC().Format(time.RFC3339))
+	if updatedPattern.MatchString(d.head) {
+		d.head = updatedPattern.ReplaceAllLiteralString(d.head, comment)
+		return
+	}
+	lines := strings.SplitAfter(d.head, "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "# ") {
+		rest := strings.TrimLeft(strings.Join(lines[1:], ""), "\n")
+		d.head = ensureNewline(lines[0]) + "\n" + comment + "\n\n" + rest
+		return
+	}
+	d.head = comment + "\n\n" + d.head
+}
+
+// renderBody renders a field's value, keeping the prose paragraphs of the
+// old body and its trailing blank lines.
+func renderBody(old string, ctx *models.DirectoryContext, field string) string {
+	var b strings.Builder
+	if field == fieldPurpose {
+		b.WriteString(strings.TrimSpace(ctx.Purpose) + "\n")
+	} else {
+		_, prose := parseListBody(old)
+		for _, p := range prose {
+			b.WriteString(p + "\n\n")
+		}
+		for _, item := range *listField(ctx, field) {
+			b.WriteString("- " + strings.ReplaceAll(item, "\n", "\n  ") + "\n")
+		}
+	}
+
+	return keepTrailing(old, b.String())
+}
+
+// renderProse keeps only the prose paragraphs of a list section's body,
+// followed by the noItems marker, or returns "" when it has none.
+func renderProse(old string) string {
+	_, prose := parseListBody(old)
+	if len(prose) == 0 {
+		return ""
+	}
+	return keepTrailing(old, strings.Join(prose, "\n\n")+"\n\n"+noItems)
+}
+
+// keepTrailing ends body with as many newlines as old, and at least one.
+func keepTrailing(old, body string) string {
+	trailing := len(old) - len(strings.TrimRight(old, "\n"))
+	if trailing < 1 {
+		trailing = 1
+	}
+	return strings.TrimRight(body, "\n") + strings.Repeat("\n", trailing)
+}
+
+// parseListBody extracts list items from a section body. When the body has
+// bullets or the noItems marker, paragraphs without bullets are prose and
+// are returned separately; a body with neither is read as one item per
+// line. Indented lines continue the previous item.
+func parseListBody(body string) (items []string, prose []string) {
+	var paragraphs [][]string
+	hasBullets := false
+	for _, p := range splitParagraphs(body) {
+		if len(p) == 1 && strings.TrimSpace(p[0]) == noItems {
+			hasBullets = true
+			continue
+		}
+		for _, line := range p {
+			if bulletPattern.MatchString(line) {
+				hasBullets = true
+			}
+		}
+		paragraphs = append(paragraphs, p)
+	}
+
+	for _, p := range paragraphs {
+		bulleted := false
+		for _, line := range p {
+			if bulletPattern.MatchString(line) {
+				bulleted = true
+			}
+		}
+		if hasBullets && !bulleted {
+			prose = append(prose, strings.Join(p, "\n"))
+			continue
+		}
+		for _, line := range p {
+			switch {
+			case bulletPattern.MatchString(line):
+				items = append(items, strings.TrimSpace(bulletPattern.ReplaceAllString(line, "")))
+			case len(items) > 0 && startsIndented(line) && bulleted:
+				items[len(items)-1] += "\n" + strings.TrimSpace(line)
+			default:
+				items = append(items, strings.TrimSpace(line))
+			}
+		}
+	}
+	return items, prose
+}
+
+func splitParagraphs(body string) [][]string {
+	var paragraphs [][]string
+	var current []string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \r"))
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, current)
+	}
+	return paragraphs
+}
+
+func listField(ctx *models.DirectoryContext, field string) *[]string {
+	switch field {
+	case fieldResponsibilities:
+		return &ctx.Responsibilities
+	case fieldLocalArchitecture:
+		return &ctx.LocalArchitecture
+	case fieldDependencies:
+		return &ctx.Dependencies
+	case fieldMustDo:
+		return &ctx.MustDo
+	case fieldCannotDo:
+		return &ctx.CannotDo
+	case fieldKeyFiles:
+		return &ctx.KeyFiles
+	}
+	return new([]string)
+}
+
+func fieldEqual(a, b *models.DirectoryContext, field string) bool {
+	if field == fieldPurpose {
+		return strings.TrimSpace(a.Purpose) == strings.TrimSpace(b.Purpose)
+	}
+	x, y := *listField(a, field), *listField(b, field)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if strings.TrimSpace(x[i]) != strings.TrimSpace(y[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldEmpty(ctx *models.DirectoryContext, field string) bool {
+	if field == fieldPurpose {
+		return strings.TrimSpace(ctx.Purpose) == ""
+	}
+	return len(*listField(ctx, field)) == 0
+}
+
+// normalizeHeading lowercases a heading and strips leading emoji or
+// punctuation and a trailing colon, so "🎯 Must Do:" matches "must do".
+func normalizeHeading(h string) string {
+	h = strings.TrimLeftFunc(h, func(r rune) bool { return !unicode.IsLetter(r) })
+	h = strings.TrimRight(strings.TrimSpace(h), ":")
+	return strings.ToLower(strings.Join(strings.Fields(h), " "))
+}
+
+func startsIndented(line string) bool {
+	return strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+}
+
+func ensureNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
+
+func ensureBlankLine(s string) string {
+	if s == "" || strings.HasSuffix(s, "\n\n") {
+		return s
+	}
+	return ensureNewline(s) + "\n"
+}
@@ -0,0 +1,224 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const handWritten = `# API Module Context
+
+These handlers are the only public surface.
+
+## Purpose
+HTTP handlers for blog API endpoints
+
+## Responsibilities
+- Handle HTTP requests
+- Validate input
+
+## Dependencies
+Keep this list short.
+
+- src/models (for Post struct)
+- net/http
+
+## 🚦 MUST DO:
+- Validate all inputs
+- Return proper HTTP status codes,
+  never 200 for errors
+
+## YOU CANNOT DO
+- Direct database access
+
+## Related Specs
+- .agentic/spec/05-domain-model.md
+
+` + "```markdown\n## Not a heading\n```\n"
+
+func TestParseContextDocument_AllFields(t *testing.T) {
+	ctx := ParseContextDocument([]byte(handWritten)).Context("src/api")
+
+	assert.Equal(t, "src/api", ctx.Path)
+	assert.Equal(t, "HTTP handlers for blog API endpoints", ctx.Purpose)
+	assert.Equal(t, []string{"Handle HTTP requests", "Validate input"}, ctx.Responsibilities)
+	assert.Equal(t, []string{"src/models (for Post struct)", "net/http"}, ctx.Dependencies)
+	assert.Equal(t, []string{"Validate all inputs", "Return proper HTTP status codes,\nnever 200 for errors"}, ctx.MustDo)
+	assert.Equal(t, []string{"Direct database access"}, ctx.CannotDo)
+	assert.Empty(t, ctx.KeyFiles)
+	assert.Empty(t, ctx.LocalArchitecture)
+}
+
+func TestContextDocument_UnchangedRoundTrip(t *testing.T) {
+	doc := ParseContextDocument([]byte(handWritten))
+	doc.SetContext(doc.Context("src/api"))
+	assert.Equal(t, handWritten, string(doc.Bytes()))
+}
+
+func TestContextDocument_ChangedFieldKeepsProseAndUnknownSections(t *testing.T) {
+	doc := ParseContextDocument([]byte(handWritten))
+	ctx := doc.Context("src/api")
+	ctx.Dependencies = append(ctx.Dependencies, "encoding/json")
+	ctx.KeyFiles = []string{"handlers.go"}
+	ctx.CannotDo = nil
+	doc.SetContext(ctx)
+	out := string(doc.Bytes())
+
+	assert.Contains(t, out, "These handlers are the only public surface.")
+	assert.Contains(t, out, "## Dependencies\nKeep this list short.\n\n- src/models (for Post struct)\n- net/http\n- encoding/json\n\n## 🚦 MUST DO:")
+	assert.Contains(t, out, "## Related Specs\n- .agentic/spec/05-domain-model.md\n")
+	assert.Contains(t, out, "## Not a heading")
+	assert.NotContains(t, out, "YOU CANNOT DO")
+	assert.Contains(t, out, "\n## Key Files\n- handlers.go\n")
+
+	reparsed := ParseContextDocument([]byte(out)).Context("src/api")
+	assert.Equal(t, ctx.Dependencies, reparsed.Dependencies)
+	assert.Equal(t, ctx.MustDo, reparsed.MustDo)
+	assert.Equal(t, []string{"handlers.go"}, reparsed.KeyFiles)
+	assert.Empty(t, reparsed.CannotDo)
+}
+
+func TestContextDocument_DuplicateHeadingsKeepProse(t *testing.T) {
+	const doc = "# Context\n\n" +
+		"## Must Do\n- Validate input\n\n" +
+		"## Cannot Do\nNothing here is final.\n\n- Call the database\n\n" +
+		"## Must Do\nAsk the API team before adding items.\n\n- Log errors\n\n" +
+		"## Must Do\n- Use context\n"
+
+	parsed := ParseContextDocument([]byte(doc))
+	ctx := parsed.Context("src/api")
+	require.Equal(t, []string{"Validate input", "Log errors", "Use context"}, ctx.MustDo)
+
+	ctx.MustDo = append(ctx.MustDo, "Return JSON")
+	ctx.CannotDo = nil
+	parsed.SetContext(ctx)
+	out := string(parsed.Bytes())
+
+	assert.Equal(t, "# Context\n\n"+
+		"## Must Do\n- Validate input\n- Log errors\n- Use context\n- Return JSON\n\n"+
+		"## Cannot Do\nNothing here is final.\n\n<!-- no items -->\n\n"+
+		"## Must Do\nAsk the API team before adding items.\n\n<!-- no items -->\n\n", out)
+
+	reparsed := ParseContextDocument([]byte(out))
+	again := reparsed.Context("src/api")
+	assert.Equal(t, ctx.MustDo, again.MustDo)
+	assert.Empty(t, again.CannotDo)
+
+	// Writing the same context again changes nothing
+	reparsed.SetContext(again)
+	assert.Equal(t, out, string(reparsed.Bytes()))
+}
+
+func TestContextDocument_NewFileRoundTrip(t *testing.T) {
+	updated := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	ctx := &models.DirectoryContext{
+		Path:              "internal/auth",
+		Purpose:           "Authentication.\n\nIssues and verifies JWTs.",
+		Responsibilities:  []string{"Token validation"},
+		LocalArchitecture: []string{"Stateless services"},
+		Dependencies:      []string{"pkg/jwt"},
+		MustDo:            []string{"Log auth failures"},
+		CannotDo:          []string{"Store secrets in code"},
+		KeyFiles:          []string{"jwt.go", "middleware.go"},
+		Updated:           updated,
+	}
+
+	doc := NewContextDocument(ctx.Path)
+	doc.SetContext(ctx)
+	out := doc.Bytes()
+
+	assert.Contains(t, string(out), "# Context for internal/auth\n\n<!-- updated: 2026-05-04T10:00:00Z -->\n\n## Purpose\n")
+	assert.Equal(t, ctx, ParseContextDocument(out).Context("internal/auth"))
+}
+
+func TestParseContextDocument_LegacyFormats(t *testing.T) {
+	// Written by the old SaveContext, which joined lists without a leading bullet
+	legacy := "# Context for src/foo\n\n## Purpose\nContains 1 source files.\n\n## Responsibilities\nExported symbols: Bar\n\n## Dependencies\nfmt\n- Hello\n"
+	ctx := ParseContextDocument([]byte(legacy)).Context("src/foo")
+	assert.Equal(t, "Contains 1 source files.", ctx.Purpose)
+	assert.Equal(t, []string{"Exported symbols: Bar"}, ctx.Responsibilities)
+	assert.Equal(t, []string{"fmt", "Hello"}, ctx.Dependencies)
+
+	// No sections at all: the prose is the purpose
+	ctx = ParseContextDocument([]byte("# Context\n\nGenerated context for src/x\n")).Context("src/x")
+	assert.Equal(t, "Generated context for src/x", ctx.Purpose)
+}
+
+func TestMergeContext(t *testing.T) {
+	existing := &models.DirectoryContext{
+		Path:             "internal/auth",
+		Purpose:          "Hand-written purpose.",
+		Responsibilities: []string{"Owns sessions", "Exported symbols: Old", "Types: Stale"},
+		Dependencies:     []string{"fmt", "github.com/old/lib", "internal/db (for user lookups)"},
+		MustDo:           []string{"Log failures"},
+		KeyFiles:         []string{"removed.go", "jwt.go", "README.md", "`session.go` — session store"},
+	}
+	generated := &models.DirectoryContext{
+		Path:             "internal/auth",
+		Purpose:          "Contains 3 source files. Implements functionality related to auth.",
+		Responsibilities: []string{"Package: auth", "Functions: Login, Logout"},
+		Dependencies:     []string{"fmt", "net/http"},
+		KeyFiles:         []string{"jwt.go", "login.go"},
+	}
+
+	merged := MergeContext(existing, generated)
+
+	assert.Equal(t, "Hand-written purpose.", merged.Purpose)
+	assert.Equal(t, []string{"Owns sessions", "Package: auth", "Functions: Login, Logout"}, merged.Responsibilities)
+	assert.Equal(t, []string{"fmt", "internal/db (for user lookups)", "net/http"}, merged.Dependencies)
+	assert.Equal(t, []string{"jwt.go", "README.md", "`session.go` — session store", "login.go"}, merged.KeyFiles)
+	assert.Equal(t, []string{"Log failures"}, merged.MustDo)
+	assert.False(t, merged.Updated.IsZero())
+
+	// The generated placeholder purpose is refreshed
+	existing.Purpose = "Contains 2 source files. Implements functionality related to auth."
+	assert.Equal(t, generated.Purpose, MergeContext(existing, generated).Purpose)
+}
+
+func TestDirectoryContextManager_UpdateKeepsHumanEdits(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.go"), []byte("package auth\n\nimport \"net/http\"\n\nfunc Login() {}\n"), 0644))
+
+	dcm := NewDirectoryContextManager(dir)
+
+	// First update creates the file from generated facts
+	generated, err := GenerateContext(dir)
+	require.NoError(t, err)
+	_, err = dcm.UpdateContext(dir, generated)
+	require.NoError(t, err)
+
+	// A human edits it
+	path := filepath.Join(dir, "context.md")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	doc := ParseContextDocument(data)
+	ctx := doc.Context(dir)
+	ctx.Purpose = "Login and session handling."
+	ctx.MustDo = []string{"Hash passwords"}
+	doc.SetContext(ctx)
+	edited := string(doc.Bytes()) + "\n## Notes\nAsk the security team before changing token TTLs.\n"
+	require.NoError(t, os.WriteFile(path, []byte(edited), 0644))
+
+	// The code changes and context is updated again
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logout.go"), []byte("package auth\n\nfunc Logout() {}\n"), 0644))
+	generated, err = GenerateContext(dir)
+	require.NoError(t, err)
+	_, err = dcm.UpdateContext(dir, generated)
+	require.NoError(t, err)
+
+	loaded, err := dcm.LoadContext(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "Login and session handling.", loaded.Purpose)
+	assert.Equal(t, []string{"Hash passwords"}, loaded.MustDo)
+	assert.Equal(t, []string{"auth.go", "logout.go"}, loaded.KeyFiles)
+	assert.Equal(t, []string{"Package: auth", "Functions: Login, Logout"}, loaded.Responsibilities)
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "## Notes\nAsk the security team before changing token TTLs.\n")
+}
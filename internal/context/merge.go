@@ -0,0 +1,103 @@
+package context
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// exportedSymbolsPrefix marks the single responsibility line generated by
+// earlier versions; it is still replaced on update.
+const exportedSymbolsPrefix = "Exported symbols: "
+
+// generatedPurposePattern matches the placeholder purpose written by the
+// generator, which humans are expected to replace.
+var generatedPurposePattern = regexp.MustCompile(`^Contains \d+ source files\. Implements functionality related to .*\.$`)
+
+// MergeContext folds regenerated facts into a context that may have been
+// edited by hand:
+//
+//   - Purpose is replaced only while it is empty or still the placeholder.
+//   - Generated responsibility lines ("Types: ...", "Functions: ...") are
+//     refreshed; other responsibilities are kept.
+//   - Dependencies and key files that no longer exist are dropped unless
+//     they carry a note ("db (for migrations)"); new ones are appended.
+//   - Local architecture, must-do and cannot-do rules are never generated
+//     and are kept as written.
+func MergeContext(existing, generated *models.DirectoryContext) *models.DirectoryContext {
+	merged := *existing
+	merged.Path = generated.Path
+	merged.Updated = time.Now()
+
+	if strings.TrimSpace(existing.Purpose) == "" || generatedPurposePattern.MatchString(strings.TrimSpace(existing.Purpose)) {
+		merged.Purpose = generated.Purpose
+	}
+
+	merged.Responsibilities = mergeResponsibilities(existing.Responsibilities, generated.Responsibilities)
+	merged.Dependencies = mergeFacts(existing.Dependencies, generated.Dependencies, func(string) bool { return true })
+	merged.KeyFiles = mergeFacts(existing.KeyFiles, generated.KeyFiles, func(name string) bool {
+		return sourceExtensions[filepath.Ext(name)]
+	})
+	return &merged
+}
+
+// mergeResponsibilities swaps the generated lines in place of the first
+// old generated line, or appends them when the existing list has none.
+func mergeResponsibilities(existing, generated []string) []string {
+	result := make([]string, 0, len(existing)+len(generated))
+	replaced := false
+	for _, r := range existing {
+		if !isGeneratedResponsibility(r) {
+			result = append(result, r)
+			continue
+		}
+		if !replaced {
+			result = append(result, generated...)
+			replaced = true
+		}
+	}
+	if !replaced {
+		result = append(result, generated...)
+	}
+	return result
+}
+
+// mergeFacts keeps existing entries that are still generated, that carry a
+// human note, or that the generator does not track (tracked reports false);
+// stale bare entries are dropped and new generated entries appended.
+func mergeFacts(existing, generated []string, tracked func(string) bool) []string {
+	current := make(map[string]bool, len(generated))
+	for _, g := range generated {
+		current[g] = true
+	}
+
+	result := make([]string, 0, len(existing)+len(generated))
+	mentioned := make(map[string]bool)
+	for _, item := range existing {
+		name := leadingToken(item)
+		mentioned[name] = true
+		annotated := name != strings.TrimSpace(item)
+		if current[name] || annotated || !tracked(name) {
+			result = append(result, item)
+		}
+	}
+	for _, g := range generated {
+		if !mentioned[g] {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+// leadingToken returns the first word of an item, without backticks, so
+// "`jwt.go` — signs tokens" names jwt.go.
+func leadingToken(item string) string {
+	fields := strings.Fields(item)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.Trim(fields[0], "`*")
+}
@@ -0,0 +1,352 @@
+package encoding
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/javierbenavides/agentic-agent/internal/specs"
+	"github.com/javierbenavides/agentic-agent/internal/token"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// Relevance ranks a bundle section against the task being worked on.
+type Relevance string
+
+const (
+	RelevanceRequired   Relevance = "required"   // task, global context and project docs
+	RelevanceScope      Relevance = "scope"      // inside the task scope or referenced by the task
+	RelevanceDependency Relevance = "dependency" // used by the scope or by prerequisite tasks
+	RelevanceOther      Relevance = "other"
+
+	// relevanceNone marks a directory unrelated to a scoped task; it is not
+	// loaded into the bundle at all.
+	relevanceNone Relevance = "none"
+)
+
+var relevanceRank = map[Relevance]int{
+	RelevanceRequired:   0,
+	RelevanceScope:      1,
+	RelevanceDependency: 2,
+	RelevanceOther:      3,
+}
+
+// SectionStatus records what budgeting did to a section.
+type SectionStatus string
+
+const (
+	SectionIncluded   SectionStatus = "included"
+	SectionSummarized SectionStatus = "summarized"
+	SectionTruncated  SectionStatus = "truncated"
+	SectionDropped    SectionStatus = "dropped"
+)
+
+const (
+	sectionDirectory = "directory"
+	sectionSpec      = "spec"
+	sectionSkills    = "skills"
+	sectionRolling   = "rolling"
+
+	// A section reduced below this many tokens is dropped instead.
+	minSectionTokens = 32
+	truncatedMarker  = "\n... [truncated]"
+)
+
+// BundleManifest reports how a bundle was fitted into the agent's budget.
+type BundleManifest struct {
+	Budget   int             `yaml:"budget" json:"budget"` // 0 means unlimited
+	Used     int             `yaml:"used" json:"used"`
+	Sections []ManifestEntry `yaml:"sections" json:"sections"`
+}
+
+// ManifestEntry describes one section of the bundle.
+type ManifestEntry struct {
+	Kind      string        `yaml:"kind" json:"kind"`
+	Name      string        `yaml:"name" json:"name"`
+	Relevance Relevance     `yaml:"relevance" json:"relevance"`
+	Status    SectionStatus `yaml:"status" json:"status"`
+	Tokens    int           `yaml:"tokens" json:"tokens"`
+	Original  int           `yaml:"original_tokens" json:"original_tokens"`
+}
+
+// Dropped returns the sections left out of the bundle.
+func (m *BundleManifest) Dropped() []ManifestEntry {
+	return m.withStatus(SectionDropped)
+}
+
+// Reduced returns the sections that were summarized or truncated.
+func (m *BundleManifest) Reduced() []ManifestEntry {
+	return append(m.withStatus(SectionSummarized), m.withStatus(SectionTruncated)...)
+}
+
+func (m *BundleManifest) withStatus(status SectionStatus) []ManifestEntry {
+	var out []ManifestEntry
+	for _, e := range m.Sections {
+		if e.Status == status {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Summary renders a one-line description of the budget outcome.
+func (m *BundleManifest) Summary() string {
+	budget := "unlimited"
+	if m.Budget > 0 {
+		budget = fmt.Sprintf("%d", m.Budget)
+	}
+	return fmt.Sprintf("%d/%s tokens, %d sections, %d reduced, %d dropped",
+		m.Used, budget, len(m.Sections), len(m.Reduced()), len(m.Dropped()))
+}
+
+// bundleSection is a rankable part of the bundle. Exactly one of dir, spec
+// or text is set, depending on kind.
+type bundleSection struct {
+	kind      string
+	name      string
+	relevance Relevance
+	dir       *models.DirectoryContext
+	spec      *specs.ResolvedSpec
+	text      string
+}
+
+// tokens measures what the section adds to the encoded bundle.
+func (s bundleSection) tokens(m *bundleMeter) int {
+	return m.measure(s.apply)
+}
+
+// shrink returns a reduced copy of the section that fits in limit tokens.
+func (s bundleSection) shrink(limit int, m *bundleMeter) (bundleSection, SectionStatus, bool) {
+	if limit < minSectionTokens {
+		return s, SectionDropped, false
+	}
+	switch s.kind {
+	case sectionDirectory:
+		// Keep the purpose and rules; detail fields go first
+		summary := &models.DirectoryContext{
+			Path:     s.dir.Path,
+			Purpose:  s.dir.Purpose,
+			MustDo:   s.dir.MustDo,
+			CannotDo: s.dir.CannotDo,
+			Updated:  s.dir.Updated,
+		}
+		s.dir = summary
+		if s.tokens(m) > limit {
+			purpose, ok := fitText(summary.Purpose, limit, func(text string) int {
+				summary.Purpose = text
+				return s.tokens(m)
+			})
+			if !ok {
+				return s, SectionDropped, false
+			}
+			summary.Purpose = purpose
+		}
+		return s, SectionSummarized, true
+	case sectionSpec:
+		spec := *s.spec
+		s.spec = &spec
+		content, ok := fitText(spec.Content, limit, func(text string) int {
+			spec.Content = text
+			return s.tokens(m)
+		})
+		if !ok {
+			return s, SectionDropped, false
+		}
+		spec.Content = content
+		return s, SectionTruncated, true
+	default:
+		text, ok := fitText(s.text, limit, func(text string) int {
+			s.text = text
+			return s.tokens(m)
+		})
+		if !ok {
+			return s, SectionDropped, false
+		}
+		s.text = text
+		return s, SectionTruncated, true
+	}
+}
+
+// fitText truncates text until measure reports at most limit tokens. The
+// measure includes whatever surrounds the text, so the cut is refined a few
+// times rather than computed once.
+func fitText(text string, limit int, measure func(string) int) (string, bool) {
+	target := limit
+	for i := 0; i < 8 && target >= minSectionTokens; i++ {
+		cut := truncateText(text, target)
+		n := measure(cut)
+		if n <= limit {
+			return cut, true
+		}
+		// Scale by the observed ratio, always making progress
+		next := target * limit / n
+		if next >= target {
+			next = target - 1
+		}
+		target = next
+	}
+	return "", false
+}
+
+func (s bundleSection) apply(b *ContextBundle) {
+	switch s.kind {
+	case sectionDirectory:
+		b.Directories = append(b.Directories, s.dir)
+	case sectionSpec:
+		b.Specs = append(b.Specs, s.spec)
+	case sectionSkills:
+		b.SkillInstructions = s.text
+	case sectionRolling:
+		b.Rolling = s.text
+	}
+}
+
+// fitToBudget adds sections to bundle in relevance order until budget tokens
+// are used, shrinking the first section that does not fit and dropping what
+// is left over. Tokens are counted as the bundle will be encoded in format.
+// The required parts already in bundle always stay. A budget of 0 includes
+// everything.
+func fitToBudget(bundle *ContextBundle, sections []bundleSection, budget int, format string) (*BundleManifest, error) {
+	m, err := newBundleMeter(format)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BundleManifest{Budget: budget}
+	for _, part := range requiredParts(bundle, m) {
+		manifest.Sections = append(manifest.Sections, part)
+		manifest.Used += part.Tokens
+	}
+
+	sort.SliceStable(sections, func(i, j int) bool {
+		return relevanceRank[sections[i].relevance] < relevanceRank[sections[j].relevance]
+	})
+
+	for _, s := range sections {
+		entry := ManifestEntry{Kind: s.kind, Name: s.name, Relevance: s.relevance, Original: s.tokens(m)}
+		entry.Status, entry.Tokens = SectionIncluded, entry.Original
+
+		if budget > 0 && manifest.Used+entry.Original > budget {
+			reduced, status, ok := s.shrink(budget-manifest.Used, m)
+			entry.Status = status
+			if ok {
+				s = reduced
+				entry.Tokens = s.tokens(m)
+			}
+		}
+
+		if entry.Status == SectionDropped {
+			entry.Tokens = 0
+		} else {
+			s.apply(bundle)
+			manifest.Used += entry.Tokens
+		}
+		manifest.Sections = append(manifest.Sections, entry)
+	}
+
+	bundle.Manifest = manifest
+	return manifest, nil
+}
+
+// requiredParts lists the sections every bundle carries.
+func requiredParts(b *ContextBundle, m *bundleMeter) []ManifestEntry {
+	parts := []ManifestEntry{
+		{Kind: "task", Name: b.Task.ID, Original: m.measure(func(p *ContextBundle) { p.Task = b.Task })},
+		{Kind: "global", Name: "global context", Original: m.measure(func(p *ContextBundle) { p.Global = b.Global })},
+	}
+	if b.TechStack != "" {
+		parts = append(parts, ManifestEntry{Kind: "tech_stack", Name: "tech-stack.md", Original: m.measure(func(p *ContextBundle) { p.TechStack = b.TechStack })})
+	}
+	if b.Workflow != "" {
+		parts = append(parts, ManifestEntry{Kind: "workflow", Name: "workflow-preferences.md", Original: m.measure(func(p *ContextBundle) { p.Workflow = b.Workflow })})
+	}
+	for i := range parts {
+		parts[i].Relevance = RelevanceRequired
+		parts[i].Status = SectionIncluded
+		parts[i].Tokens = parts[i].Original
+	}
+	return parts
+}
+
+// rankDirectory places a directory context relative to the task scope and
+// the scopes of its prerequisite tasks. Directories imported by a scope
+// directory also count as dependencies. For a task without a scope every
+// directory is other; otherwise unrelated directories rank none.
+func rankDirectory(dir string, scope, depScope, imports []string) Relevance {
+	for _, s := range scope {
+		if pathsOverlap(dir, s) {
+			return RelevanceScope
+		}
+	}
+	for _, s := range depScope {
+		if pathsOverlap(dir, s) {
+			return RelevanceDependency
+		}
+	}
+	clean := filepath.ToSlash(filepath.Clean(dir))
+	for _, imp := range imports {
+		if imp == clean || strings.HasSuffix(imp, "/"+clean) {
+			return RelevanceDependency
+		}
+	}
+	if len(scope) == 0 {
+		return RelevanceOther
+	}
+	return relevanceNone
+}
+
+// pathsOverlap reports whether one path equals or contains the other.
+func pathsOverlap(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if a == b || a == "." || b == "." {
+		return true
+	}
+	sep := string(filepath.Separator)
+	return strings.HasPrefix(a, b+sep) || strings.HasPrefix(b, a+sep)
+}
+
+// truncateText cuts text to about limit tokens, preferring a line boundary.
+func truncateText(text string, limit int) string {
+	keep := limit*4 - len(truncatedMarker)
+	if keep <= 0 {
+		return ""
+	}
+	if len(text) <= keep {
+		return text
+	}
+	cut := text[:keep]
+	if i := strings.LastIndexByte(cut, '\n'); i > keep/2 {
+		cut = cut[:i]
+	}
+	return cut + truncatedMarker
+}
+
+// bundleMeter counts the tokens a part adds to a bundle encoded in format,
+// so the budget matches what the agent is sent.
+type bundleMeter struct {
+	format string
+	empty  int // tokens of a bundle with nothing in it
+}
+
+func newBundleMeter(format string) (*bundleMeter, error) {
+	data, err := EncodeAs(&ContextBundle{}, format)
+	if err != nil {
+		return nil, err
+	}
+	return &bundleMeter{format: format, empty: token.CountTokens(string(data))}, nil
+}
+
+// measure encodes a bundle holding only what fill sets and returns its
+// tokens beyond those of an empty bundle.
+func (m *bundleMeter) measure(fill func(*ContextBundle)) int {
+	b := &ContextBundle{}
+	fill(b)
+	data, err := EncodeAs(b, m.format)
+	if err != nil {
+		return 0
+	}
+	if n := token.CountTokens(string(data)) - m.empty; n > 0 {
+		return n
+	}
+	return 0
+}
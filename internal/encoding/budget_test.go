@@ -0,0 +1,197 @@
+package encoding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/internal/specs"
+	"github.com/javierbenavides/agentic-agent/internal/token"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dirSection(path string, relevance Relevance, purposeSize int) bundleSection {
+	return bundleSection{
+		kind:      sectionDirectory,
+		name:      path,
+		relevance: relevance,
+		dir: &models.DirectoryContext{
+			Path:     path,
+			Purpose:  strings.Repeat("purpose line\n", purposeSize/13+1),
+			KeyFiles: []string{"a.go", "b.go"},
+		},
+	}
+}
+
+func testMeter(t *testing.T) *bundleMeter {
+	t.Helper()
+	m, err := newBundleMeter(FormatTOON)
+	require.NoError(t, err)
+	return m
+}
+
+func newTestBundle() *ContextBundle {
+	return &ContextBundle{
+		Task:   &models.Task{ID: "TASK-1", Title: "Budget", Scope: []string{"internal/auth"}},
+		Global: &models.GlobalContext{ProjectName: "demo"},
+	}
+}
+
+func TestFitToBudget_Unlimited(t *testing.T) {
+	bundle := newTestBundle()
+	sections := []bundleSection{
+		dirSection("internal/other", RelevanceOther, 4000),
+		dirSection("internal/auth", RelevanceScope, 4000),
+		{kind: sectionRolling, name: "rolling summary", relevance: RelevanceOther, text: "recent work"},
+	}
+
+	manifest, err := fitToBudget(bundle, sections, 0, FormatTOON)
+	require.NoError(t, err)
+
+	assert.Len(t, bundle.Directories, 2)
+	assert.Equal(t, "recent work", bundle.Rolling)
+	assert.Empty(t, manifest.Dropped())
+	assert.Empty(t, manifest.Reduced())
+	assert.Same(t, manifest, bundle.Manifest)
+	assert.Contains(t, manifest.Summary(), "unlimited")
+}
+
+func TestFitToBudget_RanksScopeBeforeOthers(t *testing.T) {
+	bundle := newTestBundle()
+	required := 0
+	for _, p := range requiredParts(bundle, testMeter(t)) {
+		required += p.Tokens
+	}
+
+	scope := dirSection("internal/auth", RelevanceScope, 800)
+	dep := dirSection("internal/db", RelevanceDependency, 800)
+	other := dirSection("internal/ui", RelevanceOther, 800)
+	budget := required + scope.tokens(testMeter(t)) + dep.tokens(testMeter(t))/2
+
+	// Passed in the wrong order on purpose
+	manifest, err := fitToBudget(bundle, []bundleSection{other, dep, scope}, budget, FormatTOON)
+	require.NoError(t, err)
+
+	require.Len(t, bundle.Directories, 2)
+	assert.Equal(t, "internal/auth", bundle.Directories[0].Path)
+	assert.Equal(t, "internal/db", bundle.Directories[1].Path)
+	assert.Empty(t, bundle.Directories[1].KeyFiles, "summarized context keeps only purpose and rules")
+	assert.Contains(t, bundle.Directories[1].Purpose, "[truncated]")
+	assert.LessOrEqual(t, manifest.Used, budget)
+
+	dropped := manifest.Dropped()
+	require.Len(t, dropped, 1)
+	assert.Equal(t, "internal/ui", dropped[0].Name)
+	assert.Equal(t, RelevanceOther, dropped[0].Relevance)
+	assert.Equal(t, 0, dropped[0].Tokens)
+	assert.Positive(t, dropped[0].Original)
+
+	reduced := manifest.Reduced()
+	require.Len(t, reduced, 1)
+	assert.Equal(t, SectionSummarized, reduced[0].Status)
+}
+
+func TestFitToBudget_TruncatesText(t *testing.T) {
+	bundle := newTestBundle()
+	required := 0
+	for _, p := range requiredParts(bundle, testMeter(t)) {
+		required += p.Tokens
+	}
+
+	spec := &specs.ResolvedSpec{Ref: "auth.md", Found: true, Content: strings.Repeat("spec body\n", 200)}
+	sections := []bundleSection{
+		{kind: sectionSpec, name: "auth.md", relevance: RelevanceScope, spec: spec},
+		{kind: sectionRolling, name: "rolling summary", relevance: RelevanceOther, text: strings.Repeat("done\n", 100)},
+	}
+	manifest, err := fitToBudget(bundle, sections, required+200, FormatTOON)
+	require.NoError(t, err)
+
+	require.Len(t, bundle.Specs, 1)
+	assert.Contains(t, bundle.Specs[0].Content, "[truncated]")
+	assert.Equal(t, strings.Repeat("spec body\n", 200), spec.Content, "original spec is not modified")
+	assert.LessOrEqual(t, manifest.Used, required+200)
+
+	// The rolling summary only gets what the spec left over, if anything
+	rolling := manifest.Sections[len(manifest.Sections)-1]
+	assert.Equal(t, sectionRolling, rolling.Kind)
+	assert.NotEqual(t, SectionIncluded, rolling.Status)
+}
+
+func TestFitToBudget_DropsWhenNothingLeft(t *testing.T) {
+	bundle := newTestBundle()
+	required := 0
+	for _, p := range requiredParts(bundle, testMeter(t)) {
+		required += p.Tokens
+	}
+
+	sections := []bundleSection{
+		{kind: sectionRolling, name: "rolling summary", relevance: RelevanceOther, text: strings.Repeat("done\n", 100)},
+	}
+	manifest, err := fitToBudget(bundle, sections, required+minSectionTokens-1, FormatTOON)
+	require.NoError(t, err)
+
+	assert.Empty(t, bundle.Rolling)
+	dropped := manifest.Dropped()
+	require.Len(t, dropped, 1)
+	assert.Equal(t, sectionRolling, dropped[0].Kind)
+}
+
+func TestFitToBudget_RequiredAlwaysIncluded(t *testing.T) {
+	bundle := newTestBundle()
+	bundle.TechStack = strings.Repeat("go ", 100)
+
+	manifest, err := fitToBudget(bundle, []bundleSection{dirSection("internal/auth", RelevanceScope, 400)}, 10, FormatTOON)
+	require.NoError(t, err)
+
+	assert.Equal(t, strings.Repeat("go ", 100), bundle.TechStack)
+	assert.Empty(t, bundle.Directories)
+	assert.Len(t, manifest.Dropped(), 1)
+	assert.Greater(t, manifest.Used, manifest.Budget)
+}
+
+func TestFitToBudget_CountsInOutputFormat(t *testing.T) {
+	section := dirSection("internal/auth", RelevanceScope, 400)
+
+	counted := make(map[string]int)
+	for _, format := range Formats {
+		bundle := newTestBundle()
+		manifest, err := fitToBudget(bundle, []bundleSection{section}, 0, format)
+		require.NoError(t, err)
+		counted[format] = manifest.Sections[len(manifest.Sections)-1].Tokens
+
+		// The sections add up to the encoded bundle without the manifest
+		m, err := newBundleMeter(format)
+		require.NoError(t, err)
+		bundle.Manifest = nil
+		data, err := EncodeAs(bundle, format)
+		require.NoError(t, err)
+		assert.InDelta(t, token.CountTokens(string(data)), m.empty+manifest.Used, float64(manifest.Used)/20, format)
+	}
+	assert.Less(t, counted[FormatTOON], counted[FormatJSON])
+
+	_, err := fitToBudget(newTestBundle(), nil, 0, "xml")
+	assert.Error(t, err)
+}
+
+func TestRankDirectory(t *testing.T) {
+	scope := []string{"internal/auth"}
+	depScope := []string{"./internal/db/"}
+	imports := []string{"github.com/acme/app/pkg/jwt"}
+
+	assert.Equal(t, RelevanceScope, rankDirectory("internal/auth", scope, depScope, imports))
+	assert.Equal(t, RelevanceScope, rankDirectory("internal/auth/middleware", scope, depScope, imports))
+	assert.Equal(t, RelevanceDependency, rankDirectory("internal/db", scope, depScope, imports))
+	assert.Equal(t, RelevanceDependency, rankDirectory("pkg/jwt", scope, depScope, imports))
+	assert.Equal(t, relevanceNone, rankDirectory("internal/authz", scope, depScope, imports))
+	assert.Equal(t, RelevanceOther, rankDirectory("cmd/app", nil, nil, nil))
+}
+
+func TestTruncateText(t *testing.T) {
+	assert.Equal(t, "short", truncateText("short", 100))
+
+	long := strings.Repeat("0123456789\n", 100)
+	cut := truncateText(long, 50)
+	assert.True(t, strings.HasSuffix(cut, truncatedMarker))
+	assert.LessOrEqual(t, len(cut), 200)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/javierbenavides/agentic-agent/internal/config"
 	"github.com/javierbenavides/agentic-agent/internal/context"
 	"github.com/javierbenavides/agentic-agent/internal/skills"
 	"github.com/javierbenavides/agentic-agent/internal/specs"
@@ -25,9 +26,26 @@ type ContextBundle struct {
 	Specs             []*specs.ResolvedSpec     `yaml:"specs,omitempty" json:"specs,omitempty"`
 	SkillInstructions string                    `yaml:"skill_instructions,omitempty" json:"skill_instructions,omitempty"`
 	BuiltAt           time.Time                 `yaml:"built_at" json:"built_at"`
+	Manifest          *BundleManifest           `yaml:"manifest,omitempty" json:"manifest,omitempty"`
 }
 
+// CreateContextBundle builds the bundle for taskID and encodes it in format
+// (toon, yaml, json or markdown; empty means toon).
 func CreateContextBundle(taskID string, format string, cfg *models.Config) ([]byte, error) {
+	bundle, err := BuildContextBundle(taskID, format, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeAs(bundle, format)
+}
+
+// BuildContextBundle assembles the context for taskID within the active
+// agent's max_tokens, counted as the bundle will be encoded in format.
+// Directory contexts, specs, skills and the rolling summary are ranked by
+// relevance to the task (scope, then dependencies, then everything else);
+// lower-ranked sections are summarized, truncated or dropped to fit, and the
+// outcome is recorded in the bundle manifest.
+func BuildContextBundle(taskID string, format string, cfg *models.Config) (*ContextBundle, error) {
 	// 1. Load Task
 	tm := tasks.NewTaskManager(".agentic/tasks")
 	// Search in all lists
@@ -75,55 +93,109 @@ func CreateContextBundle(taskID string, format string, cfg *models.Config) ([]by
 		rolling = ""
 	}
 
-	// 4. Load Directory Contexts (simplified: load all tracked dirs?)
-	// Or just from task scope?
-	// For MVP, lets looking at task scope if we had it, or just root.
-	// We'll scan root for now.
-	dcm := context.NewDirectoryContextManager(".")
-	dirs, _ := dcm.FindContextDirs(".")
-	var dirContexts []*models.DirectoryContext
-	for _, d := range dirs {
-		ctx, err := dcm.LoadContext(d)
-		if err == nil {
-			dirContexts = append(dirContexts, ctx)
-		}
-	}
-
 	// Load supplementary context files (optional, non-blocking)
 	techStack, _ := os.ReadFile(".agentic/context/tech-stack.md")
 	workflow, _ := os.ReadFile(".agentic/context/workflow-preferences.md")
 
 	bundle := &ContextBundle{
-		Task:        task,
-		Global:      global,
-		Rolling:     rolling,
-		TechStack:   string(techStack),
-		Workflow:    string(workflow),
-		Directories: dirContexts,
-		BuiltAt:     time.Now(),
+		Task:      task,
+		Global:    global,
+		TechStack: string(techStack),
+		Workflow:  string(workflow),
+		BuiltAt:   time.Now(),
 	}
 
-	// 5. Resolve specs if task has SpecRefs
+	// 4. Collect ranked sections: specs and targeted skills belong to the
+	// task itself, directories are ranked against its scope
+	var sections []bundleSection
+
 	if len(task.SpecRefs) > 0 {
 		resolver := specs.NewResolver(cfg)
-		bundle.Specs = resolver.ResolveAll(task.SpecRefs)
-
-		// Warn on stderr for unresolved specs (non-blocking)
-		for _, s := range bundle.Specs {
+		for _, s := range resolver.ResolveAll(task.SpecRefs) {
+			// Warn on stderr for unresolved specs (non-blocking)
 			if !s.Found {
 				fmt.Fprintf(os.Stderr, "Warning: spec %q could not be resolved: %s\n", s.Ref, s.Error)
 			}
+			sections = append(sections, bundleSection{kind: sectionSpec, name: s.Ref, relevance: RelevanceScope, spec: s})
 		}
 	}
 
-	// 5.5 Load skill instructions for the active agent
 	if cfg.ActiveAgent != "" {
-		bundle.SkillInstructions = loadSkillInstructions(cfg.ActiveAgent, task.SkillRefs)
+		if instructions := loadSkillInstructions(cfg.ActiveAgent, task.SkillRefs); instructions != "" {
+			relevance := RelevanceOther
+			if len(task.SkillRefs) > 0 {
+				relevance = RelevanceScope
+			}
+			sections = append(sections, bundleSection{kind: sectionSkills, name: cfg.ActiveAgent, relevance: relevance, text: instructions})
+		}
+	}
+
+	if rolling != "" {
+		sections = append(sections, bundleSection{kind: sectionRolling, name: "rolling summary", relevance: RelevanceOther, text: rolling})
 	}
 
-	// 6. Encode
-	encoder := NewToonEncoder()
-	return encoder.Encode(bundle)
+	sections = append(sections, directorySections(tm, task)...)
+
+	// 5. Fit everything into the agent's token budget
+	budget := 0
+	if cfg.ActiveAgent != "" {
+		budget = config.GetAgentConfig(cfg, cfg.ActiveAgent).MaxTokens
+	}
+	if _, err := fitToBudget(bundle, sections, budget, format); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// directorySections ranks every directory with a context against the task
+// scope, the scopes of its prerequisite tasks and the imports of its scope
+// directories, and loads those related to the task.
+func directorySections(tm *tasks.TaskManager, task *models.Task) []bundleSection {
+	var depScope []string
+	for _, id := range task.DependsOn {
+		if dep, _, err := tm.FindTask(id); err == nil && dep != nil {
+			depScope = append(depScope, dep.Scope...)
+		}
+	}
+
+	dcm := context.NewDirectoryContextManager(".")
+	dirs, _ := dcm.FindContextDirs(".")
+
+	// Scope directories come first: their imports rank the rest
+	loaded := make(map[string]*models.DirectoryContext)
+	var imports []string
+	for _, d := range dirs {
+		if rankDirectory(d, task.Scope, nil, nil) != RelevanceScope {
+			continue
+		}
+		if ctx, err := dcm.LoadContext(d); err == nil {
+			loaded[d] = ctx
+			imports = append(imports, ctx.Dependencies...)
+		}
+	}
+
+	var sections []bundleSection
+	for _, d := range dirs {
+		relevance := rankDirectory(d, task.Scope, depScope, imports)
+		if relevance == relevanceNone {
+			continue
+		}
+		ctx, ok := loaded[d]
+		if !ok {
+			var err error
+			if ctx, err = dcm.LoadContext(d); err != nil {
+				continue
+			}
+		}
+		sections = append(sections, bundleSection{
+			kind:      sectionDirectory,
+			name:      ctx.Path,
+			relevance: relevance,
+			dir:       ctx,
+		})
+	}
+	return sections
 }
 
 // loadSkillInstructions gathers agent rules and installed skill pack content.
@@ -0,0 +1,191 @@
+package encoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/javierbenavides/agentic-agent/internal/token"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by EncodeAs.
+const (
+	FormatTOON     = "toon"
+	FormatYAML     = "yaml"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists every output format, TOON first as the default.
+var Formats = []string{FormatTOON, FormatYAML, FormatJSON, FormatMarkdown}
+
+// markdownRenderer is implemented by values with a Markdown rendering.
+type markdownRenderer interface {
+	Markdown() string
+}
+
+// EncodeAs encodes v in the named format. An empty format means TOON.
+// Markdown is only available for values that render themselves, such as
+// ContextBundle.
+func EncodeAs(v interface{}, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "", FormatTOON:
+		return NewToonEncoder().Encode(v)
+	case FormatYAML, "yml":
+		return yaml.Marshal(v)
+	case FormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatMarkdown, "md":
+		if r, ok := v.(markdownRenderer); ok {
+			return []byte(r.Markdown()), nil
+		}
+		return nil, fmt.Errorf("markdown output is not supported for %T", v)
+	}
+	return nil, fmt.Errorf("unknown format %q (expected one of %s)", format, strings.Join(Formats, ", "))
+}
+
+// Markdown renders the bundle as a human-readable prompt document.
+func (b *ContextBundle) Markdown() string {
+	var sb strings.Builder
+	section := func(title string) { sb.WriteString("\n## " + title + "\n\n") }
+	list := func(items []string) {
+		for _, item := range items {
+			sb.WriteString("- " + item + "\n")
+		}
+	}
+
+	if b.Task != nil {
+		sb.WriteString(fmt.Sprintf("# Context Bundle: %s — %s\n", b.Task.ID, b.Task.Title))
+		section("Task")
+		if b.Task.Description != "" {
+			sb.WriteString(b.Task.Description + "\n\n")
+		}
+		if len(b.Task.Scope) > 0 {
+			sb.WriteString("**Scope:** " + strings.Join(b.Task.Scope, ", ") + "\n\n")
+		}
+		if len(b.Task.DependsOn) > 0 {
+			sb.WriteString("**Depends on:** " + strings.Join(b.Task.DependsOn, ", ") + "\n\n")
+		}
+		if len(b.Task.Acceptance) > 0 {
+			sb.WriteString("**Acceptance criteria:**\n\n")
+			list(b.Task.Acceptance)
+		}
+	} else {
+		sb.WriteString("# Context Bundle\n")
+	}
+
+	if b.Global != nil {
+		section("Global Context")
+		if b.Global.ProjectName != "" {
+			sb.WriteString("**Project:** " + b.Global.ProjectName + "\n\n")
+		}
+		if b.Global.Overview != "" {
+			sb.WriteString(b.Global.Overview + "\n\n")
+		}
+		if len(b.Global.Goals) > 0 {
+			sb.WriteString("**Goals:**\n\n")
+			list(b.Global.Goals)
+			sb.WriteString("\n")
+		}
+		if len(b.Global.Guidelines) > 0 {
+			sb.WriteString("**Guidelines:**\n\n")
+			list(b.Global.Guidelines)
+		}
+	}
+
+	for _, part := range []struct{ title, body string }{
+		{"Tech Stack", b.TechStack},
+		{"Workflow Preferences", b.Workflow},
+		{"Rolling Summary", b.Rolling},
+	} {
+		if strings.TrimSpace(part.body) != "" {
+			section(part.title)
+			sb.WriteString(strings.TrimSpace(part.body) + "\n")
+		}
+	}
+
+	if len(b.Specs) > 0 {
+		section("Specs")
+		for _, s := range b.Specs {
+			sb.WriteString("### " + s.Ref + "\n\n")
+			if !s.Found {
+				sb.WriteString("_Unresolved: " + s.Error + "_\n\n")
+				continue
+			}
+			sb.WriteString(strings.TrimSpace(s.Content) + "\n\n")
+		}
+	}
+
+	if strings.TrimSpace(b.SkillInstructions) != "" {
+		section("Skill Instructions")
+		sb.WriteString(strings.TrimSpace(b.SkillInstructions) + "\n")
+	}
+
+	if len(b.Directories) > 0 {
+		section("Directory Contexts")
+		for _, d := range b.Directories {
+			sb.WriteString("### " + d.Path + "\n\n")
+			if d.Purpose != "" {
+				sb.WriteString(strings.TrimSpace(d.Purpose) + "\n\n")
+			}
+			for _, group := range []struct {
+				title string
+				items []string
+			}{
+				{"Responsibilities", d.Responsibilities},
+				{"Architecture", d.LocalArchitecture},
+				{"Dependencies", d.Dependencies},
+				{"Must do", d.MustDo},
+				{"Cannot do", d.CannotDo},
+				{"Key files", d.KeyFiles},
+			} {
+				if len(group.items) > 0 {
+					sb.WriteString("**" + group.title + ":**\n\n")
+					list(group.items)
+					sb.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	if b.Manifest != nil {
+		section("Manifest")
+		sb.WriteString(b.Manifest.Summary() + "\n\n")
+		sb.WriteString("| Section | Relevance | Status | Tokens |\n|---|---|---|---|\n")
+		for _, e := range b.Manifest.Sections {
+			sb.WriteString(fmt.Sprintf("| %s %s | %s | %s | %d/%d |\n", e.Kind, e.Name, e.Relevance, e.Status, e.Tokens, e.Original))
+		}
+	}
+
+	return sb.String()
+}
+
+// FormatCost is the size of a value encoded in one format.
+type FormatCost struct {
+	Format string
+	Bytes  int
+	Tokens int
+	Err    error // set when the format does not apply to the value
+}
+
+// CompareFormats encodes v in every format and estimates the token cost of each.
+func CompareFormats(v interface{}) []FormatCost {
+	costs := make([]FormatCost, 0, len(Formats))
+	for _, format := range Formats {
+		cost := FormatCost{Format: format}
+		data, err := EncodeAs(v, format)
+		if err != nil {
+			cost.Err = err
+		} else {
+			cost.Bytes = len(data)
+			cost.Tokens = token.CountTokens(string(data))
+		}
+		costs = append(costs, cost)
+	}
+	return costs
+}
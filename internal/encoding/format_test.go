@@ -0,0 +1,80 @@
+package encoding
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func formatTestBundle() *ContextBundle {
+	bundle := &ContextBundle{
+		Task: &models.Task{
+			ID:         "TASK-1",
+			Title:      "Add login",
+			Scope:      []string{"internal/auth"},
+			Acceptance: []string{"Tokens expire"},
+		},
+		Global:      &models.GlobalContext{ProjectName: "demo"},
+		Rolling:     "Finished the db layer.",
+		Directories: []*models.DirectoryContext{{Path: "internal/auth", Purpose: "Handles login.", MustDo: []string{"Hash passwords"}}},
+	}
+	fitToBudget(bundle, nil, 0, FormatTOON)
+	return bundle
+}
+
+func TestEncodeAs_Formats(t *testing.T) {
+	bundle := formatTestBundle()
+
+	toon, err := EncodeAs(bundle, "")
+	require.NoError(t, err)
+	var fromToon ContextBundle
+	require.NoError(t, NewToonEncoder().Decode(toon, &fromToon))
+	assert.Equal(t, "TASK-1", fromToon.Task.ID)
+
+	data, err := EncodeAs(bundle, FormatYAML)
+	require.NoError(t, err)
+	var fromYAML ContextBundle
+	require.NoError(t, yaml.Unmarshal(data, &fromYAML))
+	assert.Equal(t, "Finished the db layer.", fromYAML.Rolling)
+
+	data, err = EncodeAs(bundle, FormatJSON)
+	require.NoError(t, err)
+	var fromJSON map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fromJSON))
+	assert.Contains(t, fromJSON, "directories")
+
+	md, err := EncodeAs(bundle, FormatMarkdown)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "# Context Bundle: TASK-1 — Add login")
+	assert.Contains(t, string(md), "### internal/auth")
+	assert.Contains(t, string(md), "- Hash passwords")
+	assert.Contains(t, string(md), "## Manifest")
+}
+
+func TestEncodeAs_Errors(t *testing.T) {
+	_, err := EncodeAs(formatTestBundle(), "xml")
+	assert.ErrorContains(t, err, `unknown format "xml"`)
+
+	_, err = EncodeAs(map[string]string{"a": "b"}, FormatMarkdown)
+	assert.ErrorContains(t, err, "markdown output is not supported")
+}
+
+func TestCompareFormats(t *testing.T) {
+	costs := CompareFormats(formatTestBundle())
+	require.Len(t, costs, len(Formats))
+
+	byFormat := make(map[string]FormatCost)
+	for _, c := range costs {
+		require.NoError(t, c.Err, c.Format)
+		assert.Positive(t, c.Tokens, c.Format)
+		byFormat[c.Format] = c
+	}
+	assert.Less(t, byFormat[FormatTOON].Tokens, byFormat[FormatJSON].Tokens)
+
+	costs = CompareFormats(map[string]int{"a": 1})
+	assert.Error(t, costs[len(costs)-1].Err, "markdown does not apply to plain data")
+}
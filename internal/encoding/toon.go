@@ -1,24 +1,710 @@
 package encoding
 
 import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"gopkg.in/yaml.v3"
 )
 
-// ToonEncoder wraps the encoding logic.
-// In a full implementation, this would import github.com/toon-format/toon-go.
-// For this MVP, we will use YAML as a stand-in for TOON to ensure compilation
-// without external dependencies that might be missing.
-type ToonEncoder struct{}
+// ToonEncoder writes and reads TOON (Token-Oriented Object Notation), a
+// compact notation for LLM prompts:
+//
+//	task:
+//	  id: TASK-1
+//	  scope[2]: internal/auth,pkg/jwt
+//	specs[2]{ref,found}:
+//	  auth.md,true
+//	  "db spec.md",false
+//	directories[1]:
+//	  - path: internal/auth
+//	    purpose: "Handles login.\nIssues tokens."
+//
+// Objects are indented two spaces per level, arrays declare their length,
+// arrays of uniform flat records become tables with a single header, and
+// strings are quoted only when they would otherwise be ambiguous.
+//
+// Values are mapped through their yaml tags, so anything that round-trips
+// through YAML round-trips through TOON.
+type ToonEncoder struct {
+	Indent int // spaces per level; defaults to 2
+}
 
 func NewToonEncoder() *ToonEncoder {
-	return &ToonEncoder{}
+	return &ToonEncoder{Indent: 2}
 }
 
 func (e *ToonEncoder) Encode(v interface{}) ([]byte, error) {
-	// Mock TOON encoding using YAML
-	return yaml.Marshal(v)
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, fmt.Errorf("toon: %w", err)
+	}
+	w := &toonWriter{indent: e.indent()}
+	if err := w.writeRoot(&node); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
 }
 
 func (e *ToonEncoder) Decode(data []byte, v interface{}) error {
-	return yaml.Unmarshal(data, v)
+	p, err := newToonParser(data, e.indent())
+	if err != nil {
+		return err
+	}
+	node, err := p.parseRoot()
+	if err != nil {
+		return err
+	}
+	if err := node.Decode(v); err != nil {
+		return fmt.Errorf("toon: %w", err)
+	}
+	return nil
+}
+
+func (e *ToonEncoder) indent() int {
+	if e.Indent <= 0 {
+		return 2
+	}
+	return e.Indent
+}
+
+var (
+	toonKeyPattern   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	toonIntPattern   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	toonFloatPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+const (
+	tagStr   = "!!str"
+	tagInt   = "!!int"
+	tagFloat = "!!float"
+	tagBool  = "!!bool"
+	tagNull  = "!!null"
+)
+
+// toonWriter renders a yaml.Node tree as TOON.
+type toonWriter struct {
+	buf    bytes.Buffer
+	indent int
+}
+
+func (w *toonWriter) writeRoot(n *yaml.Node) error {
+	n = resolveNode(n)
+	switch n.Kind {
+	case yaml.MappingNode:
+		return w.writeFields(n, 0)
+	case yaml.SequenceNode:
+		return w.writeArray("", n, 0)
+	case yaml.ScalarNode:
+		w.line(0, formatScalar(n))
+		return nil
+	}
+	return fmt.Errorf("toon: unsupported node kind %d", n.Kind)
+}
+
+func (w *toonWriter) writeFields(m *yaml.Node, depth int) error {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if err := w.writeField(formatKey(m.Content[i].Value), m.Content[i+1], depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *toonWriter) writeField(key string, v *yaml.Node, depth int) error {
+	v = resolveNode(v)
+	switch v.Kind {
+	case yaml.ScalarNode:
+		w.line(depth, key+": "+formatScalar(v))
+	case yaml.MappingNode:
+		w.line(depth, key+":")
+		return w.writeFields(v, depth+1)
+	case yaml.SequenceNode:
+		return w.writeArray(key, v, depth)
+	default:
+		return fmt.Errorf("toon: unsupported node kind %d for %s", v.Kind, key)
+	}
+	return nil
+}
+
+// writeArray picks the most compact form: inline for primitives, a table
+// for uniform flat records, and a list otherwise.
+func (w *toonWriter) writeArray(key string, seq *yaml.Node, depth int) error {
+	n := len(seq.Content)
+	header := fmt.Sprintf("%s[%d]", key, n)
+
+	if n == 0 {
+		w.line(depth, header+":")
+		return nil
+	}
+
+	if allScalars(seq.Content) {
+		values := make([]string, n)
+		for i, item := range seq.Content {
+			values[i] = formatScalar(resolveNode(item))
+		}
+		w.line(depth, header+": "+strings.Join(values, ","))
+		return nil
+	}
+
+	if fields, ok := tabularFields(seq.Content); ok {
+		keys := make([]string, len(fields))
+		for i, f := range fields {
+			keys[i] = formatKey(f)
+		}
+		w.line(depth, header+"{"+strings.Join(keys, ",")+"}:")
+		for _, item := range seq.Content {
+			item = resolveNode(item)
+			values := make([]string, 0, len(fields))
+			for i := 1; i < len(item.Content); i += 2 {
+				values = append(values, formatScalar(resolveNode(item.Content[i])))
+			}
+			w.line(depth+1, strings.Join(values, ","))
+		}
+		return nil
+	}
+
+	w.line(depth, header+":")
+	for _, item := range seq.Content {
+		if err := w.writeListItem(item, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeListItem writes "- value". An object's first field shares the hyphen
+// line and its remaining fields are indented one level past the hyphen.
+func (w *toonWriter) writeListItem(item *yaml.Node, depth int) error {
+	item = resolveNode(item)
+	switch item.Kind {
+	case yaml.ScalarNode:
+		w.line(depth, "- "+formatScalar(item))
+	case yaml.SequenceNode:
+		mark := w.buf.Len()
+		if err := w.writeArray("", item, depth); err != nil {
+			return err
+		}
+		w.prefixLine(mark, depth)
+	case yaml.MappingNode:
+		if len(item.Content) == 0 {
+			w.line(depth, "-")
+			return nil
+		}
+		mark := w.buf.Len()
+		if err := w.writeFields(item, depth+1); err != nil {
+			return err
+		}
+		w.prefixLine(mark, depth)
+	default:
+		return fmt.Errorf("toon: unsupported node kind %d in list", item.Kind)
+	}
+	return nil
+}
+
+// prefixLine turns the line starting at mark into a list item line at depth.
+func (w *toonWriter) prefixLine(mark, depth int) {
+	data := w.buf.Bytes()
+	line := data[mark:]
+	trimmed := bytes.TrimLeft(line, " ")
+	rewritten := append([]byte(strings.Repeat(" ", depth*w.indent)+"- "), trimmed...)
+	w.buf.Truncate(mark)
+	w.buf.Write(rewritten)
+}
+
+func (w *toonWriter) line(depth int, text string) {
+	w.buf.WriteString(strings.Repeat(" ", depth*w.indent))
+	w.buf.WriteString(text)
+	w.buf.WriteByte('\n')
+}
+
+func resolveNode(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+func allScalars(items []*yaml.Node) bool {
+	for _, item := range items {
+		if resolveNode(item).Kind != yaml.ScalarNode {
+			return false
+		}
+	}
+	return true
+}
+
+// tabularFields returns the shared field names when every item is a
+// non-empty record with the same keys in the same order and scalar values.
+func tabularFields(items []*yaml.Node) ([]string, bool) {
+	var fields []string
+	for idx, item := range items {
+		item = resolveNode(item)
+		if item.Kind != yaml.MappingNode || len(item.Content) == 0 {
+			return nil, false
+		}
+		if idx == 0 {
+			for i := 0; i < len(item.Content); i += 2 {
+				fields = append(fields, item.Content[i].Value)
+			}
+		}
+		if len(item.Content) != 2*len(fields) {
+			return nil, false
+		}
+		for i := 0; i < len(item.Content); i += 2 {
+			if item.Content[i].Value != fields[i/2] || resolveNode(item.Content[i+1]).Kind != yaml.ScalarNode {
+				return nil, false
+			}
+		}
+	}
+	return fields, true
+}
+
+func formatKey(key string) string {
+	if toonKeyPattern.MatchString(key) {
+		return key
+	}
+	return quoteString(key)
+}
+
+func formatScalar(n *yaml.Node) string {
+	switch n.Tag {
+	case tagInt, tagFloat, tagBool:
+		if toonFloatPattern.MatchString(n.Value) || n.Value == "true" || n.Value == "false" {
+			return n.Value
+		}
+	case tagNull:
+		return "null"
+	}
+	if needsQuotes(n.Value) {
+		return quoteString(n.Value)
+	}
+	return n.Value
+}
+
+// needsQuotes reports whether s would be misread if written bare: empty or
+// padded strings, literals that look like numbers, booleans or null, and
+// anything containing structural characters.
+func needsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	if s == "true" || s == "false" || s == "null" || toonFloatPattern.MatchString(s) {
+		return true
+	}
+	if strings.HasPrefix(s, "-") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || strings.ContainsRune(`,:"\[]{}`, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// toonLine is a non-blank input line with its nesting depth.
+type toonLine struct {
+	num   int
+	depth int
+	text  string
+}
+
+// toonParser builds a yaml.Node tree from TOON text.
+type toonParser struct {
+	lines []toonLine
+	pos   int
+}
+
+func newToonParser(data []byte, indent int) (*toonParser, error) {
+	p := &toonParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text := strings.TrimLeft(raw, " ")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		spaces := len(raw) - len(text)
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("toon: line %d: tabs are not allowed for indentation", i+1)
+		}
+		if spaces%indent != 0 {
+			return nil, fmt.Errorf("toon: line %d: indentation must be a multiple of %d spaces", i+1, indent)
+		}
+		p.lines = append(p.lines, toonLine{num: i + 1, depth: spaces / indent, text: strings.TrimRight(text, " ")})
+	}
+	return p, nil
+}
+
+func (p *toonParser) parseRoot() (*yaml.Node, error) {
+	if len(p.lines) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	first := p.lines[0]
+	if first.depth != 0 {
+		return nil, p.errorf(first, "unexpected indentation")
+	}
+
+	var node *yaml.Node
+	var err error
+	switch {
+	case strings.HasPrefix(first.text, "["):
+		p.pos++
+		node, err = p.parseArrayLine(first, "", first.text)
+	case isField(first.text):
+		node, err = p.parseObject(0)
+	case len(p.lines) == 1:
+		p.pos++
+		node, err = parseScalar(first.text)
+		if err != nil {
+			err = p.errorf(first, "%v", err)
+		}
+	default:
+		return nil, p.errorf(first, "expected key: value")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf(p.lines[p.pos], "unexpected content")
+	}
+	return node, nil
+}
+
+// parseObject reads consecutive fields at depth.
+func (p *toonParser) parseObject(depth int) (*yaml.Node, error) {
+	obj := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for p.pos < len(p.lines) {
+		ln := p.lines[p.pos]
+		if ln.depth < depth {
+			break
+		}
+		if ln.depth > depth {
+			return nil, p.errorf(ln, "unexpected indentation")
+		}
+		if strings.HasPrefix(ln.text, "-") {
+			break
+		}
+		p.pos++
+
+		key, rest, err := splitKey(ln.text)
+		if err != nil {
+			return nil, p.errorf(ln, "%v", err)
+		}
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: tagStr, Value: key}
+
+		var value *yaml.Node
+		switch {
+		case strings.HasPrefix(rest, "["):
+			value, err = p.parseArrayLine(ln, key, rest)
+		case rest == ":":
+			if p.pos < len(p.lines) && p.lines[p.pos].depth > depth {
+				value, err = p.parseObject(depth + 1)
+			} else {
+				value = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+		case strings.HasPrefix(rest, ":"):
+			value, err = parseScalar(strings.TrimSpace(rest[1:]))
+			if err != nil {
+				err = p.errorf(ln, "%v", err)
+			}
+		default:
+			err = p.errorf(ln, "expected ':' after key %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+		obj.Content = append(obj.Content, keyNode, value)
+	}
+	return obj, nil
+}
+
+// parseArrayLine parses "[N]...", the part of a line after the key, and
+// consumes any rows or items that follow.
+func (p *toonParser) parseArrayLine(ln toonLine, key, header string) (*yaml.Node, error) {
+	end := strings.IndexByte(header, ']')
+	if end < 0 {
+		return nil, p.errorf(ln, "unterminated array length for %q", key)
+	}
+	count, err := strconv.Atoi(header[1:end])
+	if err != nil || count < 0 {
+		return nil, p.errorf(ln, "invalid array length %q", header[1:end])
+	}
+	rest := header[end+1:]
+
+	var fields []string
+	if strings.HasPrefix(rest, "{") {
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return nil, p.errorf(ln, "unterminated field list")
+		}
+		raw, err := splitValues(rest[1:end])
+		if err != nil {
+			return nil, p.errorf(ln, "%v", err)
+		}
+		for _, f := range raw {
+			name, err := unquoteToken(f)
+			if err != nil {
+				return nil, p.errorf(ln, "%v", err)
+			}
+			fields = append(fields, name)
+		}
+		rest = rest[end+1:]
+	}
+	if !strings.HasPrefix(rest, ":") {
+		return nil, p.errorf(ln, "expected ':' after array header")
+	}
+	inline := strings.TrimSpace(rest[1:])
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+	switch {
+	case fields != nil:
+		for i := 0; i < count; i++ {
+			if p.pos >= len(p.lines) || p.lines[p.pos].depth != ln.depth+1 {
+				return nil, p.errorf(ln, "expected %d rows, found %d", count, i)
+			}
+			row := p.lines[p.pos]
+			p.pos++
+			values, err := splitValues(row.text)
+			if err != nil {
+				return nil, p.errorf(row, "%v", err)
+			}
+			if len(values) != len(fields) {
+				return nil, p.errorf(row, "expected %d values, found %d", len(fields), len(values))
+			}
+			rec := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for j, f := range fields {
+				v, err := parseScalar(values[j])
+				if err != nil {
+					return nil, p.errorf(row, "%v", err)
+				}
+				rec.Content = append(rec.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: tagStr, Value: f}, v)
+			}
+			seq.Content = append(seq.Content, rec)
+		}
+	case inline != "":
+		values, err := splitValues(inline)
+		if err != nil {
+			return nil, p.errorf(ln, "%v", err)
+		}
+		for _, raw := range values {
+			v, err := parseScalar(raw)
+			if err != nil {
+				return nil, p.errorf(ln, "%v", err)
+			}
+			seq.Content = append(seq.Content, v)
+		}
+	default:
+		for p.pos < len(p.lines) && p.lines[p.pos].depth == ln.depth+1 && strings.HasPrefix(p.lines[p.pos].text, "-") {
+			item, err := p.parseListItem()
+			if err != nil {
+				return nil, err
+			}
+			seq.Content = append(seq.Content, item)
+		}
+	}
+
+	if len(seq.Content) != count {
+		return nil, p.errorf(ln, "array declares %d items, found %d", count, len(seq.Content))
+	}
+	return seq, nil
+}
+
+// parseListItem parses a "- ..." line. An object item is parsed by treating
+// its first field as if the hyphen were indentation.
+func (p *toonParser) parseListItem() (*yaml.Node, error) {
+	ln := p.lines[p.pos]
+	if ln.text == "-" {
+		p.pos++
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	if !strings.HasPrefix(ln.text, "- ") {
+		return nil, p.errorf(ln, "expected '- ' list item")
+	}
+	content := strings.TrimSpace(ln.text[2:])
+
+	switch {
+	case strings.HasPrefix(content, "["):
+		p.pos++
+		return p.parseArrayLine(ln, "", content)
+	case isField(content):
+		p.lines[p.pos] = toonLine{num: ln.num, depth: ln.depth + 1, text: content}
+		return p.parseObject(ln.depth + 1)
+	default:
+		p.pos++
+		v, err := parseScalar(content)
+		if err != nil {
+			return nil, p.errorf(ln, "%v", err)
+		}
+		return v, nil
+	}
+}
+
+func (p *toonParser) errorf(ln toonLine, format string, args ...interface{}) error {
+	return fmt.Errorf("toon: line %d: %s", ln.num, fmt.Sprintf(format, args...))
+}
+
+// isField reports whether text starts with a key followed by ':' or '['.
+func isField(text string) bool {
+	_, rest, err := splitKey(text)
+	return err == nil && (strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "["))
+}
+
+// splitKey separates a leading (possibly quoted) key from the rest of the line.
+func splitKey(text string) (string, string, error) {
+	if strings.HasPrefix(text, `"`) {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated quoted key")
+		}
+		key, err := unquoteToken(text[:end+1])
+		return key, text[end+1:], err
+	}
+	i := strings.IndexAny(text, ":[")
+	if i <= 0 {
+		return "", text, fmt.Errorf("missing key")
+	}
+	return text[:i], text[i:], nil
+}
+
+// splitValues splits a comma-separated row, honouring quoted values.
+func splitValues(s string) ([]string, error) {
+	var values []string
+	for {
+		s = strings.TrimLeft(s, " ")
+		var token string
+		if strings.HasPrefix(s, `"`) {
+			end := closingQuote(s)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted value")
+			}
+			token, s = s[:end+1], strings.TrimLeft(s[end+1:], " ")
+			if s != "" && s[0] != ',' {
+				return nil, fmt.Errorf("unexpected text after quoted value")
+			}
+		} else if i := strings.IndexByte(s, ','); i >= 0 {
+			token, s = s[:i], s[i:]
+		} else {
+			token, s = s, ""
+		}
+		values = append(values, strings.TrimSpace(token))
+		if s == "" {
+			return values, nil
+		}
+		s = s[1:] // skip the comma
+	}
+}
+
+// closingQuote returns the index of the quote closing the string at s[0].
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func unquoteToken(token string) (string, error) {
+	if !strings.HasPrefix(token, `"`) {
+		return token, nil
+	}
+	if len(token) < 2 || !strings.HasSuffix(token, `"`) {
+		return "", fmt.Errorf("unterminated string %s", token)
+	}
+	var b strings.Builder
+	body := token[1 : len(token)-1]
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(body) {
+			return "", fmt.Errorf("dangling escape in %s", token)
+		}
+		switch body[i] {
+		case '"', '\\':
+			b.WriteByte(body[i])
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if i+4 >= len(body) {
+				return "", fmt.Errorf("invalid unicode escape in %s", token)
+			}
+			r, err := strconv.ParseUint(body[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape in %s", token)
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			return "", fmt.Errorf("invalid escape \\%c in %s", body[i], token)
+		}
+	}
+	return b.String(), nil
+}
+
+// parseScalar turns a bare or quoted token into a scalar node. Quoted
+// tokens are always strings; bare tokens may be numbers, booleans or null.
+func parseScalar(token string) (*yaml.Node, error) {
+	if strings.HasPrefix(token, `"`) {
+		s, err := unquoteToken(token)
+		if err != nil {
+			return nil, err
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tagStr, Value: s}, nil
+	}
+	tag := tagStr
+	switch {
+	case token == "true" || token == "false":
+		tag = tagBool
+	case token == "null":
+		tag = tagNull
+	case toonIntPattern.MatchString(token):
+		tag = tagInt
+	case toonFloatPattern.MatchString(token):
+		tag = tagFloat
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: token}, nil
 }
@@ -0,0 +1,196 @@
+package encoding
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/internal/specs"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestToonEncoder_Notation(t *testing.T) {
+	type spec struct {
+		Ref   string `yaml:"ref"`
+		Found bool   `yaml:"found"`
+	}
+	type doc struct {
+		Name  string            `yaml:"name"`
+		Count int               `yaml:"count"`
+		Tags  []string          `yaml:"tags"`
+		Specs []spec            `yaml:"specs"`
+		Meta  map[string]string `yaml:"meta"`
+		Empty []string          `yaml:"empty"`
+	}
+
+	out, err := NewToonEncoder().Encode(doc{
+		Name:  "demo",
+		Count: 3,
+		Tags:  []string{"go", "cli"},
+		Specs: []spec{{"auth.md", true}, {"db spec, v2", false}},
+		Meta:  map[string]string{"owner": "team"},
+		Empty: []string{},
+	})
+	require.NoError(t, err)
+
+	expected := `name: demo
+count: 3
+tags[2]: go,cli
+specs[2]{ref,found}:
+  auth.md,true
+  "db spec, v2",false
+meta:
+  owner: team
+empty[0]:
+`
+	assert.Equal(t, expected, string(out))
+}
+
+func TestToonEncoder_Quoting(t *testing.T) {
+	cases := map[string]string{
+		"plain":        "plain",
+		"two words":    "two words",
+		"":             `""`,
+		" padded":      `" padded"`,
+		"true":         `"true"`,
+		"null":         `"null"`,
+		"42":           `"42"`,
+		"-1.5":         `"-1.5"`,
+		"- item":       `"- item"`,
+		"a,b":          `"a,b"`,
+		"key: value":   `"key: value"`,
+		"line1\nline2": `"line1\nline2"`,
+		`say "hi"`:     `"say \"hi\""`,
+		"[x]":          `"[x]"`,
+		"path/to/file": "path/to/file",
+		"unicode é 日本": "unicode é 日本",
+	}
+	for in, want := range cases {
+		out, err := NewToonEncoder().Encode(map[string]string{"v": in})
+		require.NoError(t, err)
+		assert.Equal(t, "v: "+want+"\n", string(out), "input %q", in)
+	}
+}
+
+func TestToonEncoder_ListItems(t *testing.T) {
+	value := map[string]interface{}{
+		"items": []interface{}{
+			"scalar",
+			[]interface{}{1, 2},
+			map[string]interface{}{},
+			map[string]interface{}{"name": "x", "tags": []string{"a"}, "sub": map[string]interface{}{"k": "v"}},
+		},
+	}
+	out, err := NewToonEncoder().Encode(value)
+	require.NoError(t, err)
+
+	expected := `items[4]:
+  - scalar
+  - [2]: 1,2
+  -
+  - name: x
+    sub:
+      k: v
+    tags[1]: a
+`
+	assert.Equal(t, expected, string(out))
+
+	var decoded map[string]interface{}
+	require.NoError(t, NewToonEncoder().Decode(out, &decoded))
+	assert.Equal(t, value["items"].([]interface{})[0], decoded["items"].([]interface{})[0])
+	assert.Equal(t, []interface{}{1, 2}, decoded["items"].([]interface{})[1])
+	assert.Equal(t, map[string]interface{}{}, decoded["items"].([]interface{})[2])
+	assert.Equal(t, map[string]interface{}{
+		"name": "x",
+		"tags": []interface{}{"a"},
+		"sub":  map[string]interface{}{"k": "v"},
+	}, decoded["items"].([]interface{})[3])
+}
+
+func TestToonEncoder_RoundTripBundle(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	bundle := &ContextBundle{
+		Task: &models.Task{
+			ID:          "TASK-7",
+			Title:       "Add login: JWT",
+			Description: "Multi-line\ndescription with \"quotes\", commas\tand tabs",
+			Status:      models.StatusInProgress,
+			Scope:       []string{"internal/auth", "pkg/jwt"},
+			Acceptance:  []string{"cmd: go test ./internal/auth/...", "Tokens expire"},
+			ClaimedAt:   now,
+			DependsOn:   []string{"TASK-1"},
+		},
+		Global: &models.GlobalContext{ProjectName: "demo", Goals: []string{"ship"}, Updated: now},
+		Directories: []*models.DirectoryContext{
+			{Path: "internal/auth", Purpose: "# Context\n\nHandles auth.", KeyFiles: []string{"jwt.go"}},
+			{Path: "pkg/jwt", Purpose: "Signs tokens"},
+		},
+		Specs: []*specs.ResolvedSpec{
+			{Ref: "auth.md", Path: "specs/auth.md", Content: "## Auth\n- item", Found: true},
+			{Ref: "missing.md", Found: false, Error: "not found"},
+		},
+		BuiltAt: now,
+	}
+	fitToBudget(bundle, nil, 0, FormatTOON)
+
+	enc := NewToonEncoder()
+	data, err := enc.Encode(bundle)
+	require.NoError(t, err)
+
+	var decoded ContextBundle
+	require.NoError(t, enc.Decode(data, &decoded))
+	assert.Equal(t, bundle.Task, decoded.Task)
+	assert.Equal(t, bundle.Specs, decoded.Specs)
+	assert.Equal(t, bundle.Manifest, decoded.Manifest)
+	assert.True(t, bundle.BuiltAt.Equal(decoded.BuiltAt))
+
+	// Nil and empty slices are indistinguishable, exactly as in YAML
+	yamlData, err := yaml.Marshal(bundle)
+	require.NoError(t, err)
+	var viaYAML ContextBundle
+	require.NoError(t, yaml.Unmarshal(yamlData, &viaYAML))
+	assert.Equal(t, viaYAML, decoded)
+
+	// The manifest is uniform, so it is written as a table
+	assert.Contains(t, string(data), "sections[2]{kind,name,relevance,status,tokens,original_tokens}:")
+}
+
+func TestToonEncoder_RootValues(t *testing.T) {
+	enc := NewToonEncoder()
+
+	out, err := enc.Encode([]string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "[2]: a,b\n", string(out))
+	var list []string
+	require.NoError(t, enc.Decode(out, &list))
+	assert.Equal(t, []string{"a", "b"}, list)
+
+	out, err = enc.Encode("hello")
+	require.NoError(t, err)
+	var s string
+	require.NoError(t, enc.Decode(out, &s))
+	assert.Equal(t, "hello", s)
+}
+
+func TestToonEncoder_DecodeErrors(t *testing.T) {
+	cases := map[string]string{
+		"length mismatch":  "tags[3]: a,b\n",
+		"short table":      "rows[2]{a,b}:\n  1,2\n",
+		"row width":        "rows[1]{a,b}:\n  1,2,3\n",
+		"bad indentation":  "a:\n   b: 1\n",
+		"unterminated":     "a: \"open\n",
+		"missing colon":    "a b\nc: 1\n",
+		"unexpected child": "a: 1\n  b: 2\n",
+	}
+	for name, input := range cases {
+		var v interface{}
+		err := NewToonEncoder().Decode([]byte(input), &v)
+		assert.Error(t, err, name)
+		if err != nil {
+			assert.True(t, strings.HasPrefix(err.Error(), "toon: "), name)
+		}
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/javierbenavides/agentic-agent/internal/tasks"
+	"gopkg.in/yaml.v3"
 )
 
 // Archiver handles archiving of progress and task data when switching branches
@@ -74,10 +77,16 @@ func (a *Archiver) archivePreviousRun(branchName string) error {
 		return err
 	}
 
-	// Copy completed tasks
-	doneTasks := filepath.Join(a.tasksDir, "done.yaml")
-	if err := a.copyFileIfExists(doneTasks, filepath.Join(archivePath, "done.yaml")); err != nil {
-		return err
+	// Copy completed tasks; the embedded store is exported in the same format
+	if tasks.DetectStore(a.tasksDir) == tasks.StoreBolt {
+		if err := a.exportDoneTasks(filepath.Join(archivePath, "done.yaml")); err != nil {
+			return err
+		}
+	} else {
+		doneTasks := filepath.Join(a.tasksDir, "done.yaml")
+		if err := a.copyFileIfExists(doneTasks, filepath.Join(archivePath, "done.yaml")); err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("Archived previous run (%s) to: %s\n", branchName, archivePath)
@@ -100,6 +109,22 @@ func (a *Archiver) resetProgressFiles() error {
 	return nil
 }
 
+// exportDoneTasks writes the done list of the task database as YAML.
+func (a *Archiver) exportDoneTasks(dst string) error {
+	done, err := tasks.NewTaskManager(a.tasksDir).LoadTasks("done")
+	if err != nil {
+		return fmt.Errorf("failed to load done tasks: %w", err)
+	}
+	data, err := yaml.Marshal(done)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
+
 // copyFileIfExists copies a file from src to dst if src exists
 func (a *Archiver) copyFileIfExists(src, dst string) error {
 	if _, err := os.Stat(src); os.IsNotExist(err) {
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/javierbenavides/agentic-agent/internal/tasks"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -358,3 +360,23 @@ func TestArchiver_ArchivePreviousRun_MissingFiles(t *testing.T) {
 	assert.NoFileExists(t, filepath.Join(archivePath, "progress.yaml"))
 	assert.NoFileExists(t, filepath.Join(archivePath, "done.yaml"))
 }
+
+func TestArchiver_ArchiveIfBranchChanged_BoltStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	archiveDir := filepath.Join(tmpDir, "archive")
+	tasksDir := filepath.Join(tmpDir, "tasks")
+	archiver := NewArchiver(archiveDir, filepath.Join(tmpDir, "progress.txt"), filepath.Join(tmpDir, "progress.yaml"), tasksDir)
+	require.NoError(t, archiver.saveLastBranch("feature/old"))
+
+	tm := tasks.NewTaskManagerWithStore(tasksDir, tasks.NewBoltStore(tasksDir))
+	require.NoError(t, tm.SaveTasks("done", &tasks.TaskList{Tasks: []models.Task{{ID: "US-001", Title: "Completed task"}}}))
+
+	require.NoError(t, archiver.ArchiveIfBranchChanged("feature/new"))
+
+	archives, err := archiver.ListArchives()
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+	data, err := os.ReadFile(filepath.Join(archiveDir, archives[0], "done.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "id: US-001")
+}
@@ -2,11 +2,15 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/javierbenavides/agentic-agent/internal/agents"
 	"github.com/javierbenavides/agentic-agent/internal/checkpoint"
+	"github.com/javierbenavides/agentic-agent/internal/config"
 	appcontext "github.com/javierbenavides/agentic-agent/internal/context"
 	"github.com/javierbenavides/agentic-agent/internal/encoding"
 	"github.com/javierbenavides/agentic-agent/internal/openspec"
@@ -28,7 +32,14 @@ type AutopilotLoop struct {
 	specResolver     *specs.Resolver
 	trackManager     *tracks.Manager
 	executor         agents.Executor
+	newExecutor      func() agents.Executor
+	parallel         int
+	lease            time.Duration // claim lease renewed by keepAlive
+	pollInterval     time.Duration // wait before rechecking tasks blocked on in-progress work
+	reclaimExpired   bool
+	storeMu          sync.Mutex // serializes task store updates from parallel workers
 	checkpointMgr    *checkpoint.Manager
+	stateStore       *StateStore
 	tokenLimit       int
 	totalTokensUsed  int
 	currentIteration int
@@ -52,7 +63,10 @@ func NewAutopilotLoop(cfg *models.Config, maxIterations int, stopSignal string,
 		specResolver:     specs.NewResolver(cfg),
 		trackManager:     tracks.NewManager(cfg.Paths.TrackDir),
 		executor:         nil,
+		lease:            claimLease(cfg),
+		pollInterval:     30 * time.Second,
 		checkpointMgr:    checkpoint.NewManager(".agentic/checkpoints"),
+		stateStore:       NewStateStore(DefaultStateDir),
 		tokenLimit:       200000, // Default 200K tokens (Claude limit)
 		totalTokensUsed:  0,
 		currentIteration: 0,
@@ -63,7 +77,10 @@ func NewAutopilotLoop(cfg *models.Config, maxIterations int, stopSignal string,
 func (a *AutopilotLoop) WithAgentExecution(enabled bool) *AutopilotLoop {
 	a.executeAgent = enabled
 	if enabled && a.cfg.ActiveAgent != "" {
-		a.executor = agents.NewExecutor(a.cfg.ActiveAgent)
+		a.executor = agents.NewExecutorWithConfig(a.cfg.ActiveAgent, config.GetAgentOverride(a.cfg, a.cfg.ActiveAgent))
+		a.newExecutor = func() agents.Executor {
+			return agents.NewExecutorWithConfig(a.cfg.ActiveAgent, config.GetAgentOverride(a.cfg, a.cfg.ActiveAgent))
+		}
 	}
 	return a
 }
@@ -88,6 +105,9 @@ func (a *AutopilotLoop) Run(ctx context.Context) error {
 	}
 
 	fmt.Printf("🔄 Max iterations: %d\n", a.maxIterations)
+	if a.parallel > 1 && !a.dryRun {
+		fmt.Printf("⚡ Parallel workers: %d\n", a.parallel)
+	}
 	if a.dryRun {
 		fmt.Println("🔍 Mode: DRY RUN (no changes will be made)")
 	}
@@ -118,6 +138,14 @@ func (a *AutopilotLoop) Run(ctx context.Context) error {
 		user = "autopilot"
 	}
 
+	if err := a.checkExpiredClaims(user); err != nil {
+		fmt.Printf("Warning: could not check expired claims: %v\n", err)
+	}
+
+	if a.parallel > 1 && !a.dryRun {
+		return a.runParallel(ctx, user)
+	}
+
 	for iteration := 1; iteration <= a.maxIterations; iteration++ {
 		select {
 		case <-ctx.Done():
@@ -126,11 +154,39 @@ func (a *AutopilotLoop) Run(ctx context.Context) error {
 		default:
 		}
 
-		// 1. Find next claimable task
-		task, err := a.findNextTask()
+		// 1. Resume an interrupted task, or find the next claimable one
+		task, err := a.findResumableTask(user, nil)
+		if err != nil {
+			return fmt.Errorf("iteration %d: %w", iteration, err)
+		}
+		if task != nil {
+			fmt.Printf("\n--- Iteration %d/%d ---\n", iteration, a.maxIterations)
+			fmt.Printf("Resuming task: [%s] %s\n", task.ID, task.Title)
+			stop := a.keepAlive(task.ID, user)
+			a.executeTask(ctx, task)
+			stop()
+			continue
+		}
+
+		task, waiting, err := a.findNextTask()
 		if err != nil {
 			return fmt.Errorf("iteration %d: %w", iteration, err)
 		}
+		if waiting {
+			fmt.Printf("\n--- Iteration %d/%d ---\n", iteration, a.maxIterations)
+			if a.dryRun {
+				fmt.Println("[DRY RUN] Remaining backlog tasks wait on dependencies still in progress")
+				return nil
+			}
+			fmt.Printf("Remaining backlog tasks wait on dependencies still in progress; checking again in %s\n", a.pollInterval)
+			select {
+			case <-ctx.Done():
+				fmt.Println("Autopilot cancelled.")
+				return ctx.Err()
+			case <-time.After(a.pollInterval):
+			}
+			continue
+		}
 		if task == nil {
 			fmt.Println("All tasks complete. Autopilot finished.")
 			return nil
@@ -140,7 +196,11 @@ func (a *AutopilotLoop) Run(ctx context.Context) error {
 		fmt.Printf("Next task: [%s] %s\n", task.ID, task.Title)
 
 		// 2. Run readiness checks
-		result := tasks.CanClaimTask(task, a.cfg)
+		completed, err := a.taskManager.CompletedTaskIDs()
+		if err != nil {
+			return fmt.Errorf("iteration %d: %w", iteration, err)
+		}
+		result := tasks.CanClaimTaskWithDeps(task, a.cfg, completed)
 		fmt.Print(tasks.FormatReadinessResult(result))
 
 		if a.dryRun {
@@ -154,6 +214,10 @@ func (a *AutopilotLoop) Run(ctx context.Context) error {
 			continue
 		}
 		fmt.Printf("Claimed task %s\n", task.ID)
+		stop := a.keepAlive(task.ID, user)
+		if err := a.stateStore.Reset(task.ID, user, "claimed from backlog"); err != nil {
+			fmt.Printf("  Warning: could not reset task state: %v\n", err)
+		}
 
 		// 4. Generate context for scope dirs
 		for _, dir := range task.Scope {
@@ -163,144 +227,286 @@ func (a *AutopilotLoop) Run(ctx context.Context) error {
 				continue
 			}
 			dcm := appcontext.NewDirectoryContextManager(dir)
-			if err := dcm.SaveContext(dir, dirCtx); err != nil {
+			if _, err := dcm.UpdateContext(dir, dirCtx); err != nil {
 				fmt.Printf("  Warning: could not save context for %s: %v\n", dir, err)
 				continue
 			}
-			fmt.Printf("  Generated context for %s\n", dir)
+			fmt.Printf("  Updated context for %s\n", dir)
 		}
 
 		// 5. Build context bundle (with resolved specs)
-		bundle, err := encoding.CreateContextBundle(task.ID, "toon", a.cfg)
+		bundle, err := encoding.BuildContextBundle(task.ID, "", a.cfg)
 		if err != nil {
 			fmt.Printf("  Warning: could not build context bundle: %v\n", err)
 		} else {
-			fmt.Printf("  Context bundle built (%d bytes)\n", len(bundle))
+			fmt.Printf("  Context bundle built (%s)\n", bundle.Manifest.Summary())
 		}
 
 		// 6. Execute agent if enabled
 		if a.executeAgent && a.executor != nil {
-			fmt.Printf("\n🤖 Executing %s agent...\n", a.cfg.ActiveAgent)
-
-			// Check for existing checkpoint to resume from
-			existingCheckpoint, _ := a.checkpointMgr.Load(task.ID)
-			if existingCheckpoint != nil {
-				fmt.Printf("📌 Resuming from checkpoint (iteration %d, %d tokens used)\n",
-					existingCheckpoint.Iteration, existingCheckpoint.TokensUsed)
-				a.currentIteration = existingCheckpoint.Iteration
-				a.totalTokensUsed = existingCheckpoint.TokensUsed
-			}
+			a.executeTask(ctx, task)
+		} else {
+			// 6. Report task ready for agent execution
+			fmt.Printf("Task %s is ready for agent execution.\n", task.ID)
+		}
+		stop()
+	}
+
+	fmt.Printf("Reached max iterations (%d). Stopping autopilot.\n", a.maxIterations)
+	return nil
+}
 
-			a.currentIteration++
-			prompt := fmt.Sprintf("Complete task %s: %s\n\n%s", task.ID, task.Title, task.Description)
-			result, err := a.executor.Execute(ctx, prompt, task)
+// executeTask runs one agent execution for a claimed task, recording each
+// state transition so an interrupted session resumes in the right phase.
+func (a *AutopilotLoop) executeTask(ctx context.Context, task *models.Task) {
+	tracker, _, err := newStateTracker(a.stateStore, task.ID, a.cfg.ActiveAgent)
+	if err != nil {
+		fmt.Printf("  ⚠️  Could not load task state: %v\n", err)
+		return
+	}
+	if tracker.sm.CurrentState == StateDone {
+		// Verified in an earlier session but never moved to done
+		a.completeTask(task, nil)
+		return
+	}
+	if err := enterExecution(tracker); err != nil {
+		fmt.Printf("  ⚠️  Could not start execution: %v\n", err)
+		return
+	}
 
-			if err != nil {
-				fmt.Printf("  ⚠️  Agent execution error: %v\n", err)
-			} else {
-				a.totalTokensUsed += result.TokensUsed
-				fmt.Printf("  ✅ Agent completed (tokens: %d, total: %d)\n", result.TokensUsed, a.totalTokensUsed)
-				fmt.Printf("  Output: %s\n", result.Output)
+	fmt.Printf("\n🤖 Executing %s agent...\n", a.cfg.ActiveAgent)
 
-				// Create checkpoint if needed (use configured thresholds or defaults)
-				iterationInterval := 5
-				tokenThresholds := []float64{0.5, 0.75, 0.9}
+	// Check for existing checkpoint to resume from
+	existingCheckpoint, _ := a.checkpointMgr.Load(task.ID)
+	if existingCheckpoint != nil {
+		fmt.Printf("📌 Resuming from checkpoint (iteration %d, %d tokens used)\n",
+			existingCheckpoint.Iteration, existingCheckpoint.TokensUsed)
+		a.currentIteration = existingCheckpoint.Iteration
+		a.totalTokensUsed = existingCheckpoint.TokensUsed
+	}
 
-				if a.cfg.Checkpoint.IterationInterval > 0 {
-					iterationInterval = a.cfg.Checkpoint.IterationInterval
-				}
-				if len(a.cfg.Checkpoint.TokenThresholds) > 0 {
-					tokenThresholds = a.cfg.Checkpoint.TokenThresholds
-				}
+	a.currentIteration++
+	prompt := fmt.Sprintf("Complete task %s: %s\n\n%s", task.ID, task.Title, task.Description)
+	result, err := a.executor.Execute(ctx, prompt, task)
 
-				if a.checkpointMgr.ShouldCheckpointWithThresholds(a.totalTokensUsed, a.tokenLimit, a.currentIteration, iterationInterval, tokenThresholds) {
-					chkpt := checkpoint.CreateFromResult(task.ID, a.currentIteration, a.cfg.ActiveAgent, result, task)
-					chkpt.TokensUsed = a.totalTokensUsed // Use cumulative total
-					if err := a.checkpointMgr.Save(chkpt); err != nil {
-						fmt.Printf("  ⚠️  Failed to save checkpoint: %v\n", err)
-					} else {
-						progress := a.checkpointMgr.GetProgress(chkpt, len(task.Acceptance))
-						fmt.Printf("  💾 Checkpoint saved (iteration %d, %.1f%% complete)\n", a.currentIteration, progress)
-					}
-				}
+	if err != nil {
+		fmt.Printf("  ⚠️  Agent execution error: %v\n", err)
+	} else {
+		a.totalTokensUsed += result.TokensUsed
+		if err := tracker.setIteration(a.currentIteration); err != nil {
+			fmt.Printf("  ⚠️  Could not record task state: %v\n", err)
+		}
+		if err := tracker.fire(EventWorkCompleted, ""); err != nil {
+			fmt.Printf("  ⚠️  Could not record task state: %v\n", err)
+		}
+		fmt.Printf("  ✅ Agent completed (tokens: %d, total: %d)\n", result.TokensUsed, a.totalTokensUsed)
+		fmt.Printf("  Output: %s\n", result.Output)
 
-				// Check if approaching token limit
-				tokenPercentage := float64(a.totalTokensUsed) / float64(a.tokenLimit) * 100
-				if tokenPercentage >= 80 {
-					fmt.Printf("  ⚠️  Token usage at %.1f%% of limit (%d/%d)\n",
-						tokenPercentage, a.totalTokensUsed, a.tokenLimit)
-					if tokenPercentage >= 90 {
-						fmt.Println("  🛑 Approaching token limit! Consider pausing and resuming later.")
-					}
-				}
+		// Create checkpoint if needed (use configured thresholds or defaults)
+		iterationInterval := 5
+		tokenThresholds := []float64{0.5, 0.75, 0.9}
 
-				if result.Success {
-					fmt.Printf("  ✅ All acceptance criteria met!\n")
-					// Auto-complete task
-					learnings := []string{fmt.Sprintf("Completed by %s agent in %d iterations", a.cfg.ActiveAgent, a.currentIteration)}
-					if err := a.taskManager.CompleteTaskWithTracking(task.ID, learnings, result.FilesModified, ""); err != nil {
-						fmt.Printf("  ⚠️  Could not complete task: %v\n", err)
-					} else {
-						fmt.Printf("  ✅ Task %s completed successfully\n", task.ID)
-						// Clean up checkpoints after successful completion
-						if err := a.checkpointMgr.DeleteAll(task.ID); err != nil {
-							fmt.Printf("  ⚠️  Could not clean up checkpoints: %v\n", err)
-						}
-					}
-				} else {
-					fmt.Printf("  ⚠️  Criteria not met: %v\n", result.CriteriaFailed)
-					fmt.Printf("  📊 Progress: %d/%d criteria met\n",
-						len(result.CriteriaMet), len(task.Acceptance))
-				}
+		if a.cfg.Checkpoint.IterationInterval > 0 {
+			iterationInterval = a.cfg.Checkpoint.IterationInterval
+		}
+		if len(a.cfg.Checkpoint.TokenThresholds) > 0 {
+			tokenThresholds = a.cfg.Checkpoint.TokenThresholds
+		}
+
+		if a.checkpointMgr.ShouldCheckpointWithThresholds(a.totalTokensUsed, a.tokenLimit, a.currentIteration, iterationInterval, tokenThresholds) {
+			chkpt := checkpoint.CreateFromResult(task.ID, a.currentIteration, a.cfg.ActiveAgent, result, task)
+			chkpt.TokensUsed = a.totalTokensUsed // Use cumulative total
+			if err := a.checkpointMgr.Save(chkpt); err != nil {
+				fmt.Printf("  ⚠️  Failed to save checkpoint: %v\n", err)
+			} else {
+				progress := a.checkpointMgr.GetProgress(chkpt, len(task.Acceptance))
+				fmt.Printf("  💾 Checkpoint saved (iteration %d, %.1f%% complete)\n", a.currentIteration, progress)
+			}
+		}
+
+		// Check if approaching token limit
+		tokenPercentage := float64(a.totalTokensUsed) / float64(a.tokenLimit) * 100
+		if tokenPercentage >= 80 {
+			fmt.Printf("  ⚠️  Token usage at %.1f%% of limit (%d/%d)\n",
+				tokenPercentage, a.totalTokensUsed, a.tokenLimit)
+			if tokenPercentage >= 90 {
+				fmt.Println("  🛑 Approaching token limit! Consider pausing and resuming later.")
+			}
+		}
+
+		if result.Success {
+			fmt.Printf("  ✅ All acceptance criteria met!\n")
+			if err := tracker.fire(EventVerificationPass, "all acceptance criteria met"); err != nil {
+				fmt.Printf("  ⚠️  Could not record task state: %v\n", err)
 			}
+			a.completeTask(task, result.FilesModified)
 		} else {
-			// 6. Report task ready for agent execution
-			fmt.Printf("Task %s is ready for agent execution.\n", task.ID)
+			reason := fmt.Sprintf("%d/%d criteria met", len(result.CriteriaMet), len(task.Acceptance))
+			if err := tracker.fire(EventVerificationFail, reason); err != nil {
+				fmt.Printf("  ⚠️  Could not record task state: %v\n", err)
+			}
+			fmt.Printf("  ⚠️  Criteria not met: %v\n", result.CriteriaFailed)
+			for _, c := range result.Criteria {
+				if !c.Passed && c.Evidence != "" {
+					fmt.Printf("     ✗ %s [%s]: %s\n", c.Criterion, c.Method, c.Evidence)
+				}
+			}
+			fmt.Printf("  📊 Progress: %d/%d criteria met\n",
+				len(result.CriteriaMet), len(task.Acceptance))
 		}
 	}
+}
 
-	fmt.Printf("Reached max iterations (%d). Stopping autopilot.\n", a.maxIterations)
+// completeTask moves a verified task to done and cleans up its checkpoints.
+func (a *AutopilotLoop) completeTask(task *models.Task, filesModified []string) {
+	learnings := []string{fmt.Sprintf("Completed by %s agent in %d iterations", a.cfg.ActiveAgent, a.currentIteration)}
+	if err := a.taskManager.CompleteTaskWithTracking(task.ID, learnings, filesModified, ""); err != nil {
+		fmt.Printf("  ⚠️  Could not complete task: %v\n", err)
+		return
+	}
+	fmt.Printf("  ✅ Task %s completed successfully\n", task.ID)
+	// Clean up checkpoints after successful completion
+	if err := a.checkpointMgr.DeleteAll(task.ID); err != nil {
+		fmt.Printf("  ⚠️  Could not clean up checkpoints: %v\n", err)
+	}
+}
+
+// enterExecution advances the tracked state to EXECUTION. Autopilot has no
+// separate planning step, and an interrupted verification is redone.
+func enterExecution(tracker *stateTracker) error {
+	switch tracker.sm.CurrentState {
+	case StateIdle:
+		if err := tracker.fire(EventTaskStarted, "claimed by autopilot"); err != nil {
+			return err
+		}
+		return tracker.fire(EventPlanApproved, "autopilot executes without a separate plan")
+	case StatePlanning:
+		return tracker.fire(EventPlanApproved, "autopilot executes without a separate plan")
+	case StateVerification:
+		return tracker.fire(EventVerificationFail, "verification interrupted; resuming execution")
+	}
 	return nil
 }
 
+// findResumableTask returns an in-progress task claimed by user whose
+// persisted state shows an interrupted execution, or nil. Tasks rejected by
+// eligible (if set) are skipped.
+func (a *AutopilotLoop) findResumableTask(user string, eligible func(*models.Task) bool) (*models.Task, error) {
+	if !a.executeAgent || a.executor == nil || a.dryRun {
+		return nil, nil
+	}
+	inProgress, err := a.taskManager.LoadTasks("in-progress")
+	if err != nil {
+		return nil, err
+	}
+	for i := range inProgress.Tasks {
+		t := &inProgress.Tasks[i]
+		if t.AssignedTo != user || (eligible != nil && !eligible(t)) {
+			continue
+		}
+		ts, err := a.stateStore.Load(t.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ts != nil && ts.State != StateIdle && ts.State != StateDone {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+// errDependenciesPending is returned by pickTask when every candidate
+// waits on a prerequisite that is not done yet.
+var errDependenciesPending = errors.New("waiting on unfinished dependencies")
+
 // findNextTask finds the next claimable task from the backlog.
-// Prefers tasks where readiness checks all pass.
-func (a *AutopilotLoop) findNextTask() (*models.Task, error) {
+// Prefers tasks where readiness checks all pass. waiting is true when no
+// task can be claimed yet only because prerequisites are still in progress,
+// e.g. claimed by another agent.
+func (a *AutopilotLoop) findNextTask() (task *models.Task, waiting bool, err error) {
 	backlog, err := a.taskManager.LoadTasks("backlog")
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	inProgress, err := a.taskManager.LoadTasks("in-progress")
+	if err != nil {
+		return nil, false, err
 	}
 
 	if len(backlog.Tasks) == 0 {
 		// Check if there are in-progress tasks still running
-		inProgress, err := a.taskManager.LoadTasks("in-progress")
-		if err != nil {
-			return nil, err
-		}
 		if len(inProgress.Tasks) > 0 {
-			return nil, fmt.Errorf("no backlog tasks but %d still in progress", len(inProgress.Tasks))
+			return nil, false, fmt.Errorf("no backlog tasks but %d still in progress", len(inProgress.Tasks))
 		}
-		return nil, nil // All done
+		return nil, false, nil // All done
+	}
+
+	task, err = a.pickTask(backlog.Tasks, nil)
+	if errors.Is(err, errDependenciesPending) && waitsOnInProgress(backlog.Tasks, inProgress.Tasks) {
+		return nil, true, nil
+	}
+	return task, false, err
+}
+
+// waitsOnInProgress reports whether any backlog task depends on a task that
+// is in progress, so it becomes claimable once that task is done.
+func waitsOnInProgress(backlog, inProgress []models.Task) bool {
+	running := make(map[string]bool, len(inProgress))
+	for _, t := range inProgress {
+		running[t.ID] = true
+	}
+	for _, t := range backlog {
+		for _, dep := range t.DependsOn {
+			if running[dep] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pickTask chooses the next task to claim from backlog. Tasks rejected by
+// eligible (if set) are skipped without counting as waiting.
+func (a *AutopilotLoop) pickTask(backlog []models.Task, eligible func(*models.Task) bool) (*models.Task, error) {
+	// Walk the backlog in dependency order so prerequisites come first
+	ordered, err := tasks.OrderByDependencies(backlog)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := a.taskManager.CompletedTaskIDs()
+	if err != nil {
+		return nil, err
 	}
 
 	// Prefer tasks that are fully ready and whose track (if any) is active
-	for _, t := range backlog.Tasks {
-		if a.isTaskBlocked(&t) {
+	waiting := 0
+	for _, t := range ordered {
+		if a.isTaskBlocked(&t) || (eligible != nil && !eligible(&t)) {
+			continue
+		}
+		if len(tasks.UnmetDependencies(&t, completed)) > 0 {
+			waiting++
 			continue
 		}
-		result := tasks.CanClaimTask(&t, a.cfg)
+		result := tasks.CanClaimTaskWithDeps(&t, a.cfg, completed)
 		if result.Ready {
 			return &t, nil
 		}
 	}
 
-	// Fall back to first unblocked task in backlog
-	for _, t := range backlog.Tasks {
-		if !a.isTaskBlocked(&t) {
+	// Fall back to first unblocked task whose dependencies are done
+	for _, t := range ordered {
+		if eligible != nil && !eligible(&t) {
+			continue
+		}
+		if !a.isTaskBlocked(&t) && len(tasks.UnmetDependencies(&t, completed)) == 0 {
 			return &t, nil
 		}
 	}
 
+	if waiting > 0 {
+		return nil, fmt.Errorf("%d backlog task(s) %w", waiting, errDependenciesPending)
+	}
 	return nil, nil
 }
 
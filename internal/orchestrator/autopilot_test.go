@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/javierbenavides/agentic-agent/internal/checkpoint"
 	"github.com/javierbenavides/agentic-agent/internal/config"
 	"github.com/javierbenavides/agentic-agent/internal/tasks"
 	"github.com/javierbenavides/agentic-agent/pkg/models"
@@ -126,3 +128,158 @@ func TestAutopilotLoop_MaxIterations(t *testing.T) {
 	err := loop.Run(context.Background())
 	assert.NoError(t, err) // Dry run doesn't error on max iterations
 }
+
+func TestAutopilotLoop_FindNextTaskFollowsDependencies(t *testing.T) {
+	base, cfg := setupAutopilotTestDir(t)
+	tasksDir := filepath.Join(base, ".agentic", "tasks")
+
+	writeTasksFile(t, tasksDir, "backlog", tasks.TaskList{
+		Tasks: []models.Task{
+			{ID: "T-2", Title: "Needs T-1", Status: models.StatusPending, DependsOn: []string{"T-1"}},
+			{ID: "T-1", Title: "Foundation", Status: models.StatusPending},
+		},
+	})
+
+	loop := NewAutopilotLoop(cfg, 1, "", true)
+	loop.taskManager = tasks.NewTaskManager(tasksDir)
+
+	task, waiting, err := loop.findNextTask()
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.False(t, waiting)
+	assert.Equal(t, "T-1", task.ID)
+
+	// With T-1 claimed elsewhere, T-2 waits for it instead of failing
+	writeTasksFile(t, tasksDir, "backlog", tasks.TaskList{
+		Tasks: []models.Task{
+			{ID: "T-2", Title: "Needs T-1", Status: models.StatusPending, DependsOn: []string{"T-1"}},
+		},
+	})
+	writeTasksFile(t, tasksDir, "in-progress", tasks.TaskList{
+		Tasks: []models.Task{
+			{ID: "T-1", Title: "Foundation", Status: models.StatusInProgress, AssignedTo: "other"},
+		},
+	})
+	task, waiting, err = loop.findNextTask()
+	require.NoError(t, err)
+	assert.Nil(t, task)
+	assert.True(t, waiting)
+
+	// A prerequisite that nobody is working on can never finish
+	writeTasksFile(t, tasksDir, "in-progress", tasks.TaskList{})
+	task, waiting, err = loop.findNextTask()
+	assert.Nil(t, task)
+	assert.False(t, waiting)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waiting on unfinished dependencies")
+}
+
+func TestAutopilotLoop_WaitsForInProgressDependencies(t *testing.T) {
+	base, cfg := setupAutopilotTestDir(t)
+	tasksDir := filepath.Join(base, ".agentic", "tasks")
+	t.Setenv("USER", "tester")
+
+	writeTasksFile(t, tasksDir, "backlog", tasks.TaskList{
+		Tasks: []models.Task{{ID: "T-2", Title: "Needs T-1", DependsOn: []string{"T-1"}}},
+	})
+	writeTasksFile(t, tasksDir, "in-progress", tasks.TaskList{
+		Tasks: []models.Task{{ID: "T-1", Title: "Foundation", Status: models.StatusInProgress, AssignedTo: "other"}},
+	})
+
+	loop := NewAutopilotLoop(cfg, 2, "", false)
+	loop.taskManager = tasks.NewTaskManager(tasksDir)
+	loop.pollInterval = time.Millisecond
+
+	require.NoError(t, loop.Run(context.Background()))
+	backlog, _ := loop.taskManager.LoadTasks("backlog")
+	assert.Len(t, backlog.Tasks, 1, "T-2 stays in the backlog until T-1 is done")
+}
+
+func TestAutopilotLoop_ResumesInterruptedTask(t *testing.T) {
+	base, cfg := setupAutopilotTestDir(t)
+	tasksDir := filepath.Join(base, ".agentic", "tasks")
+	t.Setenv("USER", "tester")
+
+	writeTasksFile(t, tasksDir, "backlog", tasks.TaskList{})
+	writeTasksFile(t, tasksDir, "in-progress", tasks.TaskList{
+		Tasks: []models.Task{
+			{ID: "T-1", Title: "Half done", Status: models.StatusInProgress, AssignedTo: "tester", Acceptance: []string{"works"}},
+		},
+	})
+
+	// A previous session was interrupted during verification
+	store := NewStateStore(filepath.Join(base, ".agentic", "state"))
+	tracker, _, err := newStateTracker(store, "T-1", "tester")
+	require.NoError(t, err)
+	for _, e := range []Event{EventTaskStarted, EventPlanApproved, EventWorkCompleted} {
+		require.NoError(t, tracker.fire(e, ""))
+	}
+
+	exec := &scriptedExecutor{}
+	loop := NewAutopilotLoop(cfg, 3, "", false)
+	loop.taskManager = tasks.NewTaskManager(tasksDir)
+	loop.checkpointMgr = checkpoint.NewManager(filepath.Join(base, ".agentic", "checkpoints"))
+	loop.stateStore = store
+	loop.executeAgent = true
+	loop.executor = exec
+
+	require.NoError(t, loop.Run(context.Background()))
+	assert.Len(t, exec.prompts, 1)
+
+	done, _ := loop.taskManager.LoadTasks("done")
+	require.Len(t, done.Tasks, 1)
+
+	ts, err := store.Load("T-1")
+	require.NoError(t, err)
+	assert.Equal(t, StateDone, ts.State)
+	n := len(ts.History)
+	assert.Equal(t, EventVerificationFail, ts.History[n-3].Event)
+	assert.Equal(t, "verification interrupted; resuming execution", ts.History[n-3].Reason)
+}
+
+func TestAutopilotLoop_ReclaimsExpiredClaims(t *testing.T) {
+	// Claims go through the synthetic worktree path of the parallel setup
+	loop, tasksDir, _ := setupParallelAutopilot(t, nil)
+	stale := time.Now().Add(-2 * time.Hour)
+	writeTasksFile(t, tasksDir, "in-progress", tasks.TaskList{
+		Tasks: []models.Task{
+			{ID: "T-1", Title: "Abandoned", Status: models.StatusInProgress, AssignedTo: "crashed-agent", ClaimedAt: stale, LeaseExpiresAt: stale.Add(time.Minute)},
+		},
+	})
+	loop.maxIterations = 1
+	loop.WithReclaimExpired(true)
+
+	require.NoError(t, loop.Run(context.Background()))
+
+	inProgress, err := loop.taskManager.LoadTasks("in-progress")
+	require.NoError(t, err)
+	require.Len(t, inProgress.Tasks, 1)
+	task := inProgress.Tasks[0]
+	assert.Equal(t, "tester", task.AssignedTo, "the reclaimed task is claimed again")
+	assert.True(t, task.LeaseExpiresAt.After(time.Now()))
+	assert.False(t, task.HeartbeatAt.IsZero(), "autopilot renews the lease while it works")
+}
+
+func TestAutopilotLoop_KeepAlive(t *testing.T) {
+	base, cfg := setupAutopilotTestDir(t)
+	tasksDir := filepath.Join(base, ".agentic", "tasks")
+	writeTasksFile(t, tasksDir, "in-progress", tasks.TaskList{
+		Tasks: []models.Task{{ID: "T-1", Status: models.StatusInProgress, AssignedTo: "tester", ClaimedAt: time.Now()}},
+	})
+
+	loop := NewAutopilotLoop(cfg, 1, "", false)
+	loop.taskManager = tasks.NewTaskManager(tasksDir)
+	loop.lease = 30 * time.Millisecond
+
+	stop := loop.keepAlive("T-1", "tester")
+	first, _, err := loop.taskManager.FindTask("T-1")
+	require.NoError(t, err)
+	require.False(t, first.HeartbeatAt.IsZero(), "the lease is renewed right away")
+
+	assert.Eventually(t, func() bool {
+		task, _, err := loop.taskManager.FindTask("T-1")
+		return err == nil && task.HeartbeatAt.After(first.HeartbeatAt)
+	}, time.Second, 5*time.Millisecond)
+	stop()
+	stop()
+}
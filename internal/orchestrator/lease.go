@@ -0,0 +1,114 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/internal/tasks"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// WithReclaimExpired makes autopilot return tasks whose claim lease expired
+// to the backlog before it starts, so they can be picked up again. Their
+// worktrees are kept. Tasks claimed by the autopilot user itself are left
+// to the resume logic.
+func (a *AutopilotLoop) WithReclaimExpired(enabled bool) *AutopilotLoop {
+	a.reclaimExpired = enabled
+	return a
+}
+
+// checkExpiredClaims reports in-progress tasks whose lease lapsed and, with
+// reclaimExpired, returns those claimed by others to the backlog.
+func (a *AutopilotLoop) checkExpiredClaims(user string) error {
+	a.storeMu.Lock()
+	defer a.storeMu.Unlock()
+
+	now := time.Now()
+	expired, err := a.taskManager.ExpiredClaims(now, a.lease)
+	if err != nil || len(expired) == 0 {
+		return err
+	}
+
+	if !a.reclaimExpired || a.dryRun {
+		fmt.Printf("⏰ %d claim(s) expired without a heartbeat:\n", len(expired))
+		for _, t := range expired {
+			fmt.Printf("   [%s] %s (claimed by %s)\n", t.ID, t.Title, t.AssignedTo)
+		}
+		if a.dryRun {
+			fmt.Println("[DRY RUN] Would leave expired claims in place")
+		} else {
+			fmt.Println("💡 Tip: Use --reclaim-expired to return them to the backlog")
+		}
+		fmt.Println()
+		return nil
+	}
+
+	reclaimed, err := a.taskManager.ReclaimExpired(now, a.lease, false, func(t *models.Task) bool {
+		return t.AssignedTo != user
+	})
+	for _, t := range reclaimed {
+		fmt.Printf("↩️  Reclaimed [%s] %s from %s (lease expired)\n", t.ID, t.Title, t.AssignedTo)
+	}
+	if len(reclaimed) > 0 {
+		fmt.Println()
+	}
+	return err
+}
+
+// keepAlive renews the lease on a claimed task now and then every third of
+// the lease until the returned stop function is called.
+func (a *AutopilotLoop) keepAlive(taskID, user string) (stop func()) {
+	if a.dryRun {
+		return func() {}
+	}
+	return keepLeaseAlive(a.taskManager, &a.storeMu, taskID, user, a.lease)
+}
+
+// keepLeaseAlive renews the lease on taskID for user right away and then
+// every third of the lease until the returned stop function is called.
+// Renewals hold mu, when given, so they do not race other store updates.
+func keepLeaseAlive(tm *tasks.TaskManager, mu sync.Locker, taskID, user string, lease time.Duration) (stop func()) {
+	beat := func() {
+		if mu != nil {
+			mu.Lock()
+			defer mu.Unlock()
+		}
+		if _, err := tm.Heartbeat(taskID, user, lease); err != nil {
+			fmt.Printf("  ⚠️  Could not renew lease on %s: %v\n", taskID, err)
+		}
+	}
+	beat()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				beat()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// claimLease returns the configured lease, falling back to the default; an
+// invalid value is reported when a claim is attempted.
+func claimLease(cfg *models.Config) time.Duration {
+	lease, _ := tasks.ClaimLease(cfg)
+	return lease
+}
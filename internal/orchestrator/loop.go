@@ -2,65 +2,98 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/javierbenavides/agentic-agent/internal/agents"
+	"github.com/javierbenavides/agentic-agent/internal/config"
 	"github.com/javierbenavides/agentic-agent/internal/tasks"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
 )
 
 // RunLoop is the main entry point for the agent's autonomous loop.
-// For the MVP, this will just simulate the loop or run one step.
-func RunLoop(taskID string) error {
+// It claims the task if it is still in the backlog, then drives it with the
+// active agent until it is verified done, the stop signal appears, or
+// maxIterations is reached.
+func RunLoop(ctx context.Context, cfg *models.Config, taskID string, maxIterations int, stopSignal string) error {
 	fmt.Printf("Starting orchestrator for task %s...\n", taskID)
 
+	if cfg.ActiveAgent == "" {
+		return fmt.Errorf("no agent detected; pass --agent to choose one")
+	}
+
 	// 1. Load Task
 	tm := tasks.NewTaskManager(".agentic/tasks")
-	// Simplified loading... (logic duplicated from context build, should ideally be refactored to a helper)
-	// We'll assume the task is in progress for now
-	list, err := tm.LoadTasks("in-progress")
+	task, source, err := tm.FindTask(taskID)
 	if err != nil {
 		return err
 	}
+	if task == nil {
+		return fmt.Errorf("task %s not found", taskID)
+	}
 
-	found := false
-	for _, t := range list.Tasks {
-		if t.ID == taskID {
-			found = true
-			break
+	switch source {
+	case "done":
+		return fmt.Errorf("task %s is already done", taskID)
+	case "backlog":
+		user := os.Getenv("USER")
+		if user == "" {
+			user = "orchestrator"
+		}
+		if err := tm.ClaimTaskWithConfig(taskID, user, cfg); err != nil {
+			return fmt.Errorf("failed to claim task: %w", err)
+		}
+		fmt.Printf("Claimed task %s\n", taskID)
+		if err := NewStateStore(DefaultStateDir).Reset(taskID, cfg.ActiveAgent, "claimed from backlog"); err != nil {
+			return err
+		}
+		task, _, err = tm.FindTask(taskID)
+		if err != nil {
+			return err
 		}
 	}
-	if !found {
-		// Try backlog and move it?
-		return fmt.Errorf("task %s not found in in-progress list", taskID)
-	}
-
-	// 2. Initialize State Machine
-	sm := NewStateMachine(StateIdle)
 
-	// 3. Start Loop
-	// In a real agent, this would be an infinite loop waiting for LLM responses.
-	// For this CLI tool, we might just print the current state and what needs to happen.
-
-	// Simulate startup
-	fmt.Println("State: IDLE")
-	if err := sm.HandleEvent(EventTaskStarted); err != nil {
-		return err
-	}
-	fmt.Println("State: PLANNING")
-	fmt.Println(">> user should now create implementation_plan.md <<")
+	// 2. Drive the task with the active agent
+	loop := NewLoop(maxIterations, stopSignal, tm).
+		WithTask(task).
+		WithExecutor(agents.NewExecutorWithConfig(cfg.ActiveAgent, config.GetAgentOverride(cfg, cfg.ActiveAgent))).
+		WithAgentName(cfg.ActiveAgent).
+		WithStateStore(NewStateStore(DefaultStateDir)).
+		WithLease(task.AssignedTo, claimLease(cfg))
+	return loop.Run(ctx)
+}
 
-	// We could poll or look for file existence here...
-	time.Sleep(500 * time.Millisecond)
+// ErrStopped is returned by Run when the agent emits the stop signal, which
+// means it cannot make further progress; the task stays in progress.
+var ErrStopped = errors.New("agent stopped without completing the task")
 
-	return nil
-}
+// VerifyFunc checks an execution result and returns failure messages.
+// An empty result means the iteration's work is verified.
+type VerifyFunc func(ctx context.Context, task *models.Task, result *models.AgentExecutionResult) []string
 
 // Loop represents an autonomous agent loop with stop conditions
 type Loop struct {
 	maxIterations int
 	stopSignal    string
 	taskManager   *tasks.TaskManager
+	executor      agents.Executor
+	agentName     string
+	task          *models.Task
+	verify        VerifyFunc
+	stateMachine  *StateMachine
+	stateStore    *StateStore
+	tracker       *stateTracker
+	leaseHolder   string
+	lease         time.Duration
+
+	// Carried between iterations to build the next prompt
+	plan       string
+	lastOutput string
+	failures   []string
+	tokensUsed int
 }
 
 // NewLoop creates a new agent loop
@@ -75,28 +108,166 @@ func NewLoop(maxIterations int, stopSignal string, taskManager *tasks.TaskManage
 		maxIterations: maxIterations,
 		stopSignal:    stopSignal,
 		taskManager:   taskManager,
+		verify:        verifyCriteria,
+		stateMachine:  NewStateMachine(StateIdle),
 	}
 }
 
+// WithExecutor sets the agent executor used for each iteration.
+func (l *Loop) WithExecutor(executor agents.Executor) *Loop {
+	l.executor = executor
+	return l
+}
+
+// WithAgentName records the agent name used in completion learnings.
+func (l *Loop) WithAgentName(name string) *Loop {
+	l.agentName = name
+	return l
+}
+
+// WithTask sets the task the loop drives. Without one, the loop works on
+// the first in-progress task.
+func (l *Loop) WithTask(task *models.Task) *Loop {
+	l.task = task
+	return l
+}
+
+// WithStateStore persists the task's state, transition history and iteration
+// so an interrupted run resumes where it left off.
+func (l *Loop) WithStateStore(store *StateStore) *Loop {
+	l.stateStore = store
+	return l
+}
+
+// WithLease keeps the task's claim lease renewed for holder while Run is
+// executing, so a long run is not reclaimed as abandoned.
+func (l *Loop) WithLease(holder string, lease time.Duration) *Loop {
+	l.leaseHolder = holder
+	l.lease = lease
+	return l
+}
+
+// WithVerifier replaces the default acceptance-criteria verification.
+func (l *Loop) WithVerifier(verify VerifyFunc) *Loop {
+	l.verify = verify
+	return l
+}
+
+// State returns the loop's current state machine state.
+func (l *Loop) State() State {
+	return l.stateMachine.CurrentState
+}
+
 // Run executes the agent loop with stop condition detection
 func (l *Loop) Run(ctx context.Context) error {
-	for iteration := 1; iteration <= l.maxIterations; iteration++ {
-		// Run agent iteration (placeholder - actual implementation would call agent)
-		output, err := l.runIteration(ctx)
+	if l.task == nil {
+		if l.allTasksComplete() {
+			fmt.Println("All tasks completed. Nothing to run.")
+			return nil
+		}
+		task, err := l.firstInProgressTask()
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			return fmt.Errorf("no in-progress task to run; claim a task first")
+		}
+		l.task = task
+	}
+	if l.executor == nil {
+		return fmt.Errorf("no agent executor configured")
+	}
+	if l.lease > 0 && l.taskManager != nil {
+		stop := keepLeaseAlive(l.taskManager, nil, l.task.ID, l.leaseHolder, l.lease)
+		defer stop()
+	}
+
+	tracker, resumed, err := newStateTracker(l.stateStore, l.task.ID, l.actor())
+	if err != nil {
+		return err
+	}
+	l.tracker = tracker
+	l.stateMachine = tracker.sm
+	l.plan = tracker.state.Plan
+	start := tracker.state.Iteration + 1
+
+	if resumed {
+		fmt.Printf("Resuming task %s in %s after iteration %d\n", l.task.ID, l.stateMachine.CurrentState, tracker.state.Iteration)
+	}
+
+	switch l.stateMachine.CurrentState {
+	case StateIdle:
+		if err := l.transition(EventTaskStarted, "orchestrator started"); err != nil {
+			return err
+		}
+	case StateVerification:
+		// The previous session stopped before a verdict; redo the work
+		if err := l.transition(EventVerificationFail, "verification interrupted; resuming execution"); err != nil {
+			return err
+		}
+	case StateDone:
+		// Verified earlier but never moved to done
+		return l.completeTask(tracker.state.Iteration, nil)
+	}
+
+	last := start + l.maxIterations - 1
+	for iteration := start; iteration <= last; iteration++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		fmt.Printf("\n--- Iteration %d/%d [%s] ---\n", iteration, last, l.stateMachine.CurrentState)
+
+		prompt := l.buildPrompt(iteration)
+		result, err := l.runIteration(ctx, prompt)
 		if err != nil {
 			return fmt.Errorf("iteration %d failed: %w", iteration, err)
 		}
+		l.lastOutput = result.Output
+		l.tokensUsed += result.TokensUsed
+		if err := l.tracker.setIteration(iteration); err != nil {
+			return err
+		}
 
-		// Check for stop condition
-		if l.checkStopCondition(output) {
-			fmt.Printf("Stop condition detected at iteration %d\n", iteration)
-			return nil
+		if l.stateMachine.CurrentState == StatePlanning {
+			// The first response is the plan; execution starts next iteration
+			l.plan = result.Output
+			l.tracker.setPlan(l.plan)
+			if err := l.transition(EventPlanApproved, "plan recorded"); err != nil {
+				return err
+			}
+			fmt.Printf("Plan recorded (%d tokens). Moving to execution.\n", result.TokensUsed)
+			continue
 		}
 
-		// Check if all tasks completed
-		if l.allTasksComplete() {
-			fmt.Printf("All tasks completed at iteration %d\n", iteration)
-			return nil
+		// EXECUTION → VERIFICATION
+		if err := l.transition(EventWorkCompleted, ""); err != nil {
+			return err
+		}
+		l.failures = l.verify(ctx, l.task, result)
+
+		if len(l.failures) == 0 {
+			if err := l.transition(EventVerificationPass, "all acceptance criteria verified"); err != nil {
+				return err
+			}
+			fmt.Printf("Verification passed at iteration %d\n", iteration)
+			return l.completeTask(iteration, result.FilesModified)
+		}
+
+		if err := l.transition(EventVerificationFail, fmt.Sprintf("%d verification failure(s)", len(l.failures))); err != nil {
+			return err
+		}
+		fmt.Printf("Verification failed (%d issue(s)):\n", len(l.failures))
+		for _, f := range l.failures {
+			fmt.Printf("  - %s\n", f)
+		}
+
+		// Check for stop condition
+		if l.checkStopCondition(result.Output) {
+			fmt.Printf("Stop condition detected at iteration %d\n", iteration)
+			return fmt.Errorf("%w (stop signal at iteration %d)", ErrStopped, iteration)
 		}
 
 		fmt.Printf("Iteration %d complete. Continuing...\n", iteration)
@@ -105,22 +276,130 @@ func (l *Loop) Run(ctx context.Context) error {
 	return fmt.Errorf("reached max iterations (%d) without completion", l.maxIterations)
 }
 
-// runIteration runs a single iteration of the agent loop
-func (l *Loop) runIteration(ctx context.Context) (string, error) {
-	// Placeholder - in real implementation, this would:
-	// 1. Load next task from backlog
-	// 2. Spawn agent with task context
-	// 3. Capture agent output
-	// 4. Return output for stop condition checking
-
+// runIteration runs a single agent execution with the given prompt
+func (l *Loop) runIteration(ctx context.Context, prompt string) (*models.AgentExecutionResult, error) {
 	select {
 	case <-ctx.Done():
-		return "", ctx.Err()
+		return nil, ctx.Err()
 	default:
-		// Simulate agent work
-		time.Sleep(100 * time.Millisecond)
-		return "Agent iteration output", nil
 	}
+
+	result, err := l.executor.Execute(ctx, prompt, l.task)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("executor returned no result")
+	}
+	return result, nil
+}
+
+// buildPrompt assembles the prompt for the current phase, carrying the plan,
+// the previous output and any verification failures forward.
+func (l *Loop) buildPrompt(iteration int) string {
+	t := l.task
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("# Task %s: %s\n\n", t.ID, t.Title))
+	if t.Description != "" {
+		b.WriteString(t.Description + "\n\n")
+	}
+	if len(t.Scope) > 0 {
+		b.WriteString("## Scope\n")
+		for _, s := range t.Scope {
+			b.WriteString(fmt.Sprintf("- %s\n", s))
+		}
+		b.WriteString("\n")
+	}
+	if t.WorktreePath != "" {
+		b.WriteString(fmt.Sprintf("Work in the isolated worktree at %s.\n\n", t.WorktreePath))
+	}
+
+	if l.stateMachine.CurrentState == StatePlanning {
+		b.WriteString("## Phase: Planning\n")
+		b.WriteString("Outline a short, step-by-step implementation plan for this task. Do not implement it yet.\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("## Phase: Execution (iteration %d)\n", iteration))
+	if l.plan != "" {
+		b.WriteString("\n## Plan\n")
+		b.WriteString(l.plan + "\n")
+	}
+	if l.lastOutput != "" && l.lastOutput != l.plan {
+		b.WriteString("\n## Previous Iteration Output\n")
+		b.WriteString(l.lastOutput + "\n")
+	}
+	if len(l.failures) > 0 {
+		b.WriteString("\n## Verification Failures To Fix\n")
+		for _, f := range l.failures {
+			b.WriteString(fmt.Sprintf("- %s\n", f))
+		}
+	}
+	b.WriteString(fmt.Sprintf("\nImplement the plan. If you cannot make further progress, include %s in your response.\n", l.stopSignal))
+	return b.String()
+}
+
+// completeTask moves the verified task to done.
+func (l *Loop) completeTask(iteration int, filesModified []string) error {
+	if l.taskManager == nil {
+		return nil
+	}
+	learnings := []string{fmt.Sprintf("Completed by %s agent in %d iterations", l.actor(), iteration)}
+	if err := l.taskManager.CompleteTaskWithTracking(l.task.ID, learnings, filesModified, ""); err != nil {
+		return fmt.Errorf("verified but could not complete task: %w", err)
+	}
+	fmt.Printf("Task %s completed (tokens used: %d)\n", l.task.ID, l.tokensUsed)
+	return nil
+}
+
+// transition applies an event to the state machine and persists it.
+func (l *Loop) transition(event Event, reason string) error {
+	return l.tracker.fire(event, reason)
+}
+
+// actor names who drives the transitions in the recorded history.
+func (l *Loop) actor() string {
+	if l.agentName == "" {
+		return "orchestrator"
+	}
+	return l.agentName
+}
+
+// verifyCriteria is the default verifier: every acceptance criterion must be
+// met and the executor must report success.
+func verifyCriteria(_ context.Context, _ *models.Task, result *models.AgentExecutionResult) []string {
+	var failures []string
+	evidence := make(map[string]string, len(result.Criteria))
+	for _, c := range result.Criteria {
+		evidence[c.Criterion] = c.Evidence
+	}
+	for _, c := range result.CriteriaFailed {
+		msg := fmt.Sprintf("acceptance criterion not met: %s", c)
+		if e := evidence[c]; e != "" {
+			msg += fmt.Sprintf(" (%s)", e)
+		}
+		failures = append(failures, msg)
+	}
+	if result.ErrorMessage != "" {
+		failures = append(failures, result.ErrorMessage)
+	}
+	if !result.Success && len(failures) == 0 {
+		failures = append(failures, "agent did not report success")
+	}
+	return failures
+}
+
+// firstInProgressTask returns the first task in the in-progress list, if any.
+func (l *Loop) firstInProgressTask() (*models.Task, error) {
+	inProgress, err := l.taskManager.LoadTasks("in-progress")
+	if err != nil {
+		return nil, err
+	}
+	if len(inProgress.Tasks) == 0 {
+		return nil, nil
+	}
+	return &inProgress.Tasks[0], nil
 }
 
 // checkStopCondition checks if the output contains the stop signal
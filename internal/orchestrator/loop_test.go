@@ -145,45 +145,43 @@ func TestLoop_AllTasksComplete_InProgressTasks(t *testing.T) {
 
 func TestLoop_RunIteration(t *testing.T) {
 	tm := tasks.NewTaskManager("/tmp/tasks")
-	loop := NewLoop(10, "<stop>", tm)
+	loop := NewLoop(10, "<stop>", tm).
+		WithTask(&models.Task{ID: "T-1"}).
+		WithExecutor(&scriptedExecutor{outputs: []string{"Agent iteration output"}})
 
 	ctx := context.Background()
-	output, err := loop.runIteration(ctx)
+	result, err := loop.runIteration(ctx, "prompt")
 
 	require.NoError(t, err)
-	assert.NotEmpty(t, output)
+	assert.NotEmpty(t, result.Output)
 }
 
 func TestLoop_RunIteration_ContextCanceled(t *testing.T) {
 	tm := tasks.NewTaskManager("/tmp/tasks")
-	loop := NewLoop(10, "<stop>", tm)
+	loop := NewLoop(10, "<stop>", tm).WithExecutor(&scriptedExecutor{})
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
-	_, err := loop.runIteration(ctx)
+	_, err := loop.runIteration(ctx, "prompt")
 	assert.Error(t, err)
 	assert.Equal(t, context.Canceled, err)
 }
 
 func TestLoop_RunIteration_ContextTimeout(t *testing.T) {
 	tm := tasks.NewTaskManager("/tmp/tasks")
-	loop := NewLoop(10, "<stop>", tm)
+	loop := NewLoop(10, "<stop>", tm).WithExecutor(&scriptedExecutor{})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Nanosecond)
 	defer cancel()
 
 	time.Sleep(2 * time.Millisecond) // Ensure timeout
 
-	_, err := loop.runIteration(ctx)
+	_, err := loop.runIteration(ctx, "prompt")
 	assert.Error(t, err)
 	assert.Equal(t, context.DeadlineExceeded, err)
 }
 
-// Note: Testing Run with stop condition would require mocking or integration testing
-// since runIteration is a private method that simulates work.
-// The checkStopCondition method is tested separately above.
-
 func TestLoop_Run_AllTasksComplete(t *testing.T) {
 	tmpDir := t.TempDir()
 	tasksDir := filepath.Join(tmpDir, "tasks")
@@ -214,36 +212,190 @@ func TestLoop_Run_AllTasksComplete(t *testing.T) {
 }
 
 func TestLoop_Run_MaxIterationsReached(t *testing.T) {
-	tmpDir := t.TempDir()
-	tasksDir := filepath.Join(tmpDir, "tasks")
-	err := os.MkdirAll(tasksDir, 0755)
-	require.NoError(t, err)
+	tasksDir := setupLoopTasks(t, models.Task{
+		ID: "US-001", Title: "Endless task", Status: models.StatusInProgress, Acceptance: []string{"never"},
+	})
 
-	// Create backlog with tasks (so it never completes)
-	backlogTasks := tasks.TaskList{
-		Tasks: []models.Task{
-			{ID: "US-001", Title: "Endless task", Status: "backlog"},
-		},
+	tm := tasks.NewTaskManager(tasksDir)
+	exec := &scriptedExecutor{failCriteria: true}
+	loop := NewLoop(3, "<stop>", tm).WithExecutor(exec) // Only 3 iterations
+
+	ctx := context.Background()
+	err := loop.Run(ctx)
+
+	// Should error with max iterations message
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reached max iterations")
+	assert.Contains(t, err.Error(), "3")
+	assert.Len(t, exec.prompts, 3)
+}
+
+func TestLoop_Run_DrivesTaskToDone(t *testing.T) {
+	task := models.Task{ID: "US-001", Title: "Add endpoint", Status: models.StatusInProgress, Acceptance: []string{"works"}}
+	tasksDir := setupLoopTasks(t, task)
+	tm := tasks.NewTaskManager(tasksDir)
+
+	// Plan, then a failed attempt, then success
+	exec := &scriptedExecutor{
+		outputs:    []string{"1. add handler", "attempt one", "attempt two"},
+		failFirstN: 2,
 	}
-	backlogData, err := yaml.Marshal(backlogTasks)
-	require.NoError(t, err)
-	err = os.WriteFile(filepath.Join(tasksDir, "backlog.yaml"), backlogData, 0644)
+	loop := NewLoop(5, "", tm).WithTask(&task).WithExecutor(exec)
+
+	require.NoError(t, loop.Run(context.Background()))
+	assert.Equal(t, StateDone, loop.State())
+	require.Len(t, exec.prompts, 3)
+
+	assert.Contains(t, exec.prompts[0], "Phase: Planning")
+	assert.Contains(t, exec.prompts[1], "1. add handler")
+	// The second execution sees the previous output and the failure
+	assert.Contains(t, exec.prompts[2], "attempt one")
+	assert.Contains(t, exec.prompts[2], "acceptance criterion not met: works")
+
+	done, err := tm.LoadTasks("done")
 	require.NoError(t, err)
+	require.Len(t, done.Tasks, 1)
+	assert.Equal(t, "US-001", done.Tasks[0].ID)
+}
 
-	emptyList := tasks.TaskList{Tasks: []models.Task{}}
-	inProgressData, err := yaml.Marshal(emptyList)
+func TestLoop_Run_StopSignal(t *testing.T) {
+	task := models.Task{ID: "US-001", Status: models.StatusInProgress, Acceptance: []string{"works"}}
+	tasksDir := setupLoopTasks(t, task)
+	tm := tasks.NewTaskManager(tasksDir)
+
+	exec := &scriptedExecutor{outputs: []string{"plan", "giving up <stop>"}, failCriteria: true}
+	loop := NewLoop(10, "<stop>", tm).WithTask(&task).WithExecutor(exec)
+
+	err := loop.Run(context.Background())
+	require.ErrorIs(t, err, ErrStopped)
+	assert.Len(t, exec.prompts, 2)
+	assert.Equal(t, StateExecution, loop.State())
+
+	// Task is left in progress
+	inProgress, _ := tm.LoadTasks("in-progress")
+	assert.Len(t, inProgress.Tasks, 1)
+}
+
+func TestLoop_Run_NoInProgressTask(t *testing.T) {
+	tasksDir := setupLoopTasks(t)
+	tm := tasks.NewTaskManager(tasksDir)
+	require.NoError(t, tm.SaveTasks("backlog", &tasks.TaskList{Tasks: []models.Task{{ID: "US-009"}}}))
+
+	err := NewLoop(3, "", tm).WithExecutor(&scriptedExecutor{}).Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no in-progress task")
+}
+
+func TestLoop_Run_ResumesPersistedState(t *testing.T) {
+	task := models.Task{ID: "US-001", Title: "Add endpoint", Status: models.StatusInProgress, Acceptance: []string{"works"}}
+	tasksDir := setupLoopTasks(t, task)
+	tm := tasks.NewTaskManager(tasksDir)
+	store := NewStateStore(t.TempDir())
+
+	// First session plans, fails once, then is cut off by the iteration cap
+	first := &scriptedExecutor{outputs: []string{"1. add handler"}, failCriteria: true}
+	err := NewLoop(2, "", tm).WithTask(&task).WithExecutor(first).WithStateStore(store).Run(context.Background())
+	require.Error(t, err)
+
+	saved, err := store.Load("US-001")
 	require.NoError(t, err)
-	err = os.WriteFile(filepath.Join(tasksDir, "in-progress.yaml"), inProgressData, 0644)
+	assert.Equal(t, StateExecution, saved.State)
+	assert.Equal(t, 2, saved.Iteration)
+	assert.Equal(t, "1. add handler", saved.Plan)
+
+	// Second session skips planning and continues the iteration count
+	second := &scriptedExecutor{}
+	loop := NewLoop(3, "", tm).WithTask(&task).WithExecutor(second).WithStateStore(store)
+	require.NoError(t, loop.Run(context.Background()))
+	require.Len(t, second.prompts, 1)
+	assert.Contains(t, second.prompts[0], "iteration 3")
+	assert.Contains(t, second.prompts[0], "1. add handler")
+
+	final, err := store.Load("US-001")
 	require.NoError(t, err)
+	assert.Equal(t, StateDone, final.State)
+	var events []Event
+	for _, tr := range final.History {
+		events = append(events, tr.Event)
+	}
+	assert.Equal(t, []Event{
+		EventTaskStarted, EventPlanApproved, EventWorkCompleted, EventVerificationFail,
+		EventWorkCompleted, EventVerificationPass,
+	}, events)
+}
 
+func TestLoop_Run_KeepsLeaseAlive(t *testing.T) {
+	task := models.Task{ID: "US-001", Status: models.StatusInProgress, AssignedTo: "tester", ClaimedAt: time.Now()}
+	tasksDir := setupLoopTasks(t, task)
 	tm := tasks.NewTaskManager(tasksDir)
-	loop := NewLoop(3, "<stop>", tm) // Only 3 iterations
 
-	ctx := context.Background()
-	err = loop.Run(ctx)
+	exec := &leaseWatchingExecutor{tm: tm}
+	loop := NewLoop(3, "", tm).WithTask(&task).WithExecutor(exec).WithLease("tester", 30*time.Millisecond)
 
-	// Should error with max iterations message
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "reached max iterations")
-	assert.Contains(t, err.Error(), "3")
+	require.NoError(t, loop.Run(context.Background()))
+	assert.True(t, exec.renewedOnStart, "the lease is renewed when the run starts")
+	assert.True(t, exec.renewedDuring, "the lease is renewed while the agent works")
+}
+
+// leaseWatchingExecutor holds its first execution until the task's lease has
+// been renewed again, then succeeds.
+type leaseWatchingExecutor struct {
+	tm             *tasks.TaskManager
+	calls          int
+	renewedOnStart bool
+	renewedDuring  bool
+}
+
+func (e *leaseWatchingExecutor) Execute(ctx context.Context, prompt string, task *models.Task) (*models.AgentExecutionResult, error) {
+	e.calls++
+	if e.calls == 1 {
+		first, _, err := e.tm.FindTask(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		e.renewedOnStart = !first.HeartbeatAt.IsZero()
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			current, _, err := e.tm.FindTask(task.ID)
+			if err == nil && current.HeartbeatAt.After(first.HeartbeatAt) {
+				e.renewedDuring = true
+				break
+			}
+		}
+	}
+	return &models.AgentExecutionResult{Output: "output", Success: true}, nil
+}
+
+// scriptedExecutor returns canned outputs and records the prompts it receives.
+type scriptedExecutor struct {
+	outputs      []string
+	failCriteria bool // fail acceptance on every execution
+	failFirstN   int  // fail acceptance on the first N calls
+	prompts      []string
+}
+
+func (e *scriptedExecutor) Execute(ctx context.Context, prompt string, task *models.Task) (*models.AgentExecutionResult, error) {
+	e.prompts = append(e.prompts, prompt)
+	call := len(e.prompts)
+
+	output := "output"
+	if call <= len(e.outputs) {
+		output = e.outputs[call-1]
+	}
+
+	if e.failCriteria || call <= e.failFirstN {
+		return &models.AgentExecutionResult{Output: output, CriteriaFailed: task.Acceptance}, nil
+	}
+	return &models.AgentExecutionResult{Output: output, Success: true, CriteriaMet: task.Acceptance, TokensUsed: 10}, nil
+}
+
+// setupLoopTasks writes the given tasks to an in-progress list in a temp dir.
+func setupLoopTasks(t *testing.T, inProgress ...models.Task) string {
+	t.Helper()
+	tasksDir := filepath.Join(t.TempDir(), "tasks")
+	require.NoError(t, os.MkdirAll(tasksDir, 0755))
+
+	data, err := yaml.Marshal(tasks.TaskList{Tasks: inProgress})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tasksDir, "in-progress.yaml"), data, 0644))
+	return tasksDir
 }
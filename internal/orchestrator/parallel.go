@@ -0,0 +1,406 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/internal/agents"
+	appcontext "github.com/javierbenavides/agentic-agent/internal/context"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// WithParallel sets how many tasks autopilot works on at once. Each worker
+// drives one claimed task in that task's own worktree. Values below 2 keep
+// the sequential loop.
+func (a *AutopilotLoop) WithParallel(workers int) *AutopilotLoop {
+	a.parallel = workers
+	return a
+}
+
+type workerOutcome string
+
+const (
+	outcomeDone    workerOutcome = "done"
+	outcomeFailed  workerOutcome = "failed"
+	outcomeReady   workerOutcome = "ready"
+	outcomeSkipped workerOutcome = "skipped"
+)
+
+// workerResult is what a worker reports when it finishes its task.
+type workerResult struct {
+	taskID  string
+	outcome workerOutcome
+	tokens  int
+	detail  string
+}
+
+// workerEvent is a progress message from a running worker.
+type workerEvent struct {
+	taskID  string
+	message string
+}
+
+// worker tracks a running task so the dispatcher can avoid scope overlaps
+// and cancel it.
+type worker struct {
+	scope  []string
+	cancel context.CancelFunc
+}
+
+// parallelSummary aggregates worker progress for the live status line.
+type parallelSummary struct {
+	workers int
+	running int
+	tokens  int
+	counts  map[workerOutcome]int
+	results []workerResult
+}
+
+func newParallelSummary(workers int) *parallelSummary {
+	return &parallelSummary{workers: workers, counts: make(map[workerOutcome]int)}
+}
+
+func (s *parallelSummary) finish(res workerResult) {
+	s.running--
+	s.tokens += res.tokens
+	s.counts[res.outcome]++
+	s.results = append(s.results, res)
+}
+
+// line renders the one-line live summary.
+func (s *parallelSummary) line() string {
+	return fmt.Sprintf("📊 running %d/%d | done %d | failed %d | ready %d | skipped %d | tokens %d",
+		s.running, s.workers, s.counts[outcomeDone], s.counts[outcomeFailed],
+		s.counts[outcomeReady], s.counts[outcomeSkipped], s.tokens)
+}
+
+// report renders the final per-task table.
+func (s *parallelSummary) report() string {
+	var b strings.Builder
+	b.WriteString("\nParallel autopilot summary\n")
+	results := append([]workerResult(nil), s.results...)
+	sort.Slice(results, func(i, j int) bool { return results[i].taskID < results[j].taskID })
+	for _, r := range results {
+		b.WriteString(fmt.Sprintf("  %-12s %-8s", r.taskID, r.outcome))
+		if r.detail != "" {
+			b.WriteString("  " + r.detail)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(s.line() + "\n")
+	return b.String()
+}
+
+// runParallel dispatches tasks to up to a.parallel concurrent workers.
+// Tasks whose Scope overlaps a running task wait for it to finish; task
+// store updates from workers are serialized through storeMu.
+func (a *AutopilotLoop) runParallel(ctx context.Context, user string) error {
+	events := make(chan workerEvent, 16)
+	results := make(chan workerResult)
+	active := make(map[string]*worker)
+	attempted := make(map[string]bool)
+	summary := newParallelSummary(a.parallel)
+
+	done := ctx.Done()
+	started := 0
+	var pickErr error
+
+	for {
+		// Fill free slots with tasks independent of everything running
+		waiting := false
+		for ctx.Err() == nil && len(active) < a.parallel && started < a.maxIterations {
+			task, resume, wait, err := a.nextIndependentTask(user, active, attempted)
+			pickErr = err
+			waiting = wait
+			if err != nil || task == nil {
+				break
+			}
+
+			wctx, cancel := context.WithCancel(ctx)
+			active[task.ID] = &worker{scope: task.Scope, cancel: cancel}
+			attempted[task.ID] = true
+			started++
+			summary.running++
+			if resume {
+				fmt.Printf("▶️  [%s] resuming %s\n", task.ID, task.Title)
+			} else {
+				fmt.Printf("▶️  [%s] starting %s\n", task.ID, task.Title)
+			}
+			fmt.Println(summary.line())
+
+			go func(t models.Task, resume bool) {
+				results <- a.runWorker(wctx, t, user, resume, events)
+			}(*task, resume)
+		}
+
+		if len(active) == 0 {
+			if !waiting {
+				break
+			}
+			// Prerequisites are being worked on outside this session
+			fmt.Printf("Remaining backlog tasks wait on dependencies still in progress; checking again in %s\n", a.pollInterval)
+			select {
+			case <-ctx.Done():
+			case <-time.After(a.pollInterval):
+			}
+			continue
+		}
+
+		select {
+		case ev := <-events:
+			fmt.Printf("   [%s] %s\n", ev.taskID, ev.message)
+		case res := <-results:
+			active[res.taskID].cancel()
+			delete(active, res.taskID)
+			summary.finish(res)
+			icon := "✅"
+			if res.outcome == outcomeFailed || res.outcome == outcomeSkipped {
+				icon = "⚠️ "
+			}
+			fmt.Printf("%s [%s] %s %s\n", icon, res.taskID, res.outcome, res.detail)
+			fmt.Println(summary.line())
+		case <-done:
+			fmt.Println("Cancelling running workers...")
+			for _, w := range active {
+				w.cancel()
+			}
+			done = nil // keep draining results until every worker exits
+		}
+	}
+
+	fmt.Print(summary.report())
+
+	if ctx.Err() != nil {
+		fmt.Println("Autopilot cancelled.")
+		return ctx.Err()
+	}
+	if pickErr != nil {
+		return pickErr
+	}
+	if started >= a.maxIterations {
+		fmt.Printf("Reached max iterations (%d). Stopping autopilot.\n", a.maxIterations)
+		return nil
+	}
+	fmt.Println("All tasks complete. Autopilot finished.")
+	return nil
+}
+
+// nextIndependentTask returns an interrupted task to resume or the next
+// backlog task, skipping tasks already attempted this session and tasks
+// whose scope overlaps a running worker. It reports waiting when no task is
+// ready yet but some wait on prerequisites that are still in progress.
+func (a *AutopilotLoop) nextIndependentTask(user string, active map[string]*worker, attempted map[string]bool) (task *models.Task, resume, waiting bool, err error) {
+	eligible := func(t *models.Task) bool {
+		if attempted[t.ID] {
+			return false
+		}
+		for _, w := range active {
+			if scopesOverlap(t.Scope, w.scope) {
+				return false
+			}
+		}
+		return true
+	}
+
+	a.storeMu.Lock()
+	defer a.storeMu.Unlock()
+
+	task, err = a.findResumableTask(user, eligible)
+	if err != nil || task != nil {
+		return task, task != nil, false, err
+	}
+
+	backlog, err := a.taskManager.LoadTasks("backlog")
+	if err != nil {
+		return nil, false, false, err
+	}
+	task, err = a.pickTask(backlog.Tasks, eligible)
+	if !errors.Is(err, errDependenciesPending) {
+		return task, false, false, err
+	}
+
+	inProgress, loadErr := a.taskManager.LoadTasks("in-progress")
+	if loadErr != nil {
+		return nil, false, false, loadErr
+	}
+	// Tasks this session gave up on will not finish by themselves
+	var running []models.Task
+	for _, t := range inProgress.Tasks {
+		if _, ok := active[t.ID]; ok || !attempted[t.ID] {
+			running = append(running, t)
+		}
+	}
+	if waitsOnInProgress(backlog.Tasks, running) {
+		return nil, false, true, nil
+	}
+	return nil, false, false, err
+}
+
+// runWorker claims (unless resuming) and executes a single task. Progress is
+// sent on events; the final result is returned.
+func (a *AutopilotLoop) runWorker(ctx context.Context, task models.Task, user string, resume bool, events chan<- workerEvent) workerResult {
+	report := func(format string, args ...interface{}) {
+		events <- workerEvent{taskID: task.ID, message: fmt.Sprintf(format, args...)}
+	}
+	res := workerResult{taskID: task.ID}
+
+	claimed, err := a.claimForWorker(task.ID, user, resume)
+	if err != nil {
+		res.outcome = outcomeSkipped
+		res.detail = err.Error()
+		return res
+	}
+	stopLease := a.keepAlive(claimed.ID, user)
+	defer stopLease()
+	if !resume {
+		report("claimed (worktree: %s)", claimed.WorktreePath)
+
+		for _, dir := range claimed.Scope {
+			dirCtx, err := appcontext.GenerateContextWithConfig(dir, a.cfg)
+			if err == nil {
+				_, err = appcontext.NewDirectoryContextManager(dir).UpdateContext(dir, dirCtx)
+			}
+			if err != nil {
+				report("warning: context for %s: %v", dir, err)
+			}
+		}
+	}
+
+	executor := a.workerExecutor()
+	if !a.executeAgent || executor == nil {
+		res.outcome = outcomeReady
+		res.detail = "ready for agent execution"
+		return res
+	}
+
+	tracker, _, err := newStateTracker(a.stateStore, claimed.ID, a.cfg.ActiveAgent)
+	if err == nil && tracker.sm.CurrentState != StateDone {
+		err = enterExecution(tracker)
+	}
+	if err != nil {
+		res.outcome = outcomeFailed
+		res.detail = fmt.Sprintf("could not record task state: %v", err)
+		return res
+	}
+
+	var filesModified []string
+	if tracker.sm.CurrentState != StateDone {
+		report("executing %s agent", a.cfg.ActiveAgent)
+		prompt := fmt.Sprintf("Complete task %s: %s\n\n%s", claimed.ID, claimed.Title, claimed.Description)
+		if claimed.WorktreePath != "" {
+			prompt += fmt.Sprintf("\n\nWork in the isolated worktree at %s.", claimed.WorktreePath)
+		}
+
+		result, err := executor.Execute(ctx, prompt, claimed)
+		if err != nil {
+			res.outcome = outcomeFailed
+			res.detail = err.Error()
+			return res
+		}
+		res.tokens = result.TokensUsed
+
+		iterErr := tracker.setIteration(tracker.state.Iteration + 1)
+		if iterErr == nil {
+			iterErr = tracker.fire(EventWorkCompleted, "")
+		}
+		if iterErr != nil {
+			report("warning: could not record task state: %v", iterErr)
+		}
+
+		if !result.Success {
+			reason := fmt.Sprintf("%d/%d criteria met", len(result.CriteriaMet), len(claimed.Acceptance))
+			if err := tracker.fire(EventVerificationFail, reason); err != nil {
+				report("warning: could not record task state: %v", err)
+			}
+			res.outcome = outcomeFailed
+			res.detail = reason
+			return res
+		}
+		if err := tracker.fire(EventVerificationPass, "all acceptance criteria met"); err != nil {
+			report("warning: could not record task state: %v", err)
+		}
+		filesModified = result.FilesModified
+	}
+
+	stopLease()
+	a.storeMu.Lock()
+	learnings := []string{fmt.Sprintf("Completed by %s agent in parallel autopilot", a.cfg.ActiveAgent)}
+	err = a.taskManager.CompleteTaskWithTracking(claimed.ID, learnings, filesModified, "")
+	a.storeMu.Unlock()
+	if err != nil {
+		res.outcome = outcomeFailed
+		res.detail = fmt.Sprintf("could not complete task: %v", err)
+		return res
+	}
+	if err := a.checkpointMgr.DeleteAll(claimed.ID); err != nil {
+		report("warning: could not clean up checkpoints: %v", err)
+	}
+
+	res.outcome = outcomeDone
+	return res
+}
+
+// claimForWorker claims a backlog task (or looks up a resumed one) while
+// holding the store mutex, and returns the task as stored in-progress.
+func (a *AutopilotLoop) claimForWorker(taskID, user string, resume bool) (*models.Task, error) {
+	a.storeMu.Lock()
+	defer a.storeMu.Unlock()
+
+	if !resume {
+		if err := a.taskManager.ClaimTaskWithConfig(taskID, user, a.cfg); err != nil {
+			return nil, fmt.Errorf("could not claim: %w", err)
+		}
+		if err := a.stateStore.Reset(taskID, user, "claimed from backlog"); err != nil {
+			return nil, err
+		}
+	}
+
+	task, _, err := a.taskManager.FindTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("task %s not found after claim", taskID)
+	}
+	return task, nil
+}
+
+// workerExecutor returns a dedicated executor for one worker, falling back
+// to the shared executor when no factory is configured.
+func (a *AutopilotLoop) workerExecutor() agents.Executor {
+	if a.newExecutor != nil {
+		return a.newExecutor()
+	}
+	return a.executor
+}
+
+// scopesOverlap reports whether two task scopes could touch the same files.
+// An empty scope is treated as the whole repository.
+func scopesOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, x := range a {
+		for _, y := range b {
+			if pathsOverlap(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathsOverlap reports whether one path equals or contains the other.
+func pathsOverlap(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if a == b || a == "." || b == "." {
+		return true
+	}
+	sep := string(filepath.Separator)
+	return strings.HasPrefix(a, b+sep) || strings.HasPrefix(b, a+sep)
+}
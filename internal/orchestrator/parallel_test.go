@@ -0,0 +1,221 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/internal/checkpoint"
+	"github.com/javierbenavides/agentic-agent/internal/tasks"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopesOverlap(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want bool
+	}{
+		{[]string{"internal/api"}, []string{"internal/db"}, false},
+		{[]string{"internal/api"}, []string{"internal/api"}, true},
+		{[]string{"internal"}, []string{"internal/api/handlers"}, true},
+		{[]string{"internal/api"}, []string{"internal/apix"}, false},
+		{[]string{"cmd", "internal/api/"}, []string{"internal/api"}, true},
+		{nil, []string{"internal/api"}, true},
+		{[]string{"."}, []string{"docs"}, true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, scopesOverlap(tt.a, tt.b), "%v vs %v", tt.a, tt.b)
+	}
+}
+
+func TestAutopilotLoop_ParallelRunsIndependentTasks(t *testing.T) {
+	loop, tasksDir, base := setupParallelAutopilot(t, []models.Task{
+		{ID: "T-1", Title: "API", Scope: []string{"internal/api"}},
+		{ID: "T-2", Title: "DB", Scope: []string{"internal/db"}},
+		{ID: "T-3", Title: "CLI", Scope: []string{"cmd"}},
+	})
+	exec := newConcurrencyExecutor(3)
+	loop.executor = exec
+	loop.WithParallel(3)
+
+	require.NoError(t, loop.Run(context.Background()))
+	assert.Equal(t, 3, exec.maxSeen())
+
+	done, err := tasks.NewTaskManager(tasksDir).LoadTasks("done")
+	require.NoError(t, err)
+	assert.Len(t, done.Tasks, 3)
+
+	// Every task's state was recorded independently
+	store := NewStateStore(filepath.Join(base, ".agentic", "state"))
+	for _, id := range []string{"T-1", "T-2", "T-3"} {
+		ts, err := store.Load(id)
+		require.NoError(t, err)
+		assert.Equal(t, StateDone, ts.State, id)
+	}
+}
+
+func TestAutopilotLoop_ParallelSerializesOverlappingScopes(t *testing.T) {
+	loop, tasksDir, _ := setupParallelAutopilot(t, []models.Task{
+		{ID: "T-1", Title: "Parent", Scope: []string{"internal"}},
+		{ID: "T-2", Title: "Child", Scope: []string{"internal/api"}},
+		{ID: "T-3", Title: "Anywhere"},
+	})
+	exec := newConcurrencyExecutor(2)
+	loop.executor = exec
+	loop.WithParallel(2)
+
+	require.NoError(t, loop.Run(context.Background()))
+	assert.Equal(t, 1, exec.maxSeen())
+
+	done, err := tasks.NewTaskManager(tasksDir).LoadTasks("done")
+	require.NoError(t, err)
+	assert.Len(t, done.Tasks, 3)
+}
+
+func TestAutopilotLoop_ParallelWaitsForDependencies(t *testing.T) {
+	loop, tasksDir, _ := setupParallelAutopilot(t, []models.Task{
+		{ID: "T-1", Title: "First", Scope: []string{"a"}},
+		{ID: "T-2", Title: "Second", Scope: []string{"b"}, DependsOn: []string{"T-1"}},
+	})
+	exec := newConcurrencyExecutor(2)
+	loop.executor = exec
+	loop.WithParallel(2)
+
+	require.NoError(t, loop.Run(context.Background()))
+	assert.Equal(t, 1, exec.maxSeen())
+
+	done, err := tasks.NewTaskManager(tasksDir).LoadTasks("done")
+	require.NoError(t, err)
+	require.Len(t, done.Tasks, 2)
+	assert.Equal(t, "T-1", done.Tasks[0].ID)
+}
+
+func TestAutopilotLoop_ParallelWaitsForInProgressDependencies(t *testing.T) {
+	loop, tasksDir, _ := setupParallelAutopilot(t, []models.Task{
+		{ID: "T-2", Title: "Needs T-1", Scope: []string{"b"}, DependsOn: []string{"T-1"}},
+	})
+	writeTasksFile(t, tasksDir, "in-progress", tasks.TaskList{
+		Tasks: []models.Task{{ID: "T-1", Title: "Foundation", Status: models.StatusInProgress, AssignedTo: "other"}},
+	})
+	loop.executor = newConcurrencyExecutor(1)
+	loop.pollInterval = 10 * time.Millisecond
+	loop.WithParallel(2)
+
+	// T-1 is finished by someone else while autopilot waits
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = tasks.NewTaskManager(tasksDir).MoveTask("T-1", "in-progress", "done", models.StatusDone)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, loop.Run(ctx))
+
+	done, err := tasks.NewTaskManager(tasksDir).LoadTasks("done")
+	require.NoError(t, err)
+	require.Len(t, done.Tasks, 2)
+	assert.Equal(t, "T-2", done.Tasks[1].ID)
+}
+
+func TestAutopilotLoop_ParallelCancellation(t *testing.T) {
+	loop, _, _ := setupParallelAutopilot(t, []models.Task{
+		{ID: "T-1", Scope: []string{"a"}},
+		{ID: "T-2", Scope: []string{"b"}},
+	})
+	loop.executor = &blockingExecutor{}
+	loop.WithParallel(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := loop.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// setupParallelAutopilot creates a backlog in a temp dir whose name makes
+// claims use synthetic worktree paths.
+func setupParallelAutopilot(t *testing.T, backlog []models.Task) (*AutopilotLoop, string, string) {
+	t.Helper()
+	base, err := os.MkdirTemp("", "agentic-test-parallel-*")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(base) })
+	t.Setenv("USER", "tester")
+
+	_, cfg := setupAutopilotTestDir(t)
+	tasksDir := filepath.Join(base, ".agentic", "tasks")
+	require.NoError(t, os.MkdirAll(tasksDir, 0755))
+
+	for i := range backlog {
+		backlog[i].Status = models.StatusPending
+		backlog[i].Acceptance = []string{"works"}
+		for j, s := range backlog[i].Scope {
+			backlog[i].Scope[j] = filepath.Join(base, s)
+		}
+	}
+	writeTasksFile(t, tasksDir, "backlog", tasks.TaskList{Tasks: backlog})
+	writeTasksFile(t, tasksDir, "in-progress", tasks.TaskList{})
+
+	loop := NewAutopilotLoop(cfg, 10, "", false)
+	loop.taskManager = tasks.NewTaskManager(tasksDir)
+	loop.checkpointMgr = checkpoint.NewManager(filepath.Join(base, ".agentic", "checkpoints"))
+	loop.stateStore = NewStateStore(filepath.Join(base, ".agentic", "state"))
+	loop.executeAgent = true
+	return loop, tasksDir, base
+}
+
+// concurrencyExecutor succeeds after waiting briefly for up to `want`
+// executions to overlap, and records the highest concurrency seen.
+type concurrencyExecutor struct {
+	mu       sync.Mutex
+	want     int
+	inFlight int
+	max      int
+}
+
+func newConcurrencyExecutor(want int) *concurrencyExecutor {
+	return &concurrencyExecutor{want: want}
+}
+
+func (e *concurrencyExecutor) Execute(ctx context.Context, prompt string, task *models.Task) (*models.AgentExecutionResult, error) {
+	e.mu.Lock()
+	e.inFlight++
+	if e.inFlight > e.max {
+		e.max = e.inFlight
+	}
+	e.mu.Unlock()
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		e.mu.Lock()
+		reached := e.max >= e.want
+		e.mu.Unlock()
+		if reached {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	e.mu.Lock()
+	e.inFlight--
+	e.mu.Unlock()
+	return &models.AgentExecutionResult{Success: true, Output: "done", CriteriaMet: task.Acceptance, TokensUsed: 5}, nil
+}
+
+func (e *concurrencyExecutor) maxSeen() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.max
+}
+
+// blockingExecutor waits until its context is cancelled.
+type blockingExecutor struct{}
+
+func (blockingExecutor) Execute(ctx context.Context, prompt string, task *models.Task) (*models.AgentExecutionResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
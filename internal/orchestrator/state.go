@@ -1,5 +1,7 @@
 package orchestrator
 
+import "time"
+
 type State string
 
 const (
@@ -18,10 +20,25 @@ const (
 	EventWorkCompleted    Event = "WORK_COMPLETED"
 	EventVerificationPass Event = "VERIFICATION_PASS"
 	EventVerificationFail Event = "VERIFICATION_FAIL"
+
+	// EventReset is recorded in a task's history when it is claimed again;
+	// the state machine itself never accepts it.
+	EventReset Event = "RESET"
 )
 
 type StateMachine struct {
 	CurrentState State
+	History      []Transition
+}
+
+// Transition is one recorded state change.
+type Transition struct {
+	Event     Event     `json:"event"`
+	From      State     `json:"from"`
+	To        State     `json:"to"`
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
 }
 
 func NewStateMachine(initial State) *StateMachine {
@@ -0,0 +1,168 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultStateDir is where per-task orchestrator state is persisted.
+const DefaultStateDir = ".agentic/state"
+
+// TaskState is the persisted orchestrator state of a single task.
+type TaskState struct {
+	TaskID    string       `json:"task_id"`
+	State     State        `json:"state"`
+	Iteration int          `json:"iteration"`
+	Plan      string       `json:"plan,omitempty"`
+	History   []Transition `json:"history"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// StateMachine returns a state machine positioned at the persisted state.
+func (ts *TaskState) StateMachine() *StateMachine {
+	sm := NewStateMachine(ts.State)
+	sm.History = append([]Transition(nil), ts.History...)
+	return sm
+}
+
+// StateStore reads and writes TaskState files, one JSON file per task.
+type StateStore struct {
+	dir string
+}
+
+// NewStateStore creates a state store rooted at dir.
+func NewStateStore(dir string) *StateStore {
+	if dir == "" {
+		dir = DefaultStateDir
+	}
+	return &StateStore{dir: dir}
+}
+
+// Load returns the persisted state for taskID, or nil if none exists.
+func (s *StateStore) Load(taskID string) (*TaskState, error) {
+	data, err := os.ReadFile(s.path(taskID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state for %s: %w", taskID, err)
+	}
+
+	var ts TaskState
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return nil, fmt.Errorf("failed to parse state for %s: %w", taskID, err)
+	}
+	return &ts, nil
+}
+
+// Save writes the state for a task, replacing any previous file atomically.
+func (s *StateStore) Save(ts *TaskState) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	ts.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(ts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "."+ts.TaskID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(ts.TaskID)); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
+
+// Reset returns a task to IDLE when it is claimed afresh, keeping its history.
+// The reset itself is recorded so the timeline stays continuous.
+func (s *StateStore) Reset(taskID, actor, reason string) error {
+	ts, err := s.Load(taskID)
+	if err != nil || ts == nil || ts.State == StateIdle {
+		return err
+	}
+	ts.History = append(ts.History, Transition{
+		Event:     EventReset,
+		From:      ts.State,
+		To:        StateIdle,
+		Timestamp: time.Now(),
+		Actor:     actor,
+		Reason:    reason,
+	})
+	ts.State = StateIdle
+	ts.Iteration = 0
+	ts.Plan = ""
+	return s.Save(ts)
+}
+
+func (s *StateStore) path(taskID string) string {
+	return filepath.Join(s.dir, taskID+".json")
+}
+
+// stateTracker ties a state machine to its persisted TaskState so every
+// transition and iteration is recorded. A nil store keeps state in memory.
+type stateTracker struct {
+	store *StateStore
+	state *TaskState
+	sm    *StateMachine
+	actor string
+}
+
+// newStateTracker loads the persisted state for taskID, or starts at IDLE.
+// resumed reports whether an earlier session left the task mid-flight.
+func newStateTracker(store *StateStore, taskID, actor string) (tracker *stateTracker, resumed bool, err error) {
+	ts := &TaskState{TaskID: taskID, State: StateIdle}
+	if store != nil {
+		loaded, err := store.Load(taskID)
+		if err != nil {
+			return nil, false, err
+		}
+		if loaded != nil {
+			ts = loaded
+			resumed = ts.State != StateIdle
+		}
+	}
+	return &stateTracker{store: store, state: ts, sm: ts.StateMachine(), actor: actor}, resumed, nil
+}
+
+// fire applies event and persists the result.
+func (t *stateTracker) fire(event Event, reason string) error {
+	if err := t.sm.HandleEventBy(event, t.actor, reason); err != nil {
+		return err
+	}
+	return t.save()
+}
+
+// setIteration records the iteration that just ran.
+func (t *stateTracker) setIteration(iteration int) error {
+	t.state.Iteration = iteration
+	return t.save()
+}
+
+// setPlan records the approved plan so a resumed execution can use it.
+func (t *stateTracker) setPlan(plan string) {
+	t.state.Plan = plan
+}
+
+func (t *stateTracker) save() error {
+	t.state.State = t.sm.CurrentState
+	t.state.History = t.sm.History
+	if t.store == nil {
+		return nil
+	}
+	return t.store.Save(t.state)
+}
@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateMachine_RecordsHistory(t *testing.T) {
+	sm := NewStateMachine("")
+	require.NoError(t, sm.HandleEventBy(EventTaskStarted, "claude", "started"))
+	require.NoError(t, sm.HandleEvent(EventPlanApproved))
+
+	require.Len(t, sm.History, 2)
+	assert.Equal(t, StateIdle, sm.History[0].From)
+	assert.Equal(t, StatePlanning, sm.History[0].To)
+	assert.Equal(t, "claude", sm.History[0].Actor)
+	assert.Equal(t, "started", sm.History[0].Reason)
+	assert.False(t, sm.History[0].Timestamp.IsZero())
+	assert.Equal(t, StateExecution, sm.CurrentState)
+}
+
+func TestStateMachine_TypedTransitionErrors(t *testing.T) {
+	sm := NewStateMachine(StateExecution)
+	err := sm.HandleEvent(EventPlanApproved)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidTransition))
+
+	var terr *TransitionError
+	require.True(t, errors.As(err, &terr))
+	assert.Equal(t, StateExecution, terr.From)
+	assert.Equal(t, EventPlanApproved, terr.Event)
+	assert.False(t, terr.Terminal())
+	assert.Equal(t, "invalid transition from EXECUTION with event PLAN_APPROVED", err.Error())
+	assert.Empty(t, sm.History)
+
+	err = NewStateMachine(StateDone).HandleEvent(EventTaskStarted)
+	require.True(t, errors.As(err, &terr))
+	assert.True(t, terr.Terminal())
+	assert.Equal(t, "cannot transition from DONE state", err.Error())
+}
+
+func TestStateStore_SaveLoad(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+
+	ts, err := store.Load("T-1")
+	require.NoError(t, err)
+	assert.Nil(t, ts)
+
+	tracker, resumed, err := newStateTracker(store, "T-1", "claude")
+	require.NoError(t, err)
+	assert.False(t, resumed)
+	require.NoError(t, tracker.fire(EventTaskStarted, "go"))
+	tracker.setPlan("the plan")
+	require.NoError(t, tracker.setIteration(1))
+
+	loaded, err := store.Load("T-1")
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+	assert.Equal(t, StatePlanning, loaded.State)
+	assert.Equal(t, 1, loaded.Iteration)
+	assert.Equal(t, "the plan", loaded.Plan)
+	require.Len(t, loaded.History, 1)
+	assert.Equal(t, EventTaskStarted, loaded.History[0].Event)
+
+	_, resumed, err = newStateTracker(store, "T-1", "claude")
+	require.NoError(t, err)
+	assert.True(t, resumed)
+}
+
+func TestStateStore_Reset(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	tracker, _, err := newStateTracker(store, "T-1", "claude")
+	require.NoError(t, err)
+	require.NoError(t, tracker.fire(EventTaskStarted, ""))
+	require.NoError(t, tracker.setIteration(4))
+
+	require.NoError(t, store.Reset("T-1", "bob", "claimed from backlog"))
+
+	loaded, err := store.Load("T-1")
+	require.NoError(t, err)
+	assert.Equal(t, StateIdle, loaded.State)
+	assert.Equal(t, 0, loaded.Iteration)
+	require.Len(t, loaded.History, 2)
+	assert.Equal(t, EventReset, loaded.History[1].Event)
+	assert.Equal(t, StatePlanning, loaded.History[1].From)
+
+	// Resetting an unknown task is a no-op
+	assert.NoError(t, store.Reset("MISSING", "bob", ""))
+}
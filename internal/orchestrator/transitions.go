@@ -1,36 +1,83 @@
 package orchestrator
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTransition matches every *TransitionError via errors.Is.
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// TransitionError reports an event that is not valid in the current state.
+type TransitionError struct {
+	From  State
+	Event Event
+}
+
+func (e *TransitionError) Error() string {
+	if e.From == StateDone {
+		return "cannot transition from DONE state"
+	}
+	return fmt.Sprintf("invalid transition from %s with event %s", e.From, e.Event)
+}
+
+// Is lets callers use errors.Is(err, ErrInvalidTransition).
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// Terminal reports whether the machine was already in its final state.
+func (e *TransitionError) Terminal() bool {
+	return e.From == StateDone
+}
 
 func (sm *StateMachine) HandleEvent(event Event) error {
-	switch sm.CurrentState {
+	return sm.HandleEventBy(event, "", "")
+}
+
+// HandleEventBy applies event and appends the transition, with the actor that
+// caused it and an optional reason, to the machine's history.
+func (sm *StateMachine) HandleEventBy(event Event, actor, reason string) error {
+	next, ok := nextState(sm.CurrentState, event)
+	if !ok {
+		return &TransitionError{From: sm.CurrentState, Event: event}
+	}
+
+	sm.History = append(sm.History, Transition{
+		Event:     event,
+		From:      sm.CurrentState,
+		To:        next,
+		Timestamp: time.Now(),
+		Actor:     actor,
+		Reason:    reason,
+	})
+	sm.CurrentState = next
+	return nil
+}
+
+// nextState returns the state reached from current on event.
+func nextState(current State, event Event) (State, bool) {
+	switch current {
 	case StateIdle:
 		if event == EventTaskStarted {
-			sm.CurrentState = StatePlanning
-			return nil
+			return StatePlanning, true
 		}
 	case StatePlanning:
 		if event == EventPlanApproved {
-			sm.CurrentState = StateExecution
-			return nil
+			return StateExecution, true
 		}
 	case StateExecution:
 		if event == EventWorkCompleted {
-			sm.CurrentState = StateVerification
-			return nil
+			return StateVerification, true
 		}
 	case StateVerification:
 		if event == EventVerificationPass {
-			sm.CurrentState = StateDone
-			return nil
+			return StateDone, true
 		}
 		if event == EventVerificationFail {
-			sm.CurrentState = StateExecution // Back to fix
-			return nil
+			return StateExecution, true // Back to fix
 		}
-	case StateDone:
-		return fmt.Errorf("cannot transition from DONE state")
 	}
-
-	return fmt.Errorf("invalid transition from %s with event %s", sm.CurrentState, event)
+	return "", false
 }
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/javierbenavides/agentic-agent/internal/validator"
 	"gopkg.in/yaml.v3"
 )
 
@@ -46,6 +48,7 @@ func runGate1ContextCompleteness(specDir string, node SpecGraphNode) GateResult
 	}
 
 	metadataPath := filepath.Join(specDir, "metadata.yaml")
+	result.File = metadataPath
 	data, err := os.ReadFile(metadataPath)
 	if err != nil {
 		result.Status = "FAIL"
@@ -103,6 +106,7 @@ func runGate2DomainValidity(specDir string, node SpecGraphNode) GateResult {
 	if _, err := os.Stat(specPath); err != nil {
 		specPath = filepath.Join(specDir, "proposal.md")
 	}
+	result.File = specPath
 
 	data, err := os.ReadFile(specPath)
 	if err != nil {
@@ -150,6 +154,7 @@ func runGate3IntegrationSafety(specDir string, node SpecGraphNode) GateResult {
 	if _, err := os.Stat(specPath); err != nil {
 		specPath = filepath.Join(specDir, "proposal.md")
 	}
+	result.File = specPath
 
 	data, err := os.ReadFile(specPath)
 	if err != nil {
@@ -196,6 +201,7 @@ func runGate4NFRCompliance(specDir string, node SpecGraphNode) GateResult {
 	if _, err := os.Stat(specPath); err != nil {
 		specPath = filepath.Join(specDir, "proposal.md")
 	}
+	result.File = specPath
 
 	data, err := os.ReadFile(specPath)
 	if err != nil {
@@ -260,6 +266,7 @@ func runGate5ReadyToImplement(specDir string, node SpecGraphNode) GateResult {
 	if _, err := os.Stat(specPath); err != nil {
 		specPath = filepath.Join(specDir, "proposal.md")
 	}
+	result.File = specPath
 
 	data, err := os.ReadFile(specPath)
 	if err != nil {
@@ -294,3 +301,40 @@ func runGate5ReadyToImplement(specDir string, node SpecGraphNode) GateResult {
 
 	return result
 }
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// RuleID names a gate for reports shared with validate, such as
+// "gate-1-context-completeness".
+func (g GateResult) RuleID() string {
+	return fmt.Sprintf("gate-%d-%s", g.Gate, strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(g.Name), "-"), "-"))
+}
+
+// RuleResults converts the report to validator results, one per gate with
+// a finding per issue, so it can be written as SARIF or JUnit. Findings
+// point at the file the gate checked when it exists.
+func (r *GateReport) RuleResults() []*validator.RuleResult {
+	var results []*validator.RuleResult
+	for _, gate := range r.Gates {
+		res := &validator.RuleResult{RuleName: gate.RuleID(), Status: gate.Status}
+		severity := validator.LevelError
+		if gate.Status == "WARN" {
+			severity = validator.LevelWarning
+		}
+		path := ""
+		if _, err := os.Stat(gate.File); gate.File != "" && err == nil {
+			path = filepath.ToSlash(gate.File)
+		}
+		for _, issue := range gate.Issues {
+			res.AddFinding(validator.Finding{
+				RuleID:      res.RuleName,
+				Severity:    severity,
+				Path:        path,
+				Message:     fmt.Sprintf("%s: %s", r.SpecID, issue),
+				Remediation: strings.Join(gate.Remediation, "; "),
+			})
+		}
+		results = append(results, res)
+	}
+	return results
+}
@@ -54,6 +54,7 @@ type GateResult struct {
 	Status       string   `json:"status" yaml:"status"` // "PASS" | "FAIL"
 	Issues       []string `json:"issues,omitempty" yaml:"issues,omitempty"`
 	Remediation  []string `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	File         string   `json:"file,omitempty" yaml:"file,omitempty"` // the spec file the gate checked
 }
 
 // GateReport contains the results of running all five gates on a spec.
@@ -76,6 +76,48 @@ func BuildSimplifyBundle(dirs []string, agent string, cfg *models.Config) (*Simp
 	}, nil
 }
 
+// Markdown renders the bundle as a review prompt document, so
+// 'simplify --format markdown' works like it does for context bundles.
+func (b *SimplifyBundle) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Code Simplification Review\n")
+	list := func(items []string) {
+		for _, item := range items {
+			sb.WriteString("- " + item + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if strings.TrimSpace(b.SkillInstructions) != "" {
+		sb.WriteString("\n## Skill Instructions\n\n" + strings.TrimSpace(b.SkillInstructions) + "\n")
+	}
+	if strings.TrimSpace(b.TechStack) != "" {
+		sb.WriteString("\n## Tech Stack\n\n" + strings.TrimSpace(b.TechStack) + "\n")
+	}
+	if len(b.Directories) > 0 {
+		sb.WriteString("\n## Directory Contexts\n\n")
+		for _, d := range b.Directories {
+			sb.WriteString("### " + d.Path + "\n\n")
+			if d.Purpose != "" {
+				sb.WriteString(strings.TrimSpace(d.Purpose) + "\n\n")
+			}
+			if len(d.MustDo) > 0 {
+				sb.WriteString("**Must do:**\n\n")
+				list(d.MustDo)
+			}
+			if len(d.CannotDo) > 0 {
+				sb.WriteString("**Cannot do:**\n\n")
+				list(d.CannotDo)
+			}
+		}
+	}
+	if len(b.TargetFiles) > 0 {
+		sb.WriteString("\n## Target Files\n\n")
+		list(b.TargetFiles)
+	}
+	return sb.String()
+}
+
 // scanSourceFiles returns source file paths in a directory, skipping common non-source files.
 func scanSourceFiles(dir string) ([]string, error) {
 	var files []string
@@ -3,8 +3,10 @@ package simplify
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/javierbenavides/agentic-agent/internal/encoding"
 	"github.com/javierbenavides/agentic-agent/pkg/models"
 )
 
@@ -116,3 +118,22 @@ func TestScanSourceFiles_SkipsNodeModules(t *testing.T) {
 		t.Errorf("expected 1 source file (excluding node_modules), got %d: %v", len(files), files)
 	}
 }
+
+func TestSimplifyBundle_MarkdownFormat(t *testing.T) {
+	bundle := &SimplifyBundle{
+		SkillInstructions: "Prefer early returns.",
+		Directories:       []*models.DirectoryContext{{Path: "src", Purpose: "Entry point", MustDo: []string{"Keep main small"}}},
+		TargetFiles:       []string{"src/main.go"},
+	}
+
+	data, err := encoding.EncodeAs(bundle, "markdown")
+	if err != nil {
+		t.Fatalf("markdown encoding failed: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{"# Code Simplification Review", "Prefer early returns.", "### src", "- Keep main small", "- src/main.go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown output missing %q:\n%s", want, out)
+		}
+	}
+}
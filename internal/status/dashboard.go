@@ -1,6 +1,8 @@
 package status
 
 import (
+	"time"
+
 	"github.com/javierbenavides/agentic-agent/internal/tasks"
 	"github.com/javierbenavides/agentic-agent/pkg/models"
 )
@@ -17,6 +19,7 @@ type DashboardData struct {
 	BacklogTasks    []models.Task
 	NextReady       *models.Task
 	Blockers        []string
+	ExpiredClaims   []models.Task // in progress, but the lease lapsed without a heartbeat
 	RecentEntries   []tasks.ProgressEntry
 }
 
@@ -48,10 +51,34 @@ func Gather(tm *tasks.TaskManager, cfg *models.Config) (*DashboardData, error) {
 		d.CompletionPct = float64(d.DoneCount) / float64(d.TotalCount) * 100
 	}
 
+	lease, err := tasks.ClaimLease(cfg)
+	if err != nil {
+		d.Blockers = append(d.Blockers, err.Error())
+	}
+	now := time.Now()
+	for i := range inProgress.Tasks {
+		if tasks.LeaseExpired(&inProgress.Tasks[i], now, lease) {
+			d.ExpiredClaims = append(d.ExpiredClaims, inProgress.Tasks[i])
+		}
+	}
+
+	completed := make(map[string]bool, len(done.Tasks))
+	for _, t := range done.Tasks {
+		completed[t.ID] = true
+	}
+
+	// Consider backlog tasks in dependency order; fall back to file order on a cycle
+	ordered, err := tasks.OrderByDependencies(backlog.Tasks)
+	if err != nil {
+		d.Blockers = append(d.Blockers, err.Error())
+		ordered = backlog.Tasks
+	}
+	d.BacklogTasks = ordered
+
 	// Find next ready task and collect blockers
-	for i := range backlog.Tasks {
-		t := &backlog.Tasks[i]
-		result := tasks.CanClaimTask(t, cfg)
+	for i := range ordered {
+		t := &ordered[i]
+		result := tasks.CanClaimTaskWithDeps(t, cfg, completed)
 		if result.Ready {
 			if d.NextReady == nil {
 				d.NextReady = t
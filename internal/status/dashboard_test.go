@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/javierbenavides/agentic-agent/internal/config"
 	"github.com/javierbenavides/agentic-agent/internal/tasks"
@@ -121,3 +122,42 @@ func TestGather_BlockedTasks(t *testing.T) {
 	assert.NotEmpty(t, d.Blockers)
 	assert.Contains(t, d.Blockers[0], "nonexistent-file.go")
 }
+
+func TestGather_NextReadyRespectsDependencies(t *testing.T) {
+	_, tm, cfg := setupTestDir(t)
+
+	// TASK-2 is first in the file but needs TASK-1 done
+	require.NoError(t, tm.SaveTasks("backlog", &tasks.TaskList{Tasks: []models.Task{
+		{ID: "TASK-2", Title: "Second", Status: models.StatusPending, DependsOn: []string{"TASK-1"}},
+		{ID: "TASK-1", Title: "First", Status: models.StatusPending},
+	}}))
+
+	d, err := Gather(tm, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, d.NextReady)
+	assert.Equal(t, "TASK-1", d.NextReady.ID)
+	assert.Equal(t, "TASK-1", d.BacklogTasks[0].ID)
+	assert.Contains(t, d.Blockers, "TASK-2: dependency TASK-1 is not done")
+}
+
+func TestGather_ExpiredClaims(t *testing.T) {
+	_, tm, cfg := setupTestDir(t)
+	now := time.Now()
+	require.NoError(t, tm.SaveTasks("in-progress", &tasks.TaskList{Tasks: []models.Task{
+		{ID: "STALE", Status: models.StatusInProgress, ClaimedAt: now.Add(-2 * time.Hour), LeaseExpiresAt: now.Add(-time.Hour)},
+		{ID: "LIVE", Status: models.StatusInProgress, ClaimedAt: now.Add(-2 * time.Hour), LeaseExpiresAt: now.Add(time.Hour)},
+		{ID: "LEGACY", Status: models.StatusInProgress, ClaimedAt: now.Add(-time.Hour)},
+	}}))
+
+	d, err := Gather(tm, cfg)
+	require.NoError(t, err)
+	require.Len(t, d.ExpiredClaims, 2)
+	assert.Equal(t, "STALE", d.ExpiredClaims[0].ID)
+	assert.Equal(t, "LEGACY", d.ExpiredClaims[1].ID, "claims without a lease use workflow.claim_lease")
+
+	cfg.Workflow.ClaimLease = "2h"
+	d, err = Gather(tm, cfg)
+	require.NoError(t, err)
+	require.Len(t, d.ExpiredClaims, 1)
+	assert.Equal(t, "STALE", d.ExpiredClaims[0].ID)
+}
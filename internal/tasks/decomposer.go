@@ -10,51 +10,31 @@ import (
 func (tm *TaskManager) DecomposeTask(taskID string, subtasks []string) error {
 	// Task must be in In-Progress to decompose? Or any state?
 	// Usually In-Progress or Pending. Let's look in In-Progress first, then Backlog.
-
-	listType := "in-progress"
-	list, err := tm.LoadTasks(listType)
-	if err != nil {
-		return err
-	}
-
-	foundIdx := -1
-	for i, t := range list.Tasks {
-		if t.ID == taskID {
-			foundIdx = i
-			break
-		}
-	}
-
-	if foundIdx == -1 {
-		// Check backlog
-		listType = "backlog"
-		list, err = tm.LoadTasks(listType)
-		if err != nil {
-			return err
-		}
-		for i, t := range list.Tasks {
-			if t.ID == taskID {
-				foundIdx = i
-				break
+	return tm.Update(func(tx *Tx) error {
+		for _, listType := range []string{"in-progress", "backlog"} {
+			list, err := tx.Load(listType)
+			if err != nil {
+				return err
+			}
+			for i := range list.Tasks {
+				if list.Tasks[i].ID != taskID {
+					continue
+				}
+				// Add subtasks
+				for j, title := range subtasks {
+					subID := fmt.Sprintf("%s.%d", taskID, j+1)
+					list.Tasks[i].SubTasks = append(list.Tasks[i].SubTasks, models.SubTask{
+						ID:     subID,
+						Title:  title,
+						Status: models.StatusPending,
+					})
+				}
+				tx.Save(listType, list)
+				return nil
 			}
 		}
-	}
-
-	if foundIdx == -1 {
 		return fmt.Errorf("task %s not found", taskID)
-	}
-
-	// Add subtasks
-	for i, title := range subtasks {
-		subID := fmt.Sprintf("%s.%d", taskID, i+1)
-		list.Tasks[foundIdx].SubTasks = append(list.Tasks[foundIdx].SubTasks, models.SubTask{
-			ID:     subID,
-			Title:  title,
-			Status: models.StatusPending,
-		})
-	}
-
-	return tm.SaveTasks(listType, list)
+	})
 }
 
 // DecomposeFromPlan parses a plan.md and creates one task per phase,
@@ -93,17 +73,21 @@ func DecomposeFromPlan(planPath, trackID string, tm *TaskManager) ([]*models.Tas
 		task.TrackID = trackID
 
 		// Save updated task back to backlog
-		backlog, err := tm.LoadTasks("backlog")
-		if err != nil {
-			return created, fmt.Errorf("failed to load backlog: %w", err)
-		}
-		for i, t := range backlog.Tasks {
-			if t.ID == task.ID {
-				backlog.Tasks[i] = *task
-				break
+		err = tm.Update(func(tx *Tx) error {
+			backlog, err := tx.Load("backlog")
+			if err != nil {
+				return fmt.Errorf("failed to load backlog: %w", err)
 			}
-		}
-		if err := tm.SaveTasks("backlog", backlog); err != nil {
+			for i, t := range backlog.Tasks {
+				if t.ID == task.ID {
+					backlog.Tasks[i] = *task
+					break
+				}
+			}
+			tx.Save("backlog", backlog)
+			return nil
+		})
+		if err != nil {
 			return created, fmt.Errorf("failed to save task: %w", err)
 		}
 
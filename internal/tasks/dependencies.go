@@ -0,0 +1,235 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// AddDependency records that taskID cannot start until each of dependsOn is done.
+// Both ends must exist, and the new edges must not introduce a cycle.
+func (tm *TaskManager) AddDependency(taskID string, dependsOn ...string) error {
+	return tm.Update(func(tx *Tx) error {
+		all, err := loadAllInTx(tx)
+		if err != nil {
+			return err
+		}
+
+		graph := dependencyGraph(all)
+		if _, ok := graph[taskID]; !ok {
+			return fmt.Errorf("task %s not found", taskID)
+		}
+		for _, dep := range dependsOn {
+			if dep == taskID {
+				return fmt.Errorf("task %s cannot depend on itself", taskID)
+			}
+			if _, ok := graph[dep]; !ok {
+				return fmt.Errorf("dependency %s not found", dep)
+			}
+			if !containsString(graph[taskID], dep) {
+				graph[taskID] = append(graph[taskID], dep)
+			}
+		}
+		if cycle := findCycle(graph); cycle != nil {
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		return updateInTx(tx, taskID, func(t *models.Task) {
+			for _, dep := range dependsOn {
+				if !containsString(t.DependsOn, dep) {
+					t.DependsOn = append(t.DependsOn, dep)
+				}
+			}
+		})
+	})
+}
+
+// RemoveDependency drops the given prerequisites from taskID.
+func (tm *TaskManager) RemoveDependency(taskID string, dependsOn ...string) error {
+	return tm.Update(func(tx *Tx) error {
+		return updateInTx(tx, taskID, func(t *models.Task) {
+			var kept []string
+			for _, dep := range t.DependsOn {
+				if !containsString(dependsOn, dep) {
+					kept = append(kept, dep)
+				}
+			}
+			t.DependsOn = kept
+		})
+	})
+}
+
+// CompletedTaskIDs returns the set of task IDs in the done list.
+func (tm *TaskManager) CompletedTaskIDs() (map[string]bool, error) {
+	done, err := tm.LoadTasks("done")
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(done.Tasks))
+	for _, t := range done.Tasks {
+		ids[t.ID] = true
+	}
+	return ids, nil
+}
+
+// UnmetDependencies returns the prerequisites of task that are not yet done.
+func UnmetDependencies(task *models.Task, completed map[string]bool) []string {
+	var unmet []string
+	for _, dep := range task.DependsOn {
+		if !completed[dep] {
+			unmet = append(unmet, dep)
+		}
+	}
+	return unmet
+}
+
+// OrderByDependencies sorts tasks so every task comes after the prerequisites
+// that appear in the same slice. Ties keep their original (file) order.
+// Prerequisites outside the slice do not affect ordering.
+// Returns an error naming the cycle if the tasks are not a DAG.
+func OrderByDependencies(tasks []models.Task) ([]models.Task, error) {
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+
+	// Count in-slice prerequisites and record reverse edges
+	indegree := make([]int, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for i, t := range tasks {
+		for _, dep := range t.DependsOn {
+			if j, ok := index[dep]; ok {
+				indegree[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	// Kahn's algorithm, always taking the lowest original index next
+	ordered := make([]models.Task, 0, len(tasks))
+	emitted := make([]bool, len(tasks))
+	for len(ordered) < len(tasks) {
+		next := -1
+		for i := range tasks {
+			if !emitted[i] && indegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			graph := dependencyGraph(tasks)
+			cycle := findCycle(graph)
+			return nil, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+		emitted[next] = true
+		ordered = append(ordered, tasks[next])
+		for _, d := range dependents[next] {
+			indegree[d]--
+		}
+	}
+	return ordered, nil
+}
+
+// dependencyGraph maps each task ID to the IDs it depends on.
+func dependencyGraph(tasks []models.Task) map[string][]string {
+	graph := make(map[string][]string, len(tasks))
+	for _, t := range tasks {
+		graph[t.ID] = append([]string(nil), t.DependsOn...)
+	}
+	return graph
+}
+
+// findCycle returns one dependency cycle as a path that starts and ends on the
+// same task ID, or nil if the graph is acyclic. Edges to unknown IDs are ignored.
+func findCycle(graph map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(graph))
+	var stack []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dep := range graph[id] {
+			if _, ok := graph[dep]; !ok {
+				continue
+			}
+			switch state[dep] {
+			case visiting:
+				for i, s := range stack {
+					if s == dep {
+						return append(append([]string(nil), stack[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = visited
+		return nil
+	}
+
+	// Visit in sorted order for a deterministic result
+	ids := make([]string, 0, len(graph))
+	for id := range graph {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// loadAllInTx returns the tasks of every list within a transaction.
+func loadAllInTx(tx *Tx) ([]models.Task, error) {
+	var all []models.Task
+	for _, source := range []string{"backlog", "in-progress", "done"} {
+		list, err := tx.Load(source)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s: %w", source, err)
+		}
+		all = append(all, list.Tasks...)
+	}
+	return all, nil
+}
+
+// updateInTx applies update to taskID in whichever list holds it.
+func updateInTx(tx *Tx, taskID string, update func(*models.Task)) error {
+	for _, source := range []string{"backlog", "in-progress", "done"} {
+		list, err := tx.Load(source)
+		if err != nil {
+			return err
+		}
+		for i := range list.Tasks {
+			if list.Tasks[i].ID == taskID {
+				update(&list.Tasks[i])
+				tx.Save(source, list)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("task %s not found", taskID)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddDependency_Success(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "A", Status: models.StatusPending},
+		{ID: "B", Status: models.StatusPending},
+	}}))
+
+	require.NoError(t, tm.AddDependency("B", "A"))
+	// Adding the same edge twice is a no-op
+	require.NoError(t, tm.AddDependency("B", "A"))
+
+	task, _, err := tm.FindTask("B")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A"}, task.DependsOn)
+}
+
+func TestAddDependency_RejectsCycle(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "A"},
+		{ID: "B", DependsOn: []string{"A"}},
+		{ID: "C", DependsOn: []string{"B"}},
+	}}))
+
+	err := tm.AddDependency("A", "C")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle")
+
+	err = tm.AddDependency("A", "A")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "itself")
+
+	// Nothing was written
+	task, _, _ := tm.FindTask("A")
+	assert.Empty(t, task.DependsOn)
+}
+
+func TestAddDependency_UnknownTask(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{{ID: "A"}}}))
+
+	assert.Error(t, tm.AddDependency("A", "MISSING"))
+	assert.Error(t, tm.AddDependency("MISSING", "A"))
+}
+
+func TestRemoveDependency(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "A"}, {ID: "B"}, {ID: "C", DependsOn: []string{"A", "B"}},
+	}}))
+
+	require.NoError(t, tm.RemoveDependency("C", "A"))
+
+	task, _, _ := tm.FindTask("C")
+	assert.Equal(t, []string{"B"}, task.DependsOn)
+}
+
+func TestOrderByDependencies(t *testing.T) {
+	input := []models.Task{
+		{ID: "C", DependsOn: []string{"B"}},
+		{ID: "B", DependsOn: []string{"A"}},
+		{ID: "X"},
+		{ID: "A"},
+		{ID: "D", DependsOn: []string{"DONE-ELSEWHERE"}},
+	}
+
+	ordered, err := OrderByDependencies(input)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, t := range ordered {
+		ids = append(ids, t.ID)
+	}
+	assert.Equal(t, []string{"X", "A", "B", "C", "D"}, ids)
+}
+
+func TestOrderByDependencies_Cycle(t *testing.T) {
+	_, err := OrderByDependencies([]models.Task{
+		{ID: "A", DependsOn: []string{"B"}},
+		{ID: "B", DependsOn: []string{"A"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "A -> B -> A")
+}
+
+func TestCanClaimTaskWithDeps(t *testing.T) {
+	task := &models.Task{ID: "B", DependsOn: []string{"A"}}
+
+	result := CanClaimTaskWithDeps(task, nil, map[string]bool{})
+	assert.False(t, result.Ready)
+	assert.Equal(t, "dependency-done", result.Checks[0].Name)
+
+	result = CanClaimTaskWithDeps(task, nil, map[string]bool{"A": true})
+	assert.True(t, result.Ready)
+
+	// Without a completed set, declared dependencies are unmet
+	assert.False(t, CanClaimTask(task, nil).Ready)
+}
+
+func TestClaimTask_BlockedByDependency(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "A", Status: models.StatusPending},
+		{ID: "B", Status: models.StatusPending, DependsOn: []string{"A"}},
+	}}))
+
+	err := tm.ClaimTask("B", "agent")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "blocked by unfinished dependencies: A")
+
+	require.NoError(t, tm.MoveTask("A", "backlog", "done", models.StatusDone))
+	assert.NoError(t, tm.ClaimTask("B", "agent"))
+}
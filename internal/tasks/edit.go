@@ -0,0 +1,247 @@
+package tasks
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TaskEdit holds the fields of a task that can be changed after creation.
+// Lifecycle fields (status, assignee, claim times, commits, worktree) are
+// left to claim, complete and release.
+type TaskEdit struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Type        string   `yaml:"type"`
+	Scope       []string `yaml:"scope"`
+	SpecRefs    []string `yaml:"spec_refs"`
+	SkillRefs   []string `yaml:"skill_refs"`
+	Inputs      []string `yaml:"inputs"`
+	Outputs     []string `yaml:"outputs"`
+	Acceptance  []string `yaml:"acceptance"`
+}
+
+// EditableFields returns the editable fields of t.
+func EditableFields(t *models.Task) TaskEdit {
+	clone := func(s []string) []string { return append([]string{}, s...) }
+	return TaskEdit{
+		Title:       t.Title,
+		Description: t.Description,
+		Type:        t.Type,
+		Scope:       clone(t.Scope),
+		SpecRefs:    clone(t.SpecRefs),
+		SkillRefs:   clone(t.SkillRefs),
+		Inputs:      clone(t.Inputs),
+		Outputs:     clone(t.Outputs),
+		Acceptance:  clone(t.Acceptance),
+	}
+}
+
+// Apply copies the edited fields onto t. Empty lists are stored as nil so
+// they disappear from the task file.
+func (e TaskEdit) Apply(t *models.Task) {
+	orNil := func(s []string) []string {
+		if len(s) == 0 {
+			return nil
+		}
+		return s
+	}
+	t.Title = e.Title
+	t.Description = e.Description
+	t.Type = e.Type
+	t.Scope = orNil(e.Scope)
+	t.SpecRefs = orNil(e.SpecRefs)
+	t.SkillRefs = orNil(e.SkillRefs)
+	t.Inputs = orNil(e.Inputs)
+	t.Outputs = orNil(e.Outputs)
+	t.Acceptance = orNil(e.Acceptance)
+}
+
+// Validate checks the title the way task create does and rejects blank or
+// repeated list entries.
+func (e TaskEdit) Validate() error {
+	switch {
+	case strings.TrimSpace(e.Title) == "":
+		return fmt.Errorf("title cannot be empty")
+	case len(e.Title) > 200:
+		return fmt.Errorf("title too long (max 200 characters)")
+	case strings.ContainsAny(e.Title, "\r\n"):
+		return fmt.Errorf("title cannot contain newlines")
+	case strings.ContainsAny(e.Type, " \t\r\n"):
+		return fmt.Errorf("type %q cannot contain spaces", e.Type)
+	}
+	for _, field := range editListFields {
+		seen := map[string]bool{}
+		for _, item := range *e.list(field) {
+			if strings.TrimSpace(item) == "" {
+				return fmt.Errorf("%s: entries cannot be empty", field)
+			}
+			if seen[item] {
+				return fmt.Errorf("%s: %q is listed twice", field, item)
+			}
+			seen[item] = true
+		}
+	}
+	return nil
+}
+
+// editListFields are the list fields of TaskEdit by their YAML names.
+var editListFields = []string{"scope", "spec_refs", "skill_refs", "inputs", "outputs", "acceptance"}
+
+func (e *TaskEdit) list(field string) *[]string {
+	switch field {
+	case "scope":
+		return &e.Scope
+	case "spec_refs":
+		return &e.SpecRefs
+	case "skill_refs":
+		return &e.SkillRefs
+	case "inputs":
+		return &e.Inputs
+	case "outputs":
+		return &e.Outputs
+	case "acceptance":
+		return &e.Acceptance
+	}
+	return nil
+}
+
+// Add appends values to a list field, skipping ones already present.
+func (e *TaskEdit) Add(field string, values ...string) error {
+	list := e.list(field)
+	if list == nil {
+		return fmt.Errorf("unknown list field %q", field)
+	}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && !containsString(*list, v) {
+			*list = append(*list, v)
+		}
+	}
+	return nil
+}
+
+// Remove deletes values from a list field. Every value must be present.
+func (e *TaskEdit) Remove(field string, values ...string) error {
+	list := e.list(field)
+	if list == nil {
+		return fmt.Errorf("unknown list field %q", field)
+	}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		i := slices.Index(*list, v)
+		if i < 0 {
+			return fmt.Errorf("%s: %q is not on the task", field, v)
+		}
+		*list = append((*list)[:i], (*list)[i+1:]...)
+	}
+	return nil
+}
+
+// MarshalTaskEdit renders the editable fields for a text editor.
+func MarshalTaskEdit(e TaskEdit) ([]byte, error) {
+	return yaml.Marshal(&e)
+}
+
+// ParseTaskEdit reads edited fields back, rejecting unknown keys so a
+// misspelt or read-only field is reported instead of silently dropped.
+func ParseTaskEdit(data []byte) (TaskEdit, error) {
+	var e TaskEdit
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&e); err != nil {
+		return TaskEdit{}, fmt.Errorf("invalid task YAML: %w", err)
+	}
+	return e, e.Validate()
+}
+
+// EditTask changes the editable fields of a task in whichever list holds
+// it. edit receives the current fields and modifies them in place; the
+// result is validated before anything is saved. It returns the updated
+// task and its list. A subtask only has a title, so any other change to
+// one is rejected.
+func (tm *TaskManager) EditTask(taskID string, edit func(e *TaskEdit) error) (*models.Task, string, error) {
+	var updated *models.Task
+	var source string
+	err := tm.Update(func(tx *Tx) error {
+		task, list, pos, err := findInTx(tx, taskID)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			updated, source, err = editSubtask(tx, taskID, edit)
+			return err
+		}
+
+		e := EditableFields(task)
+		if err := edit(&e); err != nil {
+			return err
+		}
+		if err := e.Validate(); err != nil {
+			return err
+		}
+
+		tl, _ := tx.Load(list)
+		e.Apply(&tl.Tasks[pos])
+		tx.Save(list, tl)
+		t := tl.Tasks[pos]
+		updated, source = &t, list
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return updated, source, nil
+}
+
+// editSubtask applies an edit to the subtask taskID, which may only change
+// its title.
+func editSubtask(tx *Tx, taskID string, edit func(e *TaskEdit) error) (*models.Task, string, error) {
+	for _, name := range taskLists {
+		tl, err := tx.Load(name)
+		if err != nil {
+			return nil, "", err
+		}
+		for i := range tl.Tasks {
+			for j := range tl.Tasks[i].SubTasks {
+				st := &tl.Tasks[i].SubTasks[j]
+				if st.ID != taskID {
+					continue
+				}
+
+				e := EditableFields(&models.Task{Title: st.Title})
+				if err := edit(&e); err != nil {
+					return nil, "", err
+				}
+				if err := e.Validate(); err != nil {
+					return nil, "", err
+				}
+				if want := EditableFields(&models.Task{Title: e.Title}); !editsEqual(e, want) {
+					return nil, "", fmt.Errorf("%s is a subtask of %s; only its title can be edited", taskID, tl.Tasks[i].ID)
+				}
+
+				st.Title = e.Title
+				tx.Save(name, tl)
+				return findInList(tl.Tasks, taskID), name, nil
+			}
+		}
+	}
+	return nil, "", fmt.Errorf("task %s not found", taskID)
+}
+
+// editsEqual compares two edits, treating nil and empty lists alike.
+func editsEqual(a, b TaskEdit) bool {
+	if a.Title != b.Title || a.Description != b.Description || a.Type != b.Type {
+		return false
+	}
+	for _, field := range editListFields {
+		if !slices.Equal(*a.list(field), *b.list(field)) {
+			return false
+		}
+	}
+	return true
+}
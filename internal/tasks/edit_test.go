@@ -0,0 +1,114 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditTask_ChangesFieldsInPlace(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("in-progress", &TaskList{Tasks: []models.Task{
+		{ID: "T-0", Status: models.StatusInProgress},
+		{ID: "T-1", Title: "Login", Status: models.StatusInProgress, AssignedTo: "alice", Scope: []string{"a", "b"}},
+	}}))
+
+	task, list, err := tm.EditTask("T-1", func(e *TaskEdit) error {
+		e.Title = "Login form"
+		e.Type = "review"
+		if err := e.Remove("scope", "a"); err != nil {
+			return err
+		}
+		return e.Add("scope", "b", "c")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "in-progress", list)
+	assert.Equal(t, "Login form", task.Title)
+
+	inProgress, err := tm.LoadTasks("in-progress")
+	require.NoError(t, err)
+	require.Len(t, inProgress.Tasks, 2)
+	stored := inProgress.Tasks[1]
+	assert.Equal(t, "T-1", stored.ID)
+	assert.Equal(t, "review", stored.Type)
+	assert.Equal(t, []string{"b", "c"}, stored.Scope)
+	assert.Equal(t, "alice", stored.AssignedTo, "lifecycle fields are kept")
+
+	events, err := tm.Events("T-1")
+	require.NoError(t, err)
+	last := events[len(events)-1]
+	assert.Equal(t, EventUpdate, last.Type)
+	assert.ElementsMatch(t, []string{"title", "type", "scope"}, last.Fields)
+}
+
+func TestEditTask_RejectsInvalidEdits(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{{ID: "T-1", Title: "Login"}}}))
+
+	_, _, err := tm.EditTask("T-1", func(e *TaskEdit) error {
+		e.Title = "  "
+		return nil
+	})
+	assert.EqualError(t, err, "title cannot be empty")
+
+	_, _, err = tm.EditTask("T-1", func(e *TaskEdit) error {
+		return e.Remove("acceptance", "missing")
+	})
+	assert.EqualError(t, err, `acceptance: "missing" is not on the task`)
+
+	_, _, err = tm.EditTask("T-9", func(e *TaskEdit) error { return nil })
+	assert.EqualError(t, err, "task T-9 not found")
+
+	task, _, err := tm.FindTask("T-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Login", task.Title)
+}
+
+func TestParseTaskEdit(t *testing.T) {
+	e := EditableFields(&models.Task{Title: "Login", Scope: []string{"internal/auth"}})
+	data, err := MarshalTaskEdit(e)
+	require.NoError(t, err)
+	parsed, err := ParseTaskEdit(data)
+	require.NoError(t, err)
+	assert.Equal(t, e, parsed)
+
+	_, err = ParseTaskEdit([]byte("title: Login\nstatus: done\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field status not found")
+
+	_, err = ParseTaskEdit([]byte("title: Login\noutputs: [a.go, a.go]\n"))
+	assert.EqualError(t, err, `outputs: "a.go" is listed twice`)
+
+	_, err = ParseTaskEdit([]byte("title: Login\ntype: code review\n"))
+	assert.EqualError(t, err, `type "code review" cannot contain spaces`)
+}
+
+func TestEditTask_Subtask(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "T-1", Title: "Login", SubTasks: []models.SubTask{{ID: "T-1.1", Title: "Form"}}},
+	}}))
+
+	task, list, err := tm.EditTask("T-1.1", func(e *TaskEdit) error {
+		e.Title = "Login form"
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "backlog", list)
+	assert.Equal(t, "Login form", task.Title)
+
+	backlog, err := tm.LoadTasks("backlog")
+	require.NoError(t, err)
+	assert.Equal(t, "Login form", backlog.Tasks[0].SubTasks[0].Title)
+	assert.Equal(t, "Login", backlog.Tasks[0].Title)
+
+	_, _, err = tm.EditTask("T-1.1", func(e *TaskEdit) error {
+		return e.Add("scope", "internal/auth")
+	})
+	assert.EqualError(t, err, "T-1.1 is a subtask of T-1; only its title can be edited")
+}
@@ -0,0 +1,443 @@
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// EventLogFileName is the append-only log of task changes, one JSON event
+// per line, kept next to the task lists.
+const EventLogFileName = "events.jsonl"
+
+// EventType says what kind of change an event records.
+type EventType string
+
+const (
+	EventCreate    EventType = "create"
+	EventClaim     EventType = "claim"     // backlog -> in-progress
+	EventRelease   EventType = "release"   // in-progress -> backlog
+	EventComplete  EventType = "complete"  // -> done
+	EventMove      EventType = "move"      // any other change of list
+	EventDecompose EventType = "decompose" // subtasks changed
+	EventDepend    EventType = "depend"    // depends_on changed
+	EventPRLink    EventType = "pr-link"   // github_pr changed
+	EventUpdate    EventType = "update"    // other fields changed
+	EventDelete    EventType = "delete"
+	EventUndo      EventType = "undo"
+)
+
+// Event is one recorded change to a task. Before and After hold the whole
+// task, so any event can be reverted; Before is nil for a created task and
+// After is nil for a deleted one.
+type Event struct {
+	Seq      int64        `json:"seq"`
+	Time     time.Time    `json:"time"`
+	Type     EventType    `json:"type"`
+	TaskID   string       `json:"task_id"`
+	Actor    string       `json:"actor,omitempty"`
+	FromList string       `json:"from_list,omitempty"`
+	ToList   string       `json:"to_list,omitempty"`
+	Fields   []string     `json:"fields,omitempty"` // changed fields, by their YAML names
+	Undoes   []int64      `json:"undoes,omitempty"` // events reverted by an undo
+	Before   *models.Task `json:"before,omitempty"`
+	After    *models.Task `json:"after,omitempty"`
+}
+
+// leaseFields change on every heartbeat; such changes are not recorded.
+var leaseFields = map[string]bool{"heartbeat_at": true, "lease_expires_at": true}
+
+// commit writes lists through the store and records the changes it made
+// to the event log. undoes marks the changes as reverting those events.
+// Callers hold the lock.
+func (tm *TaskManager) commit(lists map[string]*TaskList, undoes []int64) error {
+	if len(lists) == 0 {
+		return nil
+	}
+	before := make(map[string]*TaskList, len(lists))
+	for name := range lists {
+		list, err := tm.store.Load(name)
+		if err != nil {
+			return err
+		}
+		before[name] = list
+	}
+
+	if err := tm.store.Commit(lists); err != nil {
+		return err
+	}
+
+	events := diffLists(before, lists)
+	if undoes != nil {
+		for i := range events {
+			events[i].Type = EventUndo
+			events[i].Undoes = undoes
+		}
+	}
+	if err := tm.appendEvents(events); err != nil {
+		// The change itself is saved; losing its audit entry is not worth failing it
+		fmt.Fprintf(os.Stderr, "⚠️  Could not record task events: %v\n", err)
+	}
+	return nil
+}
+
+type listedTaskRef struct {
+	list string
+	task *models.Task
+}
+
+// diffLists returns an event for every task that appeared, disappeared,
+// changed list or changed fields between before and after, ordered by ID.
+func diffLists(before, after map[string]*TaskList) []Event {
+	index := func(lists map[string]*TaskList) map[string]listedTaskRef {
+		refs := map[string]listedTaskRef{}
+		for name, list := range lists {
+			for i := range list.Tasks {
+				if _, dup := refs[list.Tasks[i].ID]; !dup {
+					refs[list.Tasks[i].ID] = listedTaskRef{name, &list.Tasks[i]}
+				}
+			}
+		}
+		return refs
+	}
+	old, cur := index(before), index(after)
+
+	ids := map[string]bool{}
+	for id := range old {
+		ids[id] = true
+	}
+	for id := range cur {
+		ids[id] = true
+	}
+
+	var events []Event
+	for id := range ids {
+		o, hadOld := old[id]
+		c, hasCur := cur[id]
+		e := Event{TaskID: id, Type: EventUpdate}
+		switch {
+		case !hadOld:
+			e.Type, e.ToList, e.After = EventCreate, c.list, cloneTask(c.task)
+		case !hasCur:
+			e.Type, e.FromList, e.Before = EventDelete, o.list, cloneTask(o.task)
+		default:
+			e.FromList, e.ToList = o.list, c.list
+			e.Fields = changedFields(o.task, c.task)
+			if o.list == c.list {
+				if len(e.Fields) == 0 || onlyFields(e.Fields, leaseFields) {
+					continue
+				}
+			}
+			e.Type = eventType(o.list, c.list, e.Fields)
+			e.Before, e.After = cloneTask(o.task), cloneTask(c.task)
+		}
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].TaskID < events[j].TaskID })
+	return events
+}
+
+// eventType names a change from its lists and changed fields.
+func eventType(from, to string, fields []string) EventType {
+	switch {
+	case from != to && to == "done":
+		return EventComplete
+	case from == "backlog" && to == "in-progress":
+		return EventClaim
+	case from == "in-progress" && to == "backlog":
+		return EventRelease
+	case from != to:
+		return EventMove
+	case onlyFields(fields, map[string]bool{"subtasks": true}):
+		return EventDecompose
+	case onlyFields(fields, map[string]bool{"depends_on": true}):
+		return EventDepend
+	case onlyFields(fields, map[string]bool{"github_pr": true}):
+		return EventPRLink
+	}
+	return EventUpdate
+}
+
+func onlyFields(fields []string, allowed map[string]bool) bool {
+	for _, f := range fields {
+		if !allowed[f] {
+			return false
+		}
+	}
+	return true
+}
+
+// changedFields lists the YAML names of the task fields that differ. Empty
+// and nil slices count as equal, and times are compared as instants.
+func changedFields(a, b *models.Task) []string {
+	var fields []string
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	for i := 0; i < va.NumField(); i++ {
+		if !fieldEqual(va.Field(i), vb.Field(i)) {
+			name, _, _ := strings.Cut(va.Type().Field(i).Tag.Get("yaml"), ",")
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func fieldEqual(a, b reflect.Value) bool {
+	if ta, ok := a.Interface().(time.Time); ok {
+		return ta.Equal(b.Interface().(time.Time))
+	}
+	if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return true
+	}
+	ja, _ := json.Marshal(a.Interface())
+	jb, _ := json.Marshal(b.Interface())
+	return bytes.Equal(ja, jb)
+}
+
+// cloneTask copies a task so later edits to its list do not reach the event.
+func cloneTask(t *models.Task) *models.Task {
+	data, _ := json.Marshal(t)
+	var c models.Task
+	json.Unmarshal(data, &c)
+	return &c
+}
+
+func (tm *TaskManager) eventLogPath() string {
+	return filepath.Join(tm.baseDir, EventLogFileName)
+}
+
+// appendEvents numbers events after the last logged one and appends them.
+// Callers hold the lock, which keeps sequence numbers unique.
+func (tm *TaskManager) appendEvents(events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	seq, err := lastEventSeq(tm.eventLogPath())
+	if err != nil {
+		return err
+	}
+
+	actor := os.Getenv("USER")
+	now := time.Now().UTC()
+	var buf bytes.Buffer
+	for i := range events {
+		seq++
+		events[i].Seq, events[i].Time, events[i].Actor = seq, now, actor
+		line, err := json.Marshal(&events[i])
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(tm.eventLogPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// lastEventSeq reads the sequence number of the last event in the log,
+// scanning back from the end only as far as the last complete line.
+func lastEventSeq(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	for chunk := int64(4096); ; chunk *= 4 {
+		if chunk > size {
+			chunk = size
+		}
+		buf := make([]byte, chunk)
+		if _, err := f.ReadAt(buf, size-chunk); err != nil && err != io.EOF {
+			return 0, err
+		}
+		buf = bytes.TrimRight(buf, "\n")
+		start := bytes.LastIndexByte(buf, '\n')
+		if start < 0 && chunk < size {
+			continue
+		}
+		if len(buf) == 0 {
+			return 0, nil
+		}
+		var e Event
+		if err := json.Unmarshal(buf[start+1:], &e); err != nil {
+			return 0, fmt.Errorf("corrupt last line in %s: %w", path, err)
+		}
+		return e.Seq, nil
+	}
+}
+
+// Events returns the logged events for a task, or all of them when taskID
+// is empty, oldest first.
+func (tm *TaskManager) Events(taskID string) ([]Event, error) {
+	f, err := os.Open(tm.eventLogPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	r := bufio.NewReader(f)
+	for lineNo := 1; ; lineNo++ {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var e Event
+			if jerr := json.Unmarshal(line, &e); jerr != nil {
+				return nil, fmt.Errorf("%s:%d: %w", tm.eventLogPath(), lineNo, jerr)
+			}
+			if taskID == "" || e.TaskID == taskID {
+				events = append(events, e)
+			}
+		}
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Undo reverts the last n events of a task that are not yet undone,
+// restoring the list and fields the task had before the earliest of them.
+// A task created by a reverted event is removed. The revert is itself
+// logged as an undo event, so undoing again goes further back. It fails
+// if the task no longer matches the newest of those events, which means it
+// was changed outside the log (lease renewals aside).
+func (tm *TaskManager) Undo(taskID string, n int) ([]Event, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("nothing to undo: count must be at least 1")
+	}
+
+	var reverted []Event
+	err := tm.Update(func(tx *Tx) error {
+		history, err := tm.Events(taskID)
+		if err != nil {
+			return err
+		}
+		undone := map[int64]bool{}
+		for _, e := range history {
+			for _, seq := range e.Undoes {
+				undone[seq] = true
+			}
+		}
+		for i := len(history) - 1; i >= 0 && len(reverted) < n; i-- {
+			if e := history[i]; e.Type != EventUndo && !undone[e.Seq] {
+				reverted = append(reverted, e)
+			}
+		}
+		if len(reverted) == 0 {
+			return fmt.Errorf("no events to undo for task %s", taskID)
+		}
+		if len(reverted) < n {
+			return fmt.Errorf("task %s has only %d event(s) to undo", taskID, len(reverted))
+		}
+
+		newest, oldest := reverted[0], reverted[len(reverted)-1]
+		current, list, pos, err := findInTx(tx, taskID)
+		if err != nil {
+			return err
+		}
+		if !sameState(current, list, newest.After, newest.ToList) {
+			return fmt.Errorf("task %s was changed outside the event log since event #%d", taskID, newest.Seq)
+		}
+		tx.undoes = eventSeqs(reverted)
+
+		// Restore in place when the list is unchanged, otherwise move it back
+		if current != nil && oldest.Before != nil && oldest.FromList == list {
+			tl, _ := tx.Load(list)
+			tl.Tasks[pos] = *oldest.Before
+			tx.Save(list, tl)
+			return nil
+		}
+		if current != nil {
+			tl, _ := tx.Load(list)
+			tl.Tasks = append(tl.Tasks[:pos], tl.Tasks[pos+1:]...)
+			tx.Save(list, tl)
+		}
+		if oldest.Before != nil {
+			tl, err := tx.Load(oldest.FromList)
+			if err != nil {
+				return err
+			}
+			tl.Tasks = append(tl.Tasks, *oldest.Before)
+			tx.Save(oldest.FromList, tl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reverted, nil
+}
+
+// findInTx locates a task in the lifecycle lists of a transaction.
+func findInTx(tx *Tx, taskID string) (*models.Task, string, int, error) {
+	for _, name := range taskLists {
+		tl, err := tx.Load(name)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		for i := range tl.Tasks {
+			if tl.Tasks[i].ID == taskID {
+				return &tl.Tasks[i], name, i, nil
+			}
+		}
+	}
+	return nil, "", 0, nil
+}
+
+// sameState reports whether a task in list is what an event left behind,
+// ignoring lease renewals since.
+func sameState(t *models.Task, list string, want *models.Task, wantList string) bool {
+	if t == nil || want == nil {
+		return t == nil && want == nil
+	}
+	if list != wantList {
+		return false
+	}
+	return onlyFields(changedFields(t, want), leaseFields)
+}
+
+func eventSeqs(events []Event) []int64 {
+	seqs := make([]int64, len(events))
+	for i, e := range events {
+		seqs[i] = e.Seq
+	}
+	return seqs
+}
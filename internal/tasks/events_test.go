@@ -0,0 +1,142 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventTypes(events []Event) []EventType {
+	var types []EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestEvents_RecordsEveryMutation(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+
+	dep, err := tm.CreateTask("Schema")
+	require.NoError(t, err)
+	task, err := tm.CreateTask("Login")
+	require.NoError(t, err)
+	require.NoError(t, tm.MoveTask(dep.ID, "backlog", "done", models.StatusDone))
+	require.NoError(t, tm.AddDependency(task.ID, dep.ID))
+	require.NoError(t, tm.DecomposeTask(task.ID, []string{"Form", "Handler"}))
+	require.NoError(t, tm.ClaimTask(task.ID, "alice"))
+	_, err = tm.Heartbeat(task.ID, "alice", time.Hour)
+	require.NoError(t, err)
+
+	inProgress, err := tm.LoadTasks("in-progress")
+	require.NoError(t, err)
+	inProgress.Tasks[0].GithubPR = models.GithubPR{Number: 7}
+	require.NoError(t, tm.SaveTasks("in-progress", inProgress))
+
+	events, err := tm.Events(task.ID)
+	require.NoError(t, err)
+	// The claim records the worktree path in a second write; heartbeats are not recorded
+	assert.Equal(t, []EventType{EventCreate, EventDepend, EventDecompose, EventClaim, EventUpdate, EventPRLink},
+		eventTypes(events))
+
+	claim := events[3]
+	assert.Equal(t, "backlog", claim.FromList)
+	assert.Equal(t, "in-progress", claim.ToList)
+	assert.Contains(t, claim.Fields, "assigned_to")
+	assert.Empty(t, claim.Before.AssignedTo)
+	assert.Equal(t, "alice", claim.After.AssignedTo)
+
+	all, err := tm.Events("")
+	require.NoError(t, err)
+	require.Len(t, all, 8)
+	for i, e := range all {
+		assert.Equal(t, int64(i+1), e.Seq)
+	}
+	assert.Equal(t, EventComplete, all[2].Type)
+	assert.Equal(t, dep.ID, all[2].TaskID)
+}
+
+func TestUndo_RestoresListAndFields(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("in-progress", &TaskList{Tasks: []models.Task{
+		{ID: "T-0", Status: models.StatusInProgress},
+		{ID: "T-1", Title: "Login", Status: models.StatusInProgress, AssignedTo: "alice"},
+		{ID: "T-2", Status: models.StatusInProgress},
+	}}))
+	require.NoError(t, tm.MoveTask("T-1", "in-progress", "done", models.StatusDone))
+
+	reverted, err := tm.Undo("T-1", 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, EventComplete, reverted[0].Type)
+
+	task, list, err := tm.FindTask("T-1")
+	require.NoError(t, err)
+	assert.Equal(t, "in-progress", list)
+	assert.Equal(t, models.StatusInProgress, task.Status)
+	assert.Equal(t, "alice", task.AssignedTo)
+	assert.True(t, task.CompletedAt.IsZero())
+	done, _ := tm.LoadTasks("done")
+	assert.Empty(t, done.Tasks)
+
+	// The complete is undone; the next undo reaches the initial save,
+	// which created the task, so it disappears
+	_, err = tm.Undo("T-1", 2)
+	assert.EqualError(t, err, "task T-1 has only 1 event(s) to undo")
+	_, err = tm.Undo("T-1", 1)
+	require.NoError(t, err)
+	task, _, err = tm.FindTask("T-1")
+	require.NoError(t, err)
+	assert.Nil(t, task)
+	_, err = tm.Undo("T-1", 1)
+	assert.EqualError(t, err, "no events to undo for task T-1")
+
+	events, err := tm.Events("T-1")
+	require.NoError(t, err)
+	assert.Equal(t, []EventType{EventCreate, EventComplete, EventUndo, EventUndo}, eventTypes(events))
+	assert.Equal(t, []int64{events[1].Seq}, events[2].Undoes)
+}
+
+func TestUndo_InPlaceAndOutsideChanges(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{{ID: "T-1", Title: "Old"}, {ID: "T-2"}}}))
+	require.NoError(t, tm.AddDependency("T-1", "T-2"))
+
+	_, err := tm.Undo("T-1", 1)
+	require.NoError(t, err)
+	backlog, _ := tm.LoadTasks("backlog")
+	assert.Equal(t, "T-1", backlog.Tasks[0].ID, "an update is undone in place")
+	assert.Empty(t, backlog.Tasks[0].DependsOn)
+
+	// A hand edit the log never saw blocks undo
+	data, err := os.ReadFile(filepath.Join(tmpDir, "backlog.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "backlog.yaml"),
+		[]byte(strings.Replace(string(data), "title: Old", "title: Edited", 1)), 0644))
+	_, err = tm.Undo("T-1", 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "changed outside the event log")
+}
+
+func TestLastEventSeq_LongLines(t *testing.T) {
+	path := filepath.Join(setupTestDir(t), EventLogFileName)
+	seq, err := lastEventSeq(path)
+	require.NoError(t, err)
+	assert.Zero(t, seq)
+
+	long := strings.Repeat("x", 10000)
+	content := `{"seq":1,"task_id":"T-1"}` + "\n" + `{"seq":2,"task_id":"T-1","after":{"Description":"` + long + `"}}` + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	seq, err = lastEventSeq(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), seq)
+}
@@ -0,0 +1,34 @@
+//go:build !unix
+
+package tasks
+
+import (
+	"os"
+	"sync"
+)
+
+// On platforms without flock, locking falls back to an in-process mutex
+// keyed by lock file path. This protects goroutines within one process only.
+var (
+	fallbackLocksMu sync.Mutex
+	fallbackLocks   = map[string]bool{}
+)
+
+// tryLockFile attempts to take the in-process lock for f's path.
+func tryLockFile(f *os.File) (bool, error) {
+	fallbackLocksMu.Lock()
+	defer fallbackLocksMu.Unlock()
+	if fallbackLocks[f.Name()] {
+		return false, nil
+	}
+	fallbackLocks[f.Name()] = true
+	return true, nil
+}
+
+// unlockFile releases a lock taken with tryLockFile.
+func unlockFile(f *os.File) error {
+	fallbackLocksMu.Lock()
+	defer fallbackLocksMu.Unlock()
+	delete(fallbackLocks, f.Name())
+	return nil
+}
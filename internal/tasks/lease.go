@@ -0,0 +1,229 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// DefaultClaimLease is how long a claim lasts without a heartbeat unless
+// workflow.claim_lease says otherwise.
+const DefaultClaimLease = 30 * time.Minute
+
+// ClaimLease returns the lease duration configured in cfg. An invalid value
+// is an error, returned together with DefaultClaimLease.
+func ClaimLease(cfg *models.Config) (time.Duration, error) {
+	if cfg == nil || cfg.Workflow.ClaimLease == "" {
+		return DefaultClaimLease, nil
+	}
+	lease, err := time.ParseDuration(cfg.Workflow.ClaimLease)
+	if err != nil || lease <= 0 {
+		return DefaultClaimLease, fmt.Errorf("workflow.claim_lease: invalid duration %q", cfg.Workflow.ClaimLease)
+	}
+	return lease, nil
+}
+
+// LeaseExpiry returns when the claim on t lapses: its LeaseExpiresAt, or
+// ClaimedAt plus lease for claims made before leases were recorded. It is
+// zero for an unclaimed task.
+func LeaseExpiry(t *models.Task, lease time.Duration) time.Time {
+	if !t.LeaseExpiresAt.IsZero() {
+		return t.LeaseExpiresAt
+	}
+	if t.ClaimedAt.IsZero() {
+		return time.Time{}
+	}
+	return t.ClaimedAt.Add(lease)
+}
+
+// LeaseExpired reports whether the claim on t has lapsed by now.
+func LeaseExpired(t *models.Task, now time.Time, lease time.Duration) bool {
+	expiry := LeaseExpiry(t, lease)
+	return !expiry.IsZero() && now.After(expiry)
+}
+
+// Heartbeat renews the lease on an in-progress task for another lease
+// period. A non-empty assignee must match the claimant, so one agent cannot
+// keep another's claim alive.
+func (tm *TaskManager) Heartbeat(taskID, assignee string, lease time.Duration) (*models.Task, error) {
+	var renewed models.Task
+	err := tm.Update(func(tx *Tx) error {
+		inProgress, err := tx.Load("in-progress")
+		if err != nil {
+			return err
+		}
+		for i := range inProgress.Tasks {
+			t := &inProgress.Tasks[i]
+			if t.ID != taskID {
+				continue
+			}
+			if assignee != "" && t.AssignedTo != assignee {
+				return fmt.Errorf("task %s is claimed by %s, not %s", taskID, t.AssignedTo, assignee)
+			}
+			now := time.Now()
+			t.HeartbeatAt = now
+			t.LeaseExpiresAt = now.Add(lease)
+			renewed = *t
+			tx.Save("in-progress", inProgress)
+			return nil
+		}
+		return fmt.Errorf("task %s is not in progress", taskID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &renewed, nil
+}
+
+// ReleaseTask returns an in-progress task to the backlog, unclaimed. The
+// worktree is kept for the next claimant to continue in, unless
+// discardWorktree is set, in which case it and its branch are removed. The
+// task is returned as it was before the release.
+func (tm *TaskManager) ReleaseTask(taskID string, discardWorktree bool) (*models.Task, error) {
+	var released *models.Task
+	err := tm.Update(func(tx *Tx) error {
+		var err error
+		released, err = releaseInTx(tx, taskID, discardWorktree, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if discardWorktree {
+		discardTaskWorktree(released)
+	}
+	return released, nil
+}
+
+// ExpiredClaims returns the in-progress tasks whose lease has lapsed by
+// now. lease applies to claims made before leases were recorded.
+func (tm *TaskManager) ExpiredClaims(now time.Time, lease time.Duration) ([]models.Task, error) {
+	inProgress, err := tm.LoadTasks("in-progress")
+	if err != nil {
+		return nil, err
+	}
+	var expired []models.Task
+	for _, t := range inProgress.Tasks {
+		if LeaseExpired(&t, now, lease) {
+			expired = append(expired, t)
+		}
+	}
+	return expired, nil
+}
+
+// ReclaimExpired releases every in-progress task whose lease has lapsed by
+// now, as ReleaseTask does. Tasks rejected by eligible (if set) are left
+// alone. Expiry is checked again under the task lock, so a heartbeat that
+// lands first keeps its claim.
+func (tm *TaskManager) ReclaimExpired(now time.Time, lease time.Duration, discardWorktree bool, eligible func(*models.Task) bool) ([]models.Task, error) {
+	expired, err := tm.ExpiredClaims(now, lease)
+	if err != nil {
+		return nil, err
+	}
+
+	var reclaimed []models.Task
+	for i := range expired {
+		if eligible != nil && !eligible(&expired[i]) {
+			continue
+		}
+		var released *models.Task
+		err := tm.Update(func(tx *Tx) error {
+			var err error
+			released, err = releaseInTx(tx, expired[i].ID, discardWorktree, func(t *models.Task) bool {
+				return LeaseExpired(t, now, lease)
+			})
+			return err
+		})
+		if err != nil {
+			return reclaimed, err
+		}
+		if released == nil {
+			continue // renewed or released meanwhile
+		}
+		if discardWorktree {
+			discardTaskWorktree(released)
+		}
+		reclaimed = append(reclaimed, *released)
+	}
+	return reclaimed, nil
+}
+
+// releaseInTx moves an in-progress task back to the backlog and clears its
+// claim, returning the task as it was. When expired is set and rejects the
+// stored task, nothing changes and nil is returned.
+func releaseInTx(tx *Tx, taskID string, discardWorktree bool, expired func(*models.Task) bool) (*models.Task, error) {
+	inProgress, err := tx.Load("in-progress")
+	if err != nil {
+		return nil, err
+	}
+
+	var original models.Task
+	found := false
+	remaining := []models.Task{}
+	for _, t := range inProgress.Tasks {
+		if t.ID == taskID && !found {
+			original = t
+			found = true
+			continue
+		}
+		remaining = append(remaining, t)
+	}
+	if !found {
+		if expired != nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("task %s is not in progress", taskID)
+	}
+	if expired != nil && !expired(&original) {
+		return nil, nil
+	}
+
+	task := original
+	task.Status = models.StatusPending
+	task.AssignedTo = ""
+	task.ClaimedAt = time.Time{}
+	task.CompletedAt = time.Time{}
+	task.LeaseExpiresAt = time.Time{}
+	task.HeartbeatAt = time.Time{}
+	if discardWorktree {
+		task.WorktreePath = ""
+		task.Branch = ""
+	}
+
+	inProgress.Tasks = remaining
+	tx.Save("in-progress", inProgress)
+
+	backlog, err := tx.Load("backlog")
+	if err != nil {
+		return nil, err
+	}
+	backlog.Tasks = append(backlog.Tasks, task)
+	tx.Save("backlog", backlog)
+	return &original, nil
+}
+
+// discardTaskWorktree removes the worktree of a released task and the branch
+// it was created with. Tasks without a worktree on disk (such as synthetic
+// paths outside git) are left alone. Failures are warnings: the task is
+// already back in the backlog.
+func discardTaskWorktree(t *models.Task) {
+	if t.WorktreePath == "" {
+		return
+	}
+	if _, err := os.Stat(t.WorktreePath); err != nil {
+		return
+	}
+	if err := CleanupWorktree(t.WorktreePath); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not remove worktree: %v\n", err)
+		return
+	}
+	if t.Branch != "" {
+		if out, err := exec.Command("git", "branch", "-D", t.Branch).CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not delete branch %s: %s\n", t.Branch, strings.TrimSpace(string(out)))
+		}
+	}
+}
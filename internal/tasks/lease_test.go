@@ -0,0 +1,143 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupClaimedTask(t *testing.T) *TaskManager {
+	t.Helper()
+	tm := NewTaskManager(setupTestDir(t))
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "TASK-001", Title: "Test Task", Status: models.StatusPending},
+	}}))
+	require.NoError(t, tm.SaveTasks("in-progress", &TaskList{Tasks: []models.Task{}}))
+	require.NoError(t, tm.ClaimTask("TASK-001", "alice"))
+	return tm
+}
+
+func TestClaimLease(t *testing.T) {
+	lease, err := ClaimLease(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultClaimLease, lease)
+
+	lease, err = ClaimLease(&models.Config{Workflow: models.WorkflowConfig{ClaimLease: "2h"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, lease)
+
+	lease, err = ClaimLease(&models.Config{Workflow: models.WorkflowConfig{ClaimLease: "soon"}})
+	assert.EqualError(t, err, `workflow.claim_lease: invalid duration "soon"`)
+	assert.Equal(t, DefaultClaimLease, lease)
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	claimed := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, LeaseExpiry(&models.Task{}, time.Hour).IsZero(), "unclaimed tasks have no lease")
+	legacy := &models.Task{ClaimedAt: claimed}
+	assert.Equal(t, claimed.Add(time.Hour), LeaseExpiry(legacy, time.Hour))
+	assert.False(t, LeaseExpired(legacy, claimed.Add(59*time.Minute), time.Hour))
+	assert.True(t, LeaseExpired(legacy, claimed.Add(61*time.Minute), time.Hour))
+
+	renewed := &models.Task{ClaimedAt: claimed, LeaseExpiresAt: claimed.Add(3 * time.Hour)}
+	assert.False(t, LeaseExpired(renewed, claimed.Add(2*time.Hour), time.Hour))
+}
+
+func TestHeartbeat(t *testing.T) {
+	tm := setupClaimedTask(t)
+
+	task, _, err := tm.FindTask("TASK-001")
+	require.NoError(t, err)
+	assert.WithinDuration(t, task.ClaimedAt.Add(DefaultClaimLease), task.LeaseExpiresAt, time.Second, "a claim starts a lease")
+
+	renewed, err := tm.Heartbeat("TASK-001", "alice", 2*time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), renewed.LeaseExpiresAt, time.Second)
+	assert.False(t, renewed.HeartbeatAt.IsZero())
+
+	_, err = tm.Heartbeat("TASK-001", "bob", time.Hour)
+	assert.EqualError(t, err, "task TASK-001 is claimed by alice, not bob")
+	_, err = tm.Heartbeat("TASK-404", "", time.Hour)
+	assert.EqualError(t, err, "task TASK-404 is not in progress")
+}
+
+func TestReleaseTask(t *testing.T) {
+	tm := setupClaimedTask(t)
+	claimed, _, err := tm.FindTask("TASK-001")
+	require.NoError(t, err)
+	require.NotEmpty(t, claimed.WorktreePath)
+
+	released, err := tm.ReleaseTask("TASK-001", false)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", released.AssignedTo, "the task is returned as it was claimed")
+
+	task, list, err := tm.FindTask("TASK-001")
+	require.NoError(t, err)
+	assert.Equal(t, "backlog", list)
+	assert.Equal(t, models.StatusPending, task.Status)
+	assert.Empty(t, task.AssignedTo)
+	assert.True(t, task.ClaimedAt.IsZero())
+	assert.True(t, task.LeaseExpiresAt.IsZero())
+	assert.Equal(t, claimed.WorktreePath, task.WorktreePath, "the worktree is kept by default")
+
+	_, err = tm.ReleaseTask("TASK-001", false)
+	assert.EqualError(t, err, "task TASK-001 is not in progress")
+
+	require.NoError(t, tm.ClaimTask("TASK-001", "bob"))
+	_, err = tm.ReleaseTask("TASK-001", true)
+	require.NoError(t, err)
+	task, _, err = tm.FindTask("TASK-001")
+	require.NoError(t, err)
+	assert.Empty(t, task.WorktreePath)
+	assert.Empty(t, task.Branch)
+}
+
+func TestClaimTask_ReusesKeptWorktree(t *testing.T) {
+	tm := setupClaimedTask(t)
+	_, err := tm.ReleaseTask("TASK-001", false)
+	require.NoError(t, err)
+
+	kept := filepath.Join(t.TempDir(), "kept")
+	require.NoError(t, os.MkdirAll(kept, 0755))
+	require.NoError(t, tm.updateTaskInList("TASK-001", "backlog", func(t *models.Task) { t.WorktreePath = kept }))
+
+	require.NoError(t, tm.ClaimTask("TASK-001", "bob"))
+	task, _, err := tm.FindTask("TASK-001")
+	require.NoError(t, err)
+	assert.Equal(t, kept, task.WorktreePath)
+	assert.Equal(t, "bob", task.AssignedTo)
+}
+
+func TestReclaimExpired(t *testing.T) {
+	tm := NewTaskManager(setupTestDir(t))
+	now := time.Now()
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{}}))
+	require.NoError(t, tm.SaveTasks("in-progress", &TaskList{Tasks: []models.Task{
+		{ID: "STALE", Status: models.StatusInProgress, AssignedTo: "alice", ClaimedAt: now.Add(-2 * time.Hour), LeaseExpiresAt: now.Add(-time.Hour)},
+		{ID: "LEGACY", Status: models.StatusInProgress, AssignedTo: "bob", ClaimedAt: now.Add(-2 * time.Hour)},
+		{ID: "MINE", Status: models.StatusInProgress, AssignedTo: "carol", ClaimedAt: now.Add(-2 * time.Hour)},
+		{ID: "LIVE", Status: models.StatusInProgress, AssignedTo: "dave", ClaimedAt: now.Add(-2 * time.Hour), LeaseExpiresAt: now.Add(time.Hour)},
+	}}))
+
+	expired, err := tm.ExpiredClaims(now, time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, expired, 3)
+
+	reclaimed, err := tm.ReclaimExpired(now, time.Hour, false, func(t *models.Task) bool { return t.AssignedTo != "carol" })
+	require.NoError(t, err)
+	require.Len(t, reclaimed, 2)
+	assert.Equal(t, "STALE", reclaimed[0].ID)
+	assert.Equal(t, "LEGACY", reclaimed[1].ID)
+
+	inProgress, err := tm.LoadTasks("in-progress")
+	require.NoError(t, err)
+	require.Len(t, inProgress.Tasks, 2)
+	assert.Equal(t, "MINE", inProgress.Tasks[0].ID)
+	assert.Equal(t, "LIVE", inProgress.Tasks[1].ID)
+}
@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -11,27 +12,81 @@ import (
 
 // ClaimTask claims a task from backlog, recording claim time and git branch.
 // NEW: Also creates an isolated git worktree for safe development.
+//
+// The claim is reserved under the task lock before the (slow) worktree setup
+// runs, so concurrent claimers of the same task fail fast instead of racing.
+// If worktree setup fails, the reservation is rolled back to the backlog.
+//
+// The claim holds a lease of DefaultClaimLease; see Heartbeat.
 func (tm *TaskManager) ClaimTask(taskID string, assignee string) error {
-	// Find task in backlog
-	backlog, err := tm.LoadTasks("backlog")
+	return tm.claim(taskID, assignee, DefaultClaimLease)
+}
+
+func (tm *TaskManager) claim(taskID, assignee string, lease time.Duration) error {
+	var original models.Task
+	err := tm.Update(func(tx *Tx) error {
+		backlog, err := tx.Load("backlog")
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, t := range backlog.Tasks {
+			if t.ID == taskID {
+				original = t
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("task %s not found in backlog (can only claim pending tasks)", taskID)
+		}
+
+		// Prerequisites must be done before the task can start
+		if len(original.DependsOn) > 0 {
+			done, err := tx.Load("done")
+			if err != nil {
+				return err
+			}
+			completed := make(map[string]bool, len(done.Tasks))
+			for _, t := range done.Tasks {
+				completed[t.ID] = true
+			}
+			if unmet := UnmetDependencies(&original, completed); len(unmet) > 0 {
+				return fmt.Errorf("task %s is blocked by unfinished dependencies: %s", taskID, strings.Join(unmet, ", "))
+			}
+		}
+
+		claimed, err := moveTaskInTx(tx, taskID, "backlog", "in-progress", models.StatusInProgress, nil)
+		if err != nil {
+			return err
+		}
+
+		inProgress, _ := tx.Load("in-progress")
+		for i := range inProgress.Tasks {
+			if inProgress.Tasks[i].ID == claimed.ID {
+				t := &inProgress.Tasks[i]
+				t.AssignedTo = assignee
+				t.ClaimedAt = time.Now()
+				t.LeaseExpiresAt = t.ClaimedAt.Add(lease)
+				t.HeartbeatAt = time.Time{}
+				t.CompletedAt = time.Time{}
+				t.Branch = fmt.Sprintf("feature/task-%s", taskID) // NEW: Explicit branch name
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	var task models.Task
-	found := false
-	for _, t := range backlog.Tasks {
-		if t.ID == taskID {
-			task = t
-			found = true
-			break
+	// A worktree kept by an earlier release is picked up where it was left
+	if original.WorktreePath != "" {
+		if _, err := os.Stat(original.WorktreePath); err == nil {
+			return nil
 		}
 	}
 
-	if !found {
-		return fmt.Errorf("task %s not found in backlog (can only claim pending tasks)", taskID)
-	}
-
 	// NEW: Create isolated git worktree for this task
 	// Use the task manager's baseDir if it looks like a temp directory, otherwise use "."
 	repoRoot := "."
@@ -44,35 +99,58 @@ func (tm *TaskManager) ClaimTask(taskID string, assignee string) error {
 		RepoRoot: repoRoot,
 	}
 
-	worktreePath, err := CreateWorktree(wtCfg)
-	if err != nil {
-		return fmt.Errorf("failed to create worktree: %w", err)
-	}
-
-	newBacklogTasks := []models.Task{}
-	for _, t := range backlog.Tasks {
-		if t.ID != taskID {
-			newBacklogTasks = append(newBacklogTasks, t)
+	worktreePath, wtErr := CreateWorktree(wtCfg)
+	if wtErr != nil {
+		if err := tm.unclaim(taskID, original); err != nil {
+			return fmt.Errorf("failed to create worktree: %w (rollback failed: %v)", wtErr, err)
 		}
-	}
-	backlog.Tasks = newBacklogTasks
-	if err := tm.SaveTasks("backlog", backlog); err != nil {
-		return err
+		return fmt.Errorf("failed to create worktree: %w", wtErr)
 	}
 
-	inProgress, err := tm.LoadTasks("in-progress")
+	err = tm.updateTaskInList(taskID, "in-progress", func(t *models.Task) {
+		t.WorktreePath = worktreePath // NEW: Store worktree path
+	})
 	if err != nil {
-		return err
+		// A claim without its worktree path could not be resumed later;
+		// the worktree is fresh, so nothing is lost by removing it
+		recordErr := fmt.Errorf("failed to record worktree %s: %w", worktreePath, err)
+		if err := tm.unclaim(taskID, original); err != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", recordErr, err)
+		}
+		if _, statErr := os.Stat(worktreePath); statErr == nil {
+			if rmErr := removeWorktree(worktreePath); rmErr != nil {
+				return fmt.Errorf("%w (worktree cleanup failed: %v)", recordErr, rmErr)
+			}
+		}
+		return recordErr
 	}
+	return nil
+}
 
-	task.Status = models.StatusInProgress
-	task.AssignedTo = assignee
-	task.ClaimedAt = time.Now()
-	task.Branch = fmt.Sprintf("feature/task-%s", taskID) // NEW: Explicit branch name
-	task.WorktreePath = worktreePath                     // NEW: Store worktree path
-	inProgress.Tasks = append(inProgress.Tasks, task)
+// unclaim returns a reserved task to the backlog in its pre-claim form.
+func (tm *TaskManager) unclaim(taskID string, original models.Task) error {
+	return tm.Update(func(tx *Tx) error {
+		inProgress, err := tx.Load("in-progress")
+		if err != nil {
+			return err
+		}
+		remaining := []models.Task{}
+		for _, t := range inProgress.Tasks {
+			if t.ID != taskID {
+				remaining = append(remaining, t)
+			}
+		}
+		inProgress.Tasks = remaining
+		tx.Save("in-progress", inProgress)
 
-	return tm.SaveTasks("in-progress", inProgress)
+		backlog, err := tx.Load("backlog")
+		if err != nil {
+			return err
+		}
+		backlog.Tasks = append(backlog.Tasks, original)
+		tx.Save("backlog", backlog)
+		return nil
+	})
 }
 
 // currentGitBranch returns the current git branch, or empty string if not in a repo.
@@ -85,7 +163,9 @@ func currentGitBranch() string {
 }
 
 // ClaimTaskWithConfig claims a task after running readiness checks.
-// Readiness failures are printed as warnings but do not block the claim.
+// Readiness failures are printed as warnings but do not block the claim,
+// except unfinished dependencies, which ClaimTask always rejects. The lease
+// comes from workflow.claim_lease.
 func (tm *TaskManager) ClaimTaskWithConfig(taskID, assignee string, cfg *models.Config) error {
 	// Find the task to run readiness checks before claiming
 	backlog, err := tm.LoadTasks("backlog")
@@ -102,11 +182,19 @@ func (tm *TaskManager) ClaimTaskWithConfig(taskID, assignee string, cfg *models.
 	}
 
 	if task != nil {
-		result := CanClaimTask(task, cfg)
+		completed, err := tm.CompletedTaskIDs()
+		if err != nil {
+			return err
+		}
+		result := CanClaimTaskWithDeps(task, cfg, completed)
 		if len(result.Checks) > 0 {
 			fmt.Print(FormatReadinessResult(result))
 		}
 	}
 
-	return tm.ClaimTask(taskID, assignee)
+	lease, err := ClaimLease(cfg)
+	if err != nil {
+		return err
+	}
+	return tm.claim(taskID, assignee, lease)
 }
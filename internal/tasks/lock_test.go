@@ -1,6 +1,7 @@
 package tasks
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/javierbenavides/agentic-agent/pkg/models"
@@ -169,3 +170,36 @@ func TestClaimTask_PreservesTaskFields(t *testing.T) {
 	assert.Len(t, task.SubTasks, 1)
 	assert.Equal(t, "TASK-001.1", task.SubTasks[0].ID)
 }
+
+// failingStore fails the commit numbered failAt (1-based).
+type failingStore struct {
+	TaskStore
+	commits, failAt int
+}
+
+func (s *failingStore) Commit(lists map[string]*TaskList) error {
+	s.commits++
+	if s.commits == s.failAt {
+		return errors.New("disk full")
+	}
+	return s.TaskStore.Commit(lists)
+}
+
+func TestClaimTask_RollsBackWhenWorktreeCannotBeRecorded(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	require.NoError(t, NewTaskManager(tmpDir).SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "TASK-001", Title: "Login", Status: models.StatusPending},
+	}}))
+
+	// The claim itself commits; recording the worktree path fails
+	tm := NewTaskManagerWithStore(tmpDir, &failingStore{TaskStore: NewYAMLStore(tmpDir), failAt: 2})
+	err := tm.ClaimTask("TASK-001", "alice")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to record worktree")
+	assert.Contains(t, err.Error(), "disk full")
+
+	task, list, err := tm.FindTask("TASK-001")
+	require.NoError(t, err)
+	assert.Equal(t, "backlog", list)
+	assert.Empty(t, task.AssignedTo)
+}
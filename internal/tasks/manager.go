@@ -4,13 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/javierbenavides/agentic-agent/pkg/models"
-	"gopkg.in/yaml.v3"
 )
 
 // taskSeq is an atomic counter to ensure unique IDs when multiple tasks
@@ -23,57 +21,61 @@ type TaskList struct {
 
 type TaskManager struct {
 	baseDir        string
+	store          TaskStore
+	detected       bool // store was picked by DetectStore and follows migrations
 	progressWriter *ProgressWriter
 	agentsMdHelper *AgentsMdHelper
 }
 
+// NewTaskManager manages the tasks in baseDir with the backend found there
+// (see DetectStore).
 func NewTaskManager(baseDir string) *TaskManager {
-	return &TaskManager{baseDir: baseDir}
+	return NewTaskManagerWithStore(baseDir, nil)
 }
 
-func NewTaskManagerWithTracking(baseDir string, progressWriter *ProgressWriter, agentsMdHelper *AgentsMdHelper) *TaskManager {
-	return &TaskManager{
-		baseDir:        baseDir,
-		progressWriter: progressWriter,
-		agentsMdHelper: agentsMdHelper,
+// NewTaskManagerWithStore manages the tasks in baseDir through store, or
+// the detected backend when store is nil.
+func NewTaskManagerWithStore(baseDir string, store TaskStore) *TaskManager {
+	detected := store == nil
+	if detected {
+		store, _ = OpenStore(baseDir, DetectStore(baseDir))
 	}
+	return &TaskManager{baseDir: baseDir, store: store, detected: detected}
 }
 
-func (tm *TaskManager) LoadTasks(listType string) (*TaskList, error) {
-	path := filepath.Join(tm.baseDir, listType+".yaml")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &TaskList{Tasks: []models.Task{}}, nil
-		}
-		return nil, err
-	}
+func NewTaskManagerWithTracking(baseDir string, progressWriter *ProgressWriter, agentsMdHelper *AgentsMdHelper) *TaskManager {
+	tm := NewTaskManager(baseDir)
+	tm.progressWriter = progressWriter
+	tm.agentsMdHelper = agentsMdHelper
+	return tm
+}
 
-	var list TaskList
-	if err := yaml.Unmarshal(data, &list); err != nil {
-		return nil, err
+// StoreKind reports which backend holds the tasks ("yaml" or "bolt").
+func (tm *TaskManager) StoreKind() string {
+	switch tm.store.(type) {
+	case *boltStore:
+		return StoreBolt
+	case *yamlStore:
+		return StoreYAML
 	}
-	return &list, nil
+	return "custom"
+}
+
+func (tm *TaskManager) LoadTasks(listType string) (*TaskList, error) {
+	return tm.store.Load(listType)
 }
 
+// SaveTasks replaces a whole list atomically while holding the task lock.
 func (tm *TaskManager) SaveTasks(listType string, list *TaskList) error {
-	if err := os.MkdirAll(tm.baseDir, 0755); err != nil {
-		return fmt.Errorf("failed to create tasks directory: %w", err)
-	}
-	path := filepath.Join(tm.baseDir, listType+".yaml")
-	data, err := yaml.Marshal(list)
+	unlock, err := tm.lock()
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	defer unlock()
+	return tm.commit(map[string]*TaskList{listType: list}, nil)
 }
 
 func (tm *TaskManager) CreateTask(title string) (*models.Task, error) {
-	backlog, err := tm.LoadTasks("backlog")
-	if err != nil {
-		return nil, err
-	}
-
 	// Unique ID: 6-digit truncated timestamp + atomic sequence to avoid collisions
 	seq := atomic.AddUint64(&taskSeq, 1)
 	id := fmt.Sprintf("TASK-%06d-%d", time.Now().Unix()%1000000, seq)
@@ -84,8 +86,16 @@ func (tm *TaskManager) CreateTask(title string) (*models.Task, error) {
 		Status: models.StatusPending,
 	}
 
-	backlog.Tasks = append(backlog.Tasks, task)
-	if err := tm.SaveTasks("backlog", backlog); err != nil {
+	err := tm.Update(func(tx *Tx) error {
+		backlog, err := tx.Load("backlog")
+		if err != nil {
+			return err
+		}
+		backlog.Tasks = append(backlog.Tasks, task)
+		tx.Save("backlog", backlog)
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -93,10 +103,41 @@ func (tm *TaskManager) CreateTask(title string) (*models.Task, error) {
 }
 
 func (tm *TaskManager) MoveTask(taskID string, fromType, toType string, newStatus models.TaskStatus) error {
-	fromList, err := tm.LoadTasks(fromType)
+	// Read the branch's commits before taking the lock
+	task, _, err := tm.FindTask(taskID)
 	if err != nil {
 		return err
 	}
+	commits := branchCommits(task)
+
+	var moved *models.Task
+	err = tm.Update(func(tx *Tx) error {
+		var err error
+		moved, err = moveTaskInTx(tx, taskID, fromType, toType, newStatus, commits)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// NEW: Auto-delete worktree when completing task (after the move is durable)
+	if toType == "done" && moved.WorktreePath != "" {
+		if err := CleanupWorktree(moved.WorktreePath); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not clean up worktree: %v\n", err)
+			// Don't fail task completion if cleanup fails
+		}
+	}
+	return nil
+}
+
+// moveTaskInTx removes a task from one list and appends it to another
+// within a transaction, returning the moved task. Non-empty commits replace
+// the task's recorded commits.
+func moveTaskInTx(tx *Tx, taskID string, fromType, toType string, newStatus models.TaskStatus, commits []string) (*models.Task, error) {
+	fromList, err := tx.Load(fromType)
+	if err != nil {
+		return nil, err
+	}
 
 	var taskToMove models.Task
 	found := false
@@ -112,84 +153,63 @@ func (tm *TaskManager) MoveTask(taskID string, fromType, toType string, newStatu
 	}
 
 	if !found {
-		return fmt.Errorf("task %s not found in %s", taskID, fromType)
+		return nil, fmt.Errorf("task %s not found in %s", taskID, fromType)
 	}
 
-	// NEW: Capture commits from the task's branch before cleanup
-	if taskToMove.Branch != "" && taskToMove.WorktreePath != "" {
-		since := taskToMove.ClaimedAt.Format(time.RFC3339)
-		commits, err := CaptureCommits(taskToMove.Branch, ".", since)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "⚠️  Could not capture commits: %v\n", err)
-		} else if len(commits) > 0 {
-			taskToMove.Commits = commits
-			fmt.Fprintf(os.Stderr, "✅ Captured %d commits\n", len(commits))
-		}
-	}
-
-	// NEW: Auto-delete worktree when completing task
-	if toType == "done" && taskToMove.WorktreePath != "" {
-		if err := CleanupWorktree(taskToMove.WorktreePath); err != nil {
-			fmt.Fprintf(os.Stderr, "⚠️  Could not clean up worktree: %v\n", err)
-			// Don't fail task completion if cleanup fails
-		}
+	if len(commits) > 0 {
+		taskToMove.Commits = commits
 	}
 
 	fromList.Tasks = newFromTasks
-	if err := tm.SaveTasks(fromType, fromList); err != nil {
-		return err
-	}
+	tx.Save(fromType, fromList)
 
-	toList, err := tm.LoadTasks(toType)
+	toList, err := tx.Load(toType)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	taskToMove.Status = newStatus
 	taskToMove.CompletedAt = time.Now()
 	toList.Tasks = append(toList.Tasks, taskToMove)
-	return tm.SaveTasks(toType, toList)
+	tx.Save(toType, toList)
+
+	return &taskToMove, nil
+}
+
+// branchCommits captures the commits on a task's branch since it was
+// claimed. It runs git, so callers use it before taking the store lock.
+func branchCommits(task *models.Task) []string {
+	if task == nil || task.Branch == "" || task.WorktreePath == "" {
+		return nil
+	}
+	since := task.ClaimedAt.Format(time.RFC3339)
+	commits, err := CaptureCommits(task.Branch, ".", since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not capture commits: %v\n", err)
+		return nil
+	}
+	if len(commits) > 0 {
+		fmt.Fprintf(os.Stderr, "✅ Captured %d commits\n", len(commits))
+	}
+	return commits
 }
 
 // FindTask searches for a task across all lists (backlog, in-progress, done)
 // Returns the task, the source list name, and an error if any
 func (tm *TaskManager) FindTask(taskID string) (*models.Task, string, error) {
-	sources := []string{"backlog", "in-progress", "done"}
-
-	for _, source := range sources {
-		list, err := tm.LoadTasks(source)
-		if err != nil {
-			return nil, "", fmt.Errorf("error loading %s: %w", source, err)
-		}
-
-		for _, task := range list.Tasks {
-			if task.ID == taskID {
-				return &task, source, nil
-			}
-			// Also check subtasks
-			for _, subtask := range task.SubTasks {
-				if subtask.ID == taskID {
-					// Convert SubTask to Task for consistent return
-					fullTask := models.Task{
-						ID:         subtask.ID,
-						Title:      subtask.Title,
-						Status:     subtask.Status,
-						AssignedTo: subtask.AssignedTo,
-					}
-					return &fullTask, source, nil
-				}
-			}
-		}
-	}
+	return tm.store.Find(taskID)
+}
 
-	return nil, "", nil // Not found, but no error
+// TasksByTrack returns the tasks linked to a track across all lists.
+func (tm *TaskManager) TasksByTrack(trackID string) ([]ListedTask, error) {
+	return tm.store.ByTrack(trackID)
 }
 
 // CompleteTaskWithTracking marks a task as complete and logs progress.
 // If the task has a ClaimedAt timestamp, git commits since that time are auto-captured.
 func (tm *TaskManager) CompleteTaskWithTracking(taskID string, learnings []string, filesChanged []string, threadURL string) error {
 	// Find the task
-	task, source, err := tm.FindTask(taskID)
+	task, _, err := tm.FindTask(taskID)
 	if err != nil {
 		return err
 	}
@@ -197,27 +217,43 @@ func (tm *TaskManager) CompleteTaskWithTracking(taskID string, learnings []strin
 		return fmt.Errorf("task %s not found", taskID)
 	}
 
-	// Auto-populate git data if ClaimedAt is set and we're in a git repo
+	// Collect git data before taking the lock: commits since the claim, or
+	// those on the task's branch when it has one
+	var commits []string
 	if !task.ClaimedAt.IsZero() {
-		task.CompletedAt = time.Now()
 		gitCommits, gitFiles := collectGitData(task.ClaimedAt)
-		if len(gitCommits) > 0 {
-			task.Commits = gitCommits
-		}
+		commits = gitCommits
 		if len(filesChanged) == 0 && len(gitFiles) > 0 {
 			filesChanged = gitFiles
 		}
 	}
+	if captured := branchCommits(task); len(captured) > 0 {
+		commits = captured
+	}
 
-	// Move task to done (with updated fields)
-	if source != "done" {
-		// Update the task in its current list before moving
-		tm.updateTaskInList(taskID, source, func(t *models.Task) {
-			t.CompletedAt = task.CompletedAt
-			t.Commits = task.Commits
-		})
-		if err := tm.MoveTask(taskID, source, "done", models.StatusDone); err != nil {
-			return fmt.Errorf("failed to move task: %w", err)
+	// Move task to done from wherever it is once the lock is held
+	var moved *models.Task
+	err = tm.Update(func(tx *Tx) error {
+		current, source, _, err := findInTx(tx, taskID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return fmt.Errorf("task %s not found", taskID)
+		}
+		task = current
+		if source == "done" {
+			return nil
+		}
+		moved, err = moveTaskInTx(tx, taskID, source, "done", models.StatusDone, commits)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move task: %w", err)
+	}
+	if moved != nil && moved.WorktreePath != "" {
+		if err := CleanupWorktree(moved.WorktreePath); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not clean up worktree: %v\n", err)
 		}
 	}
 
@@ -246,18 +282,21 @@ func (tm *TaskManager) CompleteTaskWithTracking(taskID string, learnings []strin
 }
 
 // updateTaskInList modifies a task in place within a list.
-func (tm *TaskManager) updateTaskInList(taskID, listType string, update func(*models.Task)) {
-	list, err := tm.LoadTasks(listType)
-	if err != nil {
-		return
-	}
-	for i := range list.Tasks {
-		if list.Tasks[i].ID == taskID {
-			update(&list.Tasks[i])
-			tm.SaveTasks(listType, list)
-			return
+func (tm *TaskManager) updateTaskInList(taskID, listType string, update func(*models.Task)) error {
+	return tm.Update(func(tx *Tx) error {
+		list, err := tx.Load(listType)
+		if err != nil {
+			return err
 		}
-	}
+		for i := range list.Tasks {
+			if list.Tasks[i].ID == taskID {
+				update(&list.Tasks[i])
+				tx.Save(listType, list)
+				return nil
+			}
+		}
+		return fmt.Errorf("task %s not found in %s", taskID, listType)
+	})
 }
 
 // collectGitData gathers commit hashes and changed files since the given time.
@@ -0,0 +1,493 @@
+package tasks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// ListedTask is a task together with the list it was loaded from.
+type ListedTask struct {
+	List        string `yaml:"list"`
+	models.Task `yaml:",inline"`
+}
+
+// Query selects tasks by conditions on their fields. Every term must match.
+type Query struct {
+	terms []queryTerm
+}
+
+type queryTerm struct {
+	field  string
+	op     string // "=", "!=", "<", ">", "<=" or ">="
+	values []string
+	at     time.Time // for date fields
+	negate bool      // prefixed with "-"
+}
+
+// Query fields and the operators each accepts.
+var queryFields = map[string][]string{
+	"id":        {"=", "!="},
+	"status":    {"=", "!="},
+	"type":      {"=", "!="},
+	"track":     {"=", "!="},
+	"change":    {"=", "!="},
+	"assignee":  {"=", "!="},
+	"scope":     {"=", "!="},
+	"spec":      {"=", "!="},
+	"pr":        {"=", "!="},
+	"text":      {"=", "!="},
+	"claimed":   {"<", ">", "<=", ">="},
+	"completed": {"<", ">", "<=", ">="},
+}
+
+// QueryFields lists the field names a query can use.
+func QueryFields() []string {
+	names := make([]string, 0, len(queryFields))
+	for name := range queryFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var fieldAliases = map[string]string{
+	"track_id":    "track",
+	"change_id":   "change",
+	"assigned_to": "assignee",
+	"spec_ref":    "spec",
+	"has_pr":      "pr",
+}
+
+var termPattern = regexp.MustCompile(`^([a-z_]+)(!=|<=|>=|=|<|>)(.*)$`)
+
+// ParseQuery parses a query such as
+//
+//	status=in-progress type=review track=auth scope=internal/api
+//
+// Terms are separated by spaces (an "and" between them is allowed) and all
+// must match. A term is field, operator and value, and comma-separated
+// values match any of them. Values are compared without case and may use *
+// and ** globs; an empty value matches an empty field.
+//
+//	id, type, track, change, assignee   exact value or glob
+//	status                              backlog (or pending), in-progress, done
+//	scope                               a scope path that contains, or lies under, the value
+//	spec                                a spec ref equal to, ending in, or matching the value
+//	pr                                  true if the task has a GitHub PR
+//	text                                substring of the ID, title or description
+//	claimed, completed                  <, >, <= or >= a date (2006-01-02 or RFC 3339)
+//	                                    or an age such as 36h or 7d
+//
+// Any other word is free text, as with text=, and quote it to include
+// spaces. Prefix a term or word with "-" to exclude its matches. Ages are
+// measured from now.
+func ParseQuery(expr string, now time.Time) (*Query, error) {
+	words, err := splitQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	for _, word := range words {
+		if strings.EqualFold(word, "and") {
+			continue
+		}
+		term, err := parseTerm(word, now)
+		if err != nil {
+			return nil, err
+		}
+		q.terms = append(q.terms, term)
+	}
+	return q, nil
+}
+
+func parseTerm(word string, now time.Time) (queryTerm, error) {
+	negate := false
+	if strings.HasPrefix(word, "-") && len(word) > 1 {
+		negate = true
+		word = word[1:]
+	}
+	m := termPattern.FindStringSubmatch(word)
+	if m == nil {
+		return queryTerm{field: "text", op: "=", values: []string{word}, negate: negate}, nil
+	}
+
+	field, op, value := m[1], m[2], m[3]
+	if alias, ok := fieldAliases[field]; ok {
+		field = alias
+	}
+	ops, ok := queryFields[field]
+	if !ok {
+		return queryTerm{}, fmt.Errorf("unknown query field %q (expected one of %s)", m[1], strings.Join(QueryFields(), ", "))
+	}
+	if !containsString(ops, op) {
+		return queryTerm{}, fmt.Errorf("%s: field %s takes %s", word, field, strings.Join(ops, " "))
+	}
+
+	term := queryTerm{field: field, op: op, values: strings.Split(value, ","), negate: negate}
+	switch field {
+	case "claimed", "completed":
+		at, err := parseQueryTime(value, now)
+		if err != nil {
+			return queryTerm{}, fmt.Errorf("%s: %w", word, err)
+		}
+		term.at = at
+	case "pr":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return queryTerm{}, fmt.Errorf("%s: expected true or false", word)
+		}
+	case "status":
+		for i, v := range term.values {
+			list, ok := statusLists[strings.ToLower(v)]
+			if !ok {
+				return queryTerm{}, fmt.Errorf("%s: unknown status %q (expected backlog, in-progress or done)", word, v)
+			}
+			term.values[i] = list
+		}
+	}
+	return term, nil
+}
+
+var statusLists = map[string]string{
+	"backlog":     "backlog",
+	"pending":     "backlog",
+	"in-progress": "in-progress",
+	"done":        "done",
+}
+
+// splitQuery splits expr on spaces outside double or single quotes.
+func splitQuery(expr string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	var quote rune
+	inWord := false
+	for _, r := range expr {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			cur.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in query %q", expr)
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// parseQueryTime reads a date, an RFC 3339 time or an age before now.
+func parseQueryTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("expected a date (2006-01-02), an RFC 3339 time or an age such as 36h or 7d")
+}
+
+// Match reports whether t, loaded from list, satisfies every term.
+func (q *Query) Match(t *models.Task, list string) bool {
+	for _, term := range q.terms {
+		if term.match(t, list) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (term queryTerm) match(t *models.Task, list string) bool {
+	switch term.field {
+	case "claimed":
+		return compareTime(t.ClaimedAt, term.op, term.at)
+	case "completed":
+		return compareTime(t.CompletedAt, term.op, term.at)
+	}
+
+	matched := false
+	for _, v := range term.values {
+		if term.matchValue(t, list, v) {
+			matched = true
+			break
+		}
+	}
+	if term.op == "!=" {
+		return !matched
+	}
+	return matched
+}
+
+func (term queryTerm) matchValue(t *models.Task, list, value string) bool {
+	switch term.field {
+	case "id":
+		return matchQueryValue(value, t.ID)
+	case "status":
+		return list == value
+	case "type":
+		return matchQueryValue(value, t.Type)
+	case "track":
+		return matchQueryValue(value, t.TrackID)
+	case "change":
+		return matchQueryValue(value, t.ChangeID)
+	case "assignee":
+		return matchQueryValue(value, t.AssignedTo)
+	case "scope":
+		for _, s := range t.Scope {
+			if scopeTouches(s, value) {
+				return true
+			}
+		}
+		return value == "" && len(t.Scope) == 0
+	case "spec":
+		for _, ref := range t.SpecRefs {
+			if matchQueryValue(value, ref) || strings.HasSuffix(strings.ToLower(ref), "/"+strings.ToLower(value)) {
+				return true
+			}
+		}
+		return value == "" && len(t.SpecRefs) == 0
+	case "pr":
+		want, _ := strconv.ParseBool(value)
+		return (t.GithubPR.URL != "" || t.GithubPR.Number != 0) == want
+	case "text":
+		value = strings.ToLower(value)
+		for _, s := range []string{t.ID, t.Title, t.Description} {
+			if strings.Contains(strings.ToLower(s), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchQueryValue compares value to s without case, as a glob when value
+// contains one.
+func matchQueryValue(value, s string) bool {
+	if strings.ContainsAny(value, "*?[") {
+		return globMatch(strings.ToLower(value), strings.ToLower(s))
+	}
+	return strings.EqualFold(value, s)
+}
+
+// scopeTouches reports whether a task scope entry covers path: equal,
+// nested either way, or matching it as a glob.
+func scopeTouches(scope, path string) bool {
+	if strings.ContainsAny(path, "*?[") {
+		return globMatch(path, filepath.ToSlash(scope))
+	}
+	a, b := filepath.Clean(scope), filepath.Clean(path)
+	if a == b || a == "." {
+		return true
+	}
+	sep := string(filepath.Separator)
+	return strings.HasPrefix(a, b+sep) || strings.HasPrefix(b, a+sep)
+}
+
+// globMatch matches a slash-separated path against a glob in which *
+// stays within one path segment, ** spans any number of them, and [...]
+// is a character class.
+func globMatch(pattern, s string) bool {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				re.WriteString(".*")
+				i++
+			} else {
+				re.WriteString("[^/]*")
+			}
+		case '?':
+			re.WriteString("[^/]")
+		case '[':
+			if end := strings.IndexByte(pattern[i:], ']'); end > 1 {
+				class := pattern[i+1 : i+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				re.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+				i += end
+				break
+			}
+			re.WriteString(`\[`)
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	ok, _ := regexp.MatchString(re.String(), s)
+	return ok
+}
+
+func compareTime(t time.Time, op string, at time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	switch op {
+	case "<":
+		return t.Before(at)
+	case ">":
+		return t.After(at)
+	case "<=":
+		return !t.After(at)
+	case ">=":
+		return !t.Before(at)
+	}
+	return false
+}
+
+// ListTasks returns the tasks of every list that match q, in list order:
+// backlog, in-progress, then done. A nil q matches everything. A query on
+// a single track goes through the store's track index, and lists that a
+// status term rules out are not loaded.
+func (tm *TaskManager) ListTasks(q *Query) ([]ListedTask, error) {
+	var listed []ListedTask
+	if track, ok := q.indexedTrack(); ok {
+		candidates, err := tm.TasksByTrack(track)
+		if err != nil {
+			return nil, fmt.Errorf("loading track %s: %w", track, err)
+		}
+		for _, lt := range candidates {
+			if q.Match(&lt.Task, lt.List) {
+				listed = append(listed, lt)
+			}
+		}
+		return listed, nil
+	}
+
+	for _, list := range taskLists {
+		if !q.allowsList(list) {
+			continue
+		}
+		tl, err := tm.LoadTasks(list)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", list, err)
+		}
+		for _, t := range tl.Tasks {
+			if q == nil || q.Match(&t, list) {
+				listed = append(listed, ListedTask{List: list, Task: t})
+			}
+		}
+	}
+	return listed, nil
+}
+
+// indexedTrack returns the track a query requires when it names exactly
+// one, literally.
+func (q *Query) indexedTrack() (string, bool) {
+	if q == nil {
+		return "", false
+	}
+	for _, term := range q.terms {
+		if term.field == "track" && term.op == "=" && !term.negate && len(term.values) == 1 &&
+			term.values[0] != "" && !strings.ContainsAny(term.values[0], "*?[") {
+			return term.values[0], true
+		}
+	}
+	return "", false
+}
+
+// allowsList reports whether the status terms of a query admit list.
+func (q *Query) allowsList(list string) bool {
+	if q == nil {
+		return true
+	}
+	for _, term := range q.terms {
+		if term.field == "status" && term.match(&models.Task{}, list) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Sort keys accepted by SortTasks.
+var sortKeys = map[string]func(a, b *ListedTask) int{
+	"id":        func(a, b *ListedTask) int { return strings.Compare(a.ID, b.ID) },
+	"title":     func(a, b *ListedTask) int { return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)) },
+	"status":    func(a, b *ListedTask) int { return listRank[a.List] - listRank[b.List] },
+	"type":      func(a, b *ListedTask) int { return strings.Compare(a.Type, b.Type) },
+	"track":     func(a, b *ListedTask) int { return strings.Compare(a.TrackID, b.TrackID) },
+	"change":    func(a, b *ListedTask) int { return strings.Compare(a.ChangeID, b.ChangeID) },
+	"assignee":  func(a, b *ListedTask) int { return strings.Compare(a.AssignedTo, b.AssignedTo) },
+	"claimed":   func(a, b *ListedTask) int { return a.ClaimedAt.Compare(b.ClaimedAt) },
+	"completed": func(a, b *ListedTask) int { return a.CompletedAt.Compare(b.CompletedAt) },
+}
+
+var listRank = map[string]int{"backlog": 0, "in-progress": 1, "done": 2}
+
+// SortTasks orders listed by a comma-separated list of keys, each
+// descending when prefixed with "-", e.g. "status,-claimed". Ties keep
+// their current order. An empty spec leaves listed as it is.
+func SortTasks(listed []ListedTask, spec string) error {
+	if spec == "" {
+		return nil
+	}
+	type key struct {
+		cmp  func(a, b *ListedTask) int
+		desc bool
+	}
+	var keys []key
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		if alias, ok := fieldAliases[name]; ok {
+			name = alias
+		}
+		cmp, ok := sortKeys[name]
+		if !ok {
+			names := make([]string, 0, len(sortKeys))
+			for n := range sortKeys {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown sort key %q (expected one of %s)", name, strings.Join(names, ", "))
+		}
+		keys = append(keys, key{cmp, desc})
+	}
+
+	sort.SliceStable(listed, func(i, j int) bool {
+		for _, k := range keys {
+			c := k.cmp(&listed[i], &listed[j])
+			if k.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
@@ -0,0 +1,110 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupQueryTasks(t *testing.T) (*TaskManager, time.Time) {
+	t.Helper()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tm := NewTaskManager(setupTestDir(t))
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "T-1", Title: "Add login form", Type: "build", TrackID: "auth", Scope: []string{"web/login"}},
+		{ID: "T-2", Title: "Research rate limits", Type: "research", SpecRefs: []string{"api/limits.md"}},
+	}}))
+	require.NoError(t, tm.SaveTasks("in-progress", &TaskList{Tasks: []models.Task{
+		{ID: "T-3", Title: "Review API handlers", Type: "review", TrackID: "auth", AssignedTo: "alice",
+			Scope: []string{"internal/api/handlers"}, ClaimedAt: now.Add(-3 * 24 * time.Hour)},
+		{ID: "T-4", Title: "Review DB layer", Type: "review", TrackID: "storage", AssignedTo: "bob",
+			Scope: []string{"internal/db"}, ClaimedAt: now.Add(-time.Hour)},
+	}}))
+	require.NoError(t, tm.SaveTasks("done", &TaskList{Tasks: []models.Task{
+		{ID: "T-5", Title: "Set up API module", Description: "Rate limit middleware included", TrackID: "auth",
+			Scope: []string{"internal/api"}, CompletedAt: now.Add(-48 * time.Hour), GithubPR: models.GithubPR{Number: 12}},
+	}}))
+	return tm, now
+}
+
+func queryIDs(t *testing.T, tm *TaskManager, expr string, now time.Time) []string {
+	t.Helper()
+	q, err := ParseQuery(expr, now)
+	require.NoError(t, err, expr)
+	listed, err := tm.ListTasks(q)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, lt := range listed {
+		ids = append(ids, lt.ID)
+	}
+	return ids
+}
+
+func TestParseQuery_Match(t *testing.T) {
+	tm, now := setupQueryTasks(t)
+
+	tests := map[string][]string{
+		"": {"T-1", "T-2", "T-3", "T-4", "T-5"},
+		"status=in-progress type=review track=auth scope=internal/api": {"T-3"},
+		"status=backlog,done":            {"T-1", "T-2", "T-5"},
+		"status=pending":                 {"T-1", "T-2"},
+		"type!=review and track=auth":    {"T-1", "T-5"},
+		"-type=review -track=auth":       {"T-2"},
+		"scope=internal/api":             {"T-3", "T-5"},
+		"scope=internal/**/handlers":     {"T-3"},
+		"assignee=":                      {"T-1", "T-2", "T-5"},
+		"assignee=ALICE":                 {"T-3"},
+		"id=T-[12]":                      {"T-1", "T-2"},
+		"spec=limits.md":                 {"T-2"},
+		"pr=true":                        {"T-5"},
+		"claimed<2d":                     {"T-3"},
+		"claimed>=2026-03-10":            {"T-4"},
+		"completed>2026-03-01T00:00:00Z": {"T-5"},
+		`"rate limit"`:                   {"T-2", "T-5"},
+		"review -db":                     {"T-3"},
+		"text=login":                     {"T-1"},
+	}
+	for expr, want := range tests {
+		assert.Equal(t, want, queryIDs(t, tm, expr, now), expr)
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	now := time.Now()
+	for expr, msg := range map[string]string{
+		"staus=done":      `unknown query field "staus"`,
+		"status=open":     `status=open: unknown status "open" (expected backlog, in-progress or done)`,
+		"claimed=2d":      "claimed=2d: field claimed takes < > <= >=",
+		"type>review":     "type>review: field type takes = !=",
+		"claimed<soon":    "claimed<soon: expected a date",
+		"pr=maybe":        "pr=maybe: expected true or false",
+		`title="unclosed`: "unterminated quote",
+	} {
+		_, err := ParseQuery(expr, now)
+		require.Error(t, err, expr)
+		assert.Contains(t, err.Error(), msg, expr)
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	tm, _ := setupQueryTasks(t)
+	listed, err := tm.ListTasks(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, SortTasks(listed, "track,-claimed"))
+	var ids []string
+	for _, lt := range listed {
+		ids = append(ids, lt.ID)
+	}
+	assert.Equal(t, []string{"T-2", "T-3", "T-1", "T-5", "T-4"}, ids, "ties keep list order")
+
+	require.NoError(t, SortTasks(listed, "-status,id"))
+	assert.Equal(t, "T-5", listed[0].ID)
+	assert.Equal(t, "done", listed[0].List)
+
+	assert.EqualError(t, SortTasks(listed, "priority"),
+		`unknown sort key "priority" (expected one of assignee, change, claimed, completed, id, status, title, track, type)`)
+}
@@ -24,13 +24,39 @@ type ReadinessResult struct {
 }
 
 // CanClaimTask performs readiness checks on a task.
-// Checks: inputs exist, spec refs resolvable, scope dirs exist (warning only).
+// Checks: dependencies done, inputs exist, spec refs resolvable, scope dirs exist (warning only).
+// Without a completed-task set every declared dependency counts as unmet;
+// use CanClaimTaskWithDeps when the done list is available.
 func CanClaimTask(task *models.Task, cfg *models.Config) *ReadinessResult {
+	return CanClaimTaskWithDeps(task, cfg, nil)
+}
+
+// CanClaimTaskWithDeps performs readiness checks, treating the IDs in
+// completed as finished prerequisites.
+func CanClaimTaskWithDeps(task *models.Task, cfg *models.Config, completed map[string]bool) *ReadinessResult {
 	result := &ReadinessResult{
 		TaskID: task.ID,
 		Ready:  true,
 	}
 
+	// Check 0: All DependsOn tasks are done (blocking)
+	for _, dep := range task.DependsOn {
+		if completed[dep] {
+			result.Checks = append(result.Checks, ReadinessCheck{
+				Name:    "dependency-done",
+				Passed:  true,
+				Message: fmt.Sprintf("dependency %s is done", dep),
+			})
+		} else {
+			result.Checks = append(result.Checks, ReadinessCheck{
+				Name:    "dependency-done",
+				Passed:  false,
+				Message: fmt.Sprintf("dependency %s is not done", dep),
+			})
+			result.Ready = false
+		}
+	}
+
 	// Check 1: All Inputs files exist on disk
 	for _, input := range task.Inputs {
 		if _, err := os.Stat(input); os.IsNotExist(err) {
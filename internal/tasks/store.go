@@ -0,0 +1,184 @@
+package tasks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Task store backends.
+const (
+	StoreYAML = "yaml" // one YAML file per list (default)
+	StoreBolt = "bolt" // single embedded bbolt database
+)
+
+// taskLists are the lists a store holds, in lifecycle order.
+var taskLists = []string{"backlog", "in-progress", "done"}
+
+// TaskStore persists the task lists behind a TaskManager.
+type TaskStore interface {
+	// Load returns a list; one never saved is empty.
+	Load(listType string) (*TaskList, error)
+	// Lock takes the exclusive writer lock, waiting up to LockTimeout.
+	Lock() (unlock func(), err error)
+	// Commit replaces the given lists, all or none. The caller holds the lock.
+	Commit(lists map[string]*TaskList) error
+	// Find returns the first task or subtask with the given ID and the list
+	// holding it, or a nil task when there is none.
+	Find(taskID string) (*models.Task, string, error)
+	// ByTrack returns the tasks of a track, compared without case, in list order.
+	ByTrack(trackID string) ([]ListedTask, error)
+}
+
+// DetectStore reports the backend the tasks in dir use: bolt once a
+// database file exists, YAML otherwise.
+func DetectStore(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, BoltFileName)); err == nil {
+		return StoreBolt
+	}
+	return StoreYAML
+}
+
+// OpenStore returns the named backend for the tasks in dir.
+func OpenStore(dir, kind string) (TaskStore, error) {
+	switch kind {
+	case StoreYAML:
+		return NewYAMLStore(dir), nil
+	case StoreBolt:
+		return NewBoltStore(dir), nil
+	}
+	return nil, fmt.Errorf("unknown task store %q (expected %s or %s)", kind, StoreYAML, StoreBolt)
+}
+
+// findInList returns the task or subtask with the given ID. A subtask is
+// returned as a task carrying its own fields.
+func findInList(tasks []models.Task, taskID string) *models.Task {
+	for i := range tasks {
+		task := tasks[i]
+		if task.ID == taskID {
+			return &task
+		}
+		for _, subtask := range task.SubTasks {
+			if subtask.ID == taskID {
+				return &models.Task{
+					ID:         subtask.ID,
+					Title:      subtask.Title,
+					Status:     subtask.Status,
+					AssignedTo: subtask.AssignedTo,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// MigrateResult describes a completed store migration.
+type MigrateResult struct {
+	From   string
+	To     string
+	Counts map[string]int // tasks per list
+	Backup string         // where the old backend's files were moved
+}
+
+// MigrateStore copies every list from the backend dir currently uses to
+// the one named by to, checks the copy reads back identically, and only
+// then moves the old files aside under a timestamped backup name. The old
+// store stays locked throughout, so no write can be lost in between.
+func MigrateStore(dir, to string) (*MigrateResult, error) {
+	from := DetectStore(dir)
+	if _, err := OpenStore(dir, to); err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("tasks in %s already use the %s store", dir, to)
+	}
+
+	src, _ := OpenStore(dir, from)
+	unlock, err := src.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	res := &MigrateResult{From: from, To: to, Counts: map[string]int{}}
+	lists := map[string]*TaskList{}
+	for _, name := range taskLists {
+		list, err := src.Load(name)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", name, err)
+		}
+		lists[name] = list
+		res.Counts[name] = len(list.Tasks)
+	}
+
+	stamp := time.Now().Format("20060102-150405")
+	switch to {
+	case StoreBolt:
+		// Build the database under a temporary name so a failed run leaves
+		// the YAML store in charge.
+		tmp := filepath.Join(dir, BoltFileName+".migrating")
+		os.Remove(tmp)
+		if err := copyLists(newBoltStoreAt(tmp), lists); err != nil {
+			os.Remove(tmp)
+			return nil, err
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, BoltFileName)); err != nil {
+			return nil, fmt.Errorf("failed to install task database: %w", err)
+		}
+		res.Backup = filepath.Join(dir, "yaml-backup-"+stamp)
+		if err := os.MkdirAll(res.Backup, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		for _, name := range taskLists {
+			path := filepath.Join(dir, name+".yaml")
+			if err := os.Rename(path, filepath.Join(res.Backup, name+".yaml")); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to back up %s: %w", path, err)
+			}
+		}
+	case StoreYAML:
+		if err := copyLists(NewYAMLStore(dir), lists); err != nil {
+			return nil, err
+		}
+		res.Backup = filepath.Join(dir, BoltFileName+".bak-"+stamp)
+		if err := os.Rename(filepath.Join(dir, BoltFileName), res.Backup); err != nil {
+			return nil, fmt.Errorf("failed to back up task database: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// copyLists commits lists to dst and verifies they read back unchanged.
+func copyLists(dst TaskStore, lists map[string]*TaskList) error {
+	unlock, err := dst.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := dst.Commit(lists); err != nil {
+		return err
+	}
+	for _, name := range taskLists {
+		got, err := dst.Load(name)
+		if err != nil {
+			return fmt.Errorf("verifying %s: %w", name, err)
+		}
+		want, _ := yaml.Marshal(lists[name])
+		have, _ := yaml.Marshal(got)
+		if !bytes.Equal(want, have) {
+			return fmt.Errorf("verifying %s: copied list differs from the original", name)
+		}
+	}
+	return nil
+}
+
+// sameTrack compares track IDs the way queries do.
+func sameTrack(a, b string) bool {
+	return a != "" && strings.EqualFold(a, b)
+}
@@ -0,0 +1,332 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	bolt "go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+)
+
+// BoltFileName is the database file of the embedded task store.
+const BoltFileName = "tasks.db"
+
+// Index buckets. Keys are <id or lowercased track>\x00<list rank><position>,
+// so a prefix seek finds every entry in list order. There is no status
+// index: a task's status is the list it is in (status queries match on the
+// list, see Query), so the list buckets already hold the tasks of each
+// status and a status lookup reads only its bucket.
+var (
+	boltIDIndex    = []byte("index/id")
+	boltTrackIndex = []byte("index/track")
+)
+
+// boltStore keeps all lists in one bbolt database. Each list is a bucket
+// mapping the 8-byte position of a task to its JSON encoding, which keeps
+// order and every field. Commits rewrite only the positions whose task
+// changed, together with their index entries.
+type boltStore struct {
+	path string
+
+	mu sync.RWMutex
+	db *bolt.DB // open for writing while the lock is held
+}
+
+// NewBoltStore returns the embedded store kept in <dir>/tasks.db.
+func NewBoltStore(dir string) TaskStore {
+	return newBoltStoreAt(filepath.Join(dir, BoltFileName))
+}
+
+func newBoltStoreAt(path string) *boltStore {
+	return &boltStore{path: path}
+}
+
+// Lock opens the database for writing; bbolt's file lock keeps every other
+// writer and reader out until unlock closes it.
+func (s *boltStore) Lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tasks directory: %w", err)
+	}
+	db, err := s.open(false)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.db = db
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		if s.db == db {
+			s.db = nil
+		}
+		s.mu.Unlock()
+		db.Close()
+	}, nil
+}
+
+func (s *boltStore) open(readOnly bool) (*bolt.DB, error) {
+	db, err := bolt.Open(s.path, 0644, &bolt.Options{Timeout: LockTimeout, ReadOnly: readOnly})
+	if errors.Is(err, bolterrors.ErrTimeout) {
+		return nil, fmt.Errorf("timed out after %s waiting for task store %s", LockTimeout, s.path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task store %s: %w", s.path, err)
+	}
+	return db, nil
+}
+
+// view runs fn in a read transaction: on the writer's handle while this
+// store holds the lock, on a read-only handle otherwise. A database that
+// does not exist yet reads as empty and fn is not called.
+func (s *boltStore) view(fn func(tx *bolt.Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db != nil {
+		return s.db.View(fn)
+	}
+
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil
+	}
+	db, err := s.open(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(fn)
+}
+
+func (s *boltStore) Load(listType string) (*TaskList, error) {
+	list := &TaskList{Tasks: []models.Task{}}
+	err := s.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(listType))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var t models.Task
+			if err := json.Unmarshal(v, &t); err != nil {
+				return fmt.Errorf("corrupt task in %s: %w", listType, err)
+			}
+			list.Tasks = append(list.Tasks, t)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *boltStore) Commit(lists map[string]*TaskList) error {
+	s.mu.RLock()
+	db := s.db
+	s.mu.RUnlock()
+	if db == nil {
+		return fmt.Errorf("task store %s is not locked", s.path)
+	}
+
+	return db.Update(func(tx *bolt.Tx) error {
+		ids, err := tx.CreateBucketIfNotExists(boltIDIndex)
+		if err != nil {
+			return err
+		}
+		tracks, err := tx.CreateBucketIfNotExists(boltTrackIndex)
+		if err != nil {
+			return err
+		}
+		for name, list := range lists {
+			if err := commitList(tx, ids, tracks, name, list); err != nil {
+				return fmt.Errorf("writing %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// commitList makes the list bucket match list position by position.
+func commitList(tx *bolt.Tx, ids, tracks *bolt.Bucket, name string, list *TaskList) error {
+	b, err := tx.CreateBucketIfNotExists([]byte(name))
+	if err != nil {
+		return err
+	}
+	var old [][]byte
+	b.ForEach(func(_, v []byte) error {
+		old = append(old, append([]byte(nil), v...))
+		return nil
+	})
+
+	for i := range list.Tasks {
+		t := &list.Tasks[i]
+		enc, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		if i < len(old) {
+			if bytes.Equal(old[i], enc) {
+				continue
+			}
+			if err := unindexTask(ids, tracks, name, i, old[i]); err != nil {
+				return err
+			}
+		}
+		if err := b.Put(boltPos(i), enc); err != nil {
+			return err
+		}
+		if err := indexTask(ids, tracks, name, i, t.ID, t.TrackID, t.SubTasks); err != nil {
+			return err
+		}
+	}
+
+	for i := len(list.Tasks); i < len(old); i++ {
+		if err := unindexTask(ids, tracks, name, i, old[i]); err != nil {
+			return err
+		}
+		if err := b.Delete(boltPos(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *boltStore) Find(taskID string) (*models.Task, string, error) {
+	var task *models.Task
+	var source string
+	err := s.view(func(tx *bolt.Tx) error {
+		ids := tx.Bucket(boltIDIndex)
+		if ids == nil {
+			return nil
+		}
+		prefix := boltIndexPrefix(taskID)
+		k, _ := ids.Cursor().Seek(prefix)
+		if k == nil || !bytes.HasPrefix(k, prefix) {
+			return nil
+		}
+		lt, err := loadIndexed(tx, k[len(prefix):])
+		if err != nil {
+			return err
+		}
+		task, source = findInList([]models.Task{lt.Task}, taskID), lt.List
+		return nil
+	})
+	if err != nil || task == nil {
+		return nil, "", err
+	}
+	return task, source, nil
+}
+
+func (s *boltStore) ByTrack(trackID string) ([]ListedTask, error) {
+	if trackID == "" {
+		return nil, nil
+	}
+	var listed []ListedTask
+	err := s.view(func(tx *bolt.Tx) error {
+		tracks := tx.Bucket(boltTrackIndex)
+		if tracks == nil {
+			return nil
+		}
+		prefix := boltIndexPrefix(strings.ToLower(trackID))
+		c := tracks.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			lt, err := loadIndexed(tx, k[len(prefix):])
+			if err != nil {
+				return err
+			}
+			listed = append(listed, *lt)
+		}
+		return nil
+	})
+	return listed, err
+}
+
+// indexTask records a task and its subtasks under their IDs, and the task
+// under its track. Lists outside the lifecycle are not indexed.
+func indexTask(ids, tracks *bolt.Bucket, list string, pos int, id, trackID string, subtasks []models.SubTask) error {
+	ref, ok := boltRef(list, pos)
+	if !ok {
+		return nil
+	}
+	if err := ids.Put(append(boltIndexPrefix(id), ref...), nil); err != nil {
+		return err
+	}
+	for _, st := range subtasks {
+		if err := ids.Put(append(boltIndexPrefix(st.ID), ref...), nil); err != nil {
+			return err
+		}
+	}
+	if trackID != "" {
+		return tracks.Put(append(boltIndexPrefix(strings.ToLower(trackID)), ref...), nil)
+	}
+	return nil
+}
+
+// unindexTask removes the index entries of the task encoded in old.
+func unindexTask(ids, tracks *bolt.Bucket, list string, pos int, old []byte) error {
+	ref, ok := boltRef(list, pos)
+	if !ok {
+		return nil
+	}
+	var t models.Task
+	if err := json.Unmarshal(old, &t); err != nil {
+		return fmt.Errorf("corrupt task in %s: %w", list, err)
+	}
+	if err := ids.Delete(append(boltIndexPrefix(t.ID), ref...)); err != nil {
+		return err
+	}
+	for _, st := range t.SubTasks {
+		if err := ids.Delete(append(boltIndexPrefix(st.ID), ref...)); err != nil {
+			return err
+		}
+	}
+	if t.TrackID != "" {
+		return tracks.Delete(append(boltIndexPrefix(strings.ToLower(t.TrackID)), ref...))
+	}
+	return nil
+}
+
+// loadIndexed reads the task an index entry points at.
+func loadIndexed(tx *bolt.Tx, ref []byte) (*ListedTask, error) {
+	if len(ref) != 9 || int(ref[0]) >= len(taskLists) {
+		return nil, fmt.Errorf("corrupt task index entry %x", ref)
+	}
+	list := taskLists[ref[0]]
+	var v []byte
+	if b := tx.Bucket([]byte(list)); b != nil {
+		v = b.Get(ref[1:])
+	}
+	if v == nil {
+		return nil, fmt.Errorf("task index points at missing %s entry %d", list, binary.BigEndian.Uint64(ref[1:]))
+	}
+	lt := &ListedTask{List: list}
+	if err := json.Unmarshal(v, &lt.Task); err != nil {
+		return nil, fmt.Errorf("corrupt task in %s: %w", list, err)
+	}
+	return lt, nil
+}
+
+func boltPos(pos int) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(pos))
+}
+
+// boltRef encodes a list and position as an index entry suffix.
+func boltRef(list string, pos int) ([]byte, bool) {
+	rank, ok := listRank[list]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte{byte(rank)}, boltPos(pos)...), true
+}
+
+func boltIndexPrefix(key string) []byte {
+	return append([]byte(key), 0)
+}
@@ -0,0 +1,182 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBoltStore_IndexesFollowChanges(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManagerWithStore(tmpDir, NewBoltStore(tmpDir))
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{
+		{ID: "T-1", Title: "Login", TrackID: "auth", SubTasks: []models.SubTask{{ID: "T-1.1", Title: "Form"}}},
+		{ID: "T-2", Title: "Limits", TrackID: "api"},
+		{ID: "T-3", Title: "Logout", TrackID: "Auth"},
+	}}))
+	assert.Equal(t, StoreBolt, DetectStore(tmpDir))
+	assert.Equal(t, StoreBolt, NewTaskManager(tmpDir).StoreKind())
+
+	task, list, err := tm.FindTask("T-2")
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "Limits", task.Title)
+	assert.Equal(t, "backlog", list)
+
+	sub, _, err := tm.FindTask("T-1.1")
+	require.NoError(t, err)
+	require.NotNil(t, sub)
+	assert.Equal(t, "Form", sub.Title)
+
+	require.NoError(t, tm.MoveTask("T-1", "backlog", "done", models.StatusDone))
+
+	task, list, err = tm.FindTask("T-1")
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "done", list)
+	assert.Equal(t, models.StatusDone, task.Status)
+
+	// T-3 shifted into T-2's old position; the indexes must point at it
+	task, _, err = tm.FindTask("T-3")
+	require.NoError(t, err)
+	assert.Equal(t, "Logout", task.Title)
+
+	byTrack, err := tm.TasksByTrack("AUTH")
+	require.NoError(t, err)
+	require.Len(t, byTrack, 2)
+	assert.Equal(t, "T-3", byTrack[0].ID)
+	assert.Equal(t, "backlog", byTrack[0].List)
+	assert.Equal(t, "T-1", byTrack[1].ID)
+	assert.Equal(t, "done", byTrack[1].List)
+
+	missing, _, err := tm.FindTask("T-9")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	backlog, err := tm.LoadTasks("backlog")
+	require.NoError(t, err)
+	require.Len(t, backlog.Tasks, 2)
+	assert.Equal(t, []string{"T-2", "T-3"}, []string{backlog.Tasks[0].ID, backlog.Tasks[1].ID})
+}
+
+func TestBoltStore_ConcurrentCreateAndLockTimeout(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	require.NoError(t, NewTaskManagerWithStore(tmpDir, NewBoltStore(tmpDir)).SaveTasks("backlog", &TaskList{}))
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := NewTaskManager(tmpDir).CreateTask("concurrent")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	backlog, err := NewTaskManager(tmpDir).LoadTasks("backlog")
+	require.NoError(t, err)
+	assert.Len(t, backlog.Tasks, n)
+
+	unlock, err := NewTaskManager(tmpDir).lock()
+	require.NoError(t, err)
+	defer unlock()
+
+	old := LockTimeout
+	LockTimeout = 50 * time.Millisecond
+	defer func() { LockTimeout = old }()
+
+	err = NewTaskManager(tmpDir).SaveTasks("backlog", &TaskList{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestMigrateStore_RoundTrip(t *testing.T) {
+	tm, now := setupQueryTasks(t)
+	dir := tm.baseDir
+	before, err := tm.ListTasks(nil)
+	require.NoError(t, err)
+
+	res, err := MigrateStore(dir, StoreBolt)
+	require.NoError(t, err)
+	assert.Equal(t, StoreYAML, res.From)
+	assert.Equal(t, map[string]int{"backlog": 2, "in-progress": 2, "done": 1}, res.Counts)
+	assert.FileExists(t, filepath.Join(res.Backup, "done.yaml"))
+	assert.NoFileExists(t, filepath.Join(dir, "done.yaml"))
+
+	db := NewTaskManager(dir)
+	require.Equal(t, StoreBolt, db.StoreKind())
+	after, err := db.ListTasks(nil)
+	require.NoError(t, err)
+	assert.Equal(t, yamlOf(t, before), yamlOf(t, after))
+	assert.Equal(t, []string{"T-3", "T-5"}, queryIDs(t, db, "track=auth scope=internal/api", now))
+	assert.Equal(t, []string{"T-4"}, queryIDs(t, db, "status=in-progress -track=auth", now))
+
+	_, err = MigrateStore(dir, StoreBolt)
+	assert.EqualError(t, err, "tasks in "+dir+" already use the bolt store")
+
+	res, err = MigrateStore(dir, StoreYAML)
+	require.NoError(t, err)
+	assert.Equal(t, StoreYAML, DetectStore(dir))
+	assert.FileExists(t, res.Backup)
+	back, err := NewTaskManager(dir).ListTasks(nil)
+	require.NoError(t, err)
+	assert.Equal(t, yamlOf(t, before), yamlOf(t, back))
+
+	_, err = os.Stat(filepath.Join(dir, BoltFileName+".migrating"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func yamlOf(t *testing.T, v any) string {
+	t.Helper()
+	data, err := yaml.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestMigrateStore_WaitingWriterFollowsNewBackend(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{{ID: "T-1", Title: "Login"}}}))
+
+	// Hold the YAML lock the way migrate-store does while tm waits on it
+	unlock, err := NewYAMLStore(tmpDir).Lock()
+	require.NoError(t, err)
+	created := make(chan error, 1)
+	go func() {
+		_, err := tm.CreateTask("Logout")
+		created <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	lists := map[string]*TaskList{}
+	for _, name := range taskLists {
+		lists[name], err = NewYAMLStore(tmpDir).Load(name)
+		require.NoError(t, err)
+	}
+	require.NoError(t, copyLists(NewBoltStore(tmpDir), lists))
+	require.NoError(t, os.Rename(filepath.Join(tmpDir, "backlog.yaml"), filepath.Join(tmpDir, "backlog.yaml.bak")))
+	unlock()
+	require.NoError(t, <-created)
+
+	assert.Equal(t, StoreBolt, tm.StoreKind())
+	assert.NoFileExists(t, filepath.Join(tmpDir, "backlog.yaml"))
+	backlog, err := NewTaskManager(tmpDir).LoadTasks("backlog")
+	require.NoError(t, err)
+	require.Len(t, backlog.Tasks, 2)
+	assert.Equal(t, "Logout", backlog.Tasks[1].Title)
+
+	// A manager pinned to YAML refuses instead of writing beside tasks.db
+	pinned := NewTaskManagerWithStore(tmpDir, NewYAMLStore(tmpDir))
+	err = pinned.SaveTasks("backlog", &TaskList{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "now use the bolt store")
+}
@@ -0,0 +1,240 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// LockFileName is the advisory lock file guarding the task directory.
+	LockFileName = ".lock"
+	// JournalFileName holds the pending writes of a multi-list transaction.
+	JournalFileName = ".journal.yaml"
+)
+
+// LockTimeout bounds how long a task operation waits for another process
+// to release the task directory lock.
+var LockTimeout = 30 * time.Second
+
+const lockPollInterval = 10 * time.Millisecond
+
+// journal is the on-disk record of a transaction touching several lists.
+// It is written before any list file and removed once all lists are in place,
+// so a crash in between is repaired by replaying it.
+type journal struct {
+	StartedAt time.Time           `yaml:"started_at"`
+	Lists     map[string]TaskList `yaml:"lists"`
+}
+
+// yamlStore keeps each list in <dir>/<list>.yaml, rewriting the whole file
+// on every change.
+type yamlStore struct {
+	dir string
+}
+
+// NewYAMLStore returns the default store, one YAML file per list in dir.
+func NewYAMLStore(dir string) TaskStore {
+	return &yamlStore{dir: dir}
+}
+
+func (s *yamlStore) Load(listType string) (*TaskList, error) {
+	// Finish any interrupted transaction before reading a possibly stale list
+	if s.hasPendingJournal() {
+		unlock, err := s.Lock()
+		if err != nil {
+			return nil, err
+		}
+		unlock()
+	}
+	return s.readList(listType)
+}
+
+// Lock takes the exclusive advisory lock on the task directory and replays
+// any journal left behind by an interrupted transaction.
+func (s *yamlStore) Lock() (func(), error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tasks directory: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir, LockFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task lock: %w", err)
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		ok, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock tasks: %w", err)
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out after %s waiting for task lock %s", LockTimeout, f.Name())
+		}
+		time.Sleep(lockPollInterval)
+	}
+
+	unlock := func() {
+		unlockFile(f)
+		f.Close()
+	}
+
+	if err := s.replayJournal(); err != nil {
+		unlock()
+		return nil, fmt.Errorf("failed to recover task journal: %w", err)
+	}
+	return unlock, nil
+}
+
+// Commit writes the given lists. A single list is replaced atomically on
+// its own; several lists go through the journal first.
+func (s *yamlStore) Commit(lists map[string]*TaskList) error {
+	var names []string
+	for name := range lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	switch len(names) {
+	case 0:
+		return nil
+	case 1:
+		return s.writeList(names[0], lists[names[0]])
+	}
+
+	j := journal{StartedAt: time.Now(), Lists: map[string]TaskList{}}
+	for _, name := range names {
+		j.Lists[name] = *lists[name]
+	}
+	data, err := yaml.Marshal(&j)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+	if err := writeFileAtomic(s.journalPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return s.applyJournal(&j)
+}
+
+// Find loads the lists in order until one holds the task.
+func (s *yamlStore) Find(taskID string) (*models.Task, string, error) {
+	for _, source := range taskLists {
+		list, err := s.Load(source)
+		if err != nil {
+			return nil, "", fmt.Errorf("error loading %s: %w", source, err)
+		}
+		if task := findInList(list.Tasks, taskID); task != nil {
+			return task, source, nil
+		}
+	}
+	return nil, "", nil
+}
+
+func (s *yamlStore) ByTrack(trackID string) ([]ListedTask, error) {
+	var listed []ListedTask
+	for _, source := range taskLists {
+		list, err := s.Load(source)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s: %w", source, err)
+		}
+		for _, t := range list.Tasks {
+			if sameTrack(t.TrackID, trackID) {
+				listed = append(listed, ListedTask{List: source, Task: t})
+			}
+		}
+	}
+	return listed, nil
+}
+
+// readList reads a list file without locking.
+func (s *yamlStore) readList(listType string) (*TaskList, error) {
+	path := filepath.Join(s.dir, listType+".yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &TaskList{Tasks: []models.Task{}}, nil
+		}
+		return nil, err
+	}
+
+	var list TaskList
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// writeList replaces a list file via temp-file-and-rename. Callers hold the lock.
+func (s *yamlStore) writeList(listType string, list *TaskList) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create tasks directory: %w", err)
+	}
+	path := filepath.Join(s.dir, listType+".yaml")
+	data, err := yaml.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+// replayJournal finishes a transaction that was interrupted after its journal
+// was written. Must be called with the lock held.
+func (s *yamlStore) replayJournal() error {
+	data, err := os.ReadFile(s.journalPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var j journal
+	if err := yaml.Unmarshal(data, &j); err != nil {
+		return fmt.Errorf("corrupt journal %s: %w", s.journalPath(), err)
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Replaying interrupted task transaction from %s\n", j.StartedAt.Format(time.RFC3339))
+	return s.applyJournal(&j)
+}
+
+// applyJournal writes every list recorded in j, then discards the journal.
+func (s *yamlStore) applyJournal(j *journal) error {
+	var names []string
+	for name := range j.Lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		list := j.Lists[name]
+		if err := s.writeList(name, &list); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(s.journalPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove journal: %w", err)
+	}
+	syncDir(s.dir)
+	return nil
+}
+
+func (s *yamlStore) journalPath() string {
+	return filepath.Join(s.dir, JournalFileName)
+}
+
+// hasPendingJournal reports whether an interrupted transaction awaits replay.
+func (s *yamlStore) hasPendingJournal() bool {
+	_, err := os.Stat(s.journalPath())
+	return err == nil
+}
@@ -43,9 +43,12 @@ func DecomposeForTDD(tm *TaskManager, parentTaskID string) ([]models.SubTask, er
 	}
 
 	// Update the parent task with sub-tasks
-	tm.updateTaskInList(parentTaskID, source, func(t *models.Task) {
+	err = tm.updateTaskInList(parentTaskID, source, func(t *models.Task) {
 		t.SubTasks = subtasks
 	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save TDD subtasks: %w", err)
+	}
 
 	return subtasks, nil
 }
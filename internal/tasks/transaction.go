@@ -0,0 +1,84 @@
+package tasks
+
+import "fmt"
+
+// Tx is a set of task list changes applied atomically under the task lock.
+// Lists are loaded once per transaction and written back only when saved.
+type Tx struct {
+	tm     *TaskManager
+	lists  map[string]*TaskList
+	dirty  map[string]bool
+	undoes []int64 // events this transaction reverts
+}
+
+// Load returns the named list, reading it from disk on first access.
+func (tx *Tx) Load(listType string) (*TaskList, error) {
+	if list, ok := tx.lists[listType]; ok {
+		return list, nil
+	}
+	list, err := tx.tm.store.Load(listType)
+	if err != nil {
+		return nil, err
+	}
+	tx.lists[listType] = list
+	return list, nil
+}
+
+// Save stages the named list to be written when the transaction commits.
+func (tx *Tx) Save(listType string, list *TaskList) {
+	tx.lists[listType] = list
+	tx.dirty[listType] = true
+}
+
+// Update runs fn inside a transaction holding the task directory lock.
+// All lists saved by fn are committed together, or not at all if fn fails.
+func (tm *TaskManager) Update(fn func(tx *Tx) error) error {
+	unlock, err := tm.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tx := &Tx{tm: tm, lists: map[string]*TaskList{}, dirty: map[string]bool{}}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	lists := make(map[string]*TaskList, len(tx.dirty))
+	for name := range tx.dirty {
+		lists[name] = tx.lists[name]
+	}
+	return tm.commit(lists, tx.undoes)
+}
+
+// lock takes the store's exclusive writer lock. The backend is detected
+// again under the lock: if 'task migrate-store' switched it while this
+// manager waited, a detected store moves to the new backend and an
+// explicit one refuses, rather than writing files the new backend ignores.
+func (tm *TaskManager) lock() (func(), error) {
+	if tm.detected {
+		tm.follow(DetectStore(tm.baseDir))
+	}
+	unlock, err := tm.store.Lock()
+	if err != nil {
+		return nil, err
+	}
+
+	kind, current := DetectStore(tm.baseDir), tm.StoreKind()
+	if kind == current || (current != StoreYAML && current != StoreBolt) {
+		return unlock, nil
+	}
+	unlock()
+	if !tm.detected {
+		return nil, fmt.Errorf("tasks in %s now use the %s store", tm.baseDir, kind)
+	}
+	tm.follow(kind)
+	return tm.store.Lock()
+}
+
+// follow switches a detected manager to the given backend.
+func (tm *TaskManager) follow(kind string) {
+	if kind != tm.StoreKind() {
+		tm.store, _ = OpenStore(tm.baseDir, kind)
+	}
+}
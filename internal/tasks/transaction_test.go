@@ -0,0 +1,165 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteFileAtomic_NoTempFilesLeft(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	path := filepath.Join(tmpDir, "backlog.yaml")
+
+	require.NoError(t, writeFileAtomic(path, []byte("tasks: []\n"), 0644))
+	require.NoError(t, writeFileAtomic(path, []byte("tasks: [{id: A}]\n"), 0644))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "id: A")
+
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.False(t, strings.Contains(e.Name(), ".tmp-"), "leftover temp file %s", e.Name())
+	}
+}
+
+func TestUpdate_CommitsAllLists(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{{ID: "T-1", Status: models.StatusPending}}}))
+
+	err := tm.Update(func(tx *Tx) error {
+		_, err := moveTaskInTx(tx, "T-1", "backlog", "done", models.StatusDone, nil)
+		return err
+	})
+	require.NoError(t, err)
+
+	backlog, _ := tm.LoadTasks("backlog")
+	done, _ := tm.LoadTasks("done")
+	assert.Empty(t, backlog.Tasks)
+	require.Len(t, done.Tasks, 1)
+	assert.Equal(t, models.StatusDone, done.Tasks[0].Status)
+
+	_, err = os.Stat(filepath.Join(tmpDir, JournalFileName))
+	assert.True(t, os.IsNotExist(err), "journal should be removed after commit")
+}
+
+func TestUpdate_ErrorDiscardsChanges(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{{ID: "T-1"}}}))
+
+	err := tm.Update(func(tx *Tx) error {
+		list, _ := tx.Load("backlog")
+		list.Tasks = nil
+		tx.Save("backlog", list)
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+
+	backlog, _ := tm.LoadTasks("backlog")
+	assert.Len(t, backlog.Tasks, 1)
+}
+
+func TestLoadTasks_ReplaysInterruptedJournal(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+
+	// Simulate a crash after the journal was written but before in-progress
+	// was updated: the task is gone from backlog and missing from in-progress.
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{}}))
+	j := journal{
+		StartedAt: time.Now(),
+		Lists: map[string]TaskList{
+			"backlog":     {Tasks: []models.Task{}},
+			"in-progress": {Tasks: []models.Task{{ID: "T-1", Status: models.StatusInProgress}}},
+		},
+	}
+	data, err := yaml.Marshal(&j)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, JournalFileName), data, 0644))
+
+	inProgress, err := tm.LoadTasks("in-progress")
+	require.NoError(t, err)
+	require.Len(t, inProgress.Tasks, 1)
+	assert.Equal(t, "T-1", inProgress.Tasks[0].ID)
+
+	_, err = os.Stat(filepath.Join(tmpDir, JournalFileName))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLock_TimesOutWhenHeld(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+
+	unlock, err := tm.lock()
+	require.NoError(t, err)
+	defer unlock()
+
+	old := LockTimeout
+	LockTimeout = 50 * time.Millisecond
+	defer func() { LockTimeout = old }()
+
+	err = NewTaskManager(tmpDir).SaveTasks("backlog", &TaskList{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestCreateTask_ConcurrentNoLostTasks(t *testing.T) {
+	tmpDir := setupTestDir(t)
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := NewTaskManager(tmpDir).CreateTask("concurrent")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	backlog, err := NewTaskManager(tmpDir).LoadTasks("backlog")
+	require.NoError(t, err)
+	assert.Len(t, backlog.Tasks, n)
+}
+
+func TestClaimTask_ConcurrentSingleWinner(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	tm := NewTaskManager(tmpDir)
+	require.NoError(t, tm.SaveTasks("backlog", &TaskList{Tasks: []models.Task{{ID: "T-1", Status: models.StatusPending}}}))
+
+	const n = 8
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	wins := 0
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := NewTaskManager(tmpDir).ClaimTask("T-1", "agent"); err == nil {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, wins)
+	backlog, _ := tm.LoadTasks("backlog")
+	inProgress, _ := tm.LoadTasks("in-progress")
+	assert.Empty(t, backlog.Tasks)
+	assert.Len(t, inProgress.Tasks, 1)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/javierbenavides/agentic-agent/internal/tasks"
 	"github.com/javierbenavides/agentic-agent/internal/ui/components"
 	"github.com/javierbenavides/agentic-agent/internal/ui/styles"
+	taskmodels "github.com/javierbenavides/agentic-agent/pkg/models"
 )
 
 // TaskCreateStep represents the current step in task creation
@@ -51,6 +52,7 @@ type TaskCreateModel struct {
 	selectedSpecRefs []string
 	selectedScope    []string
 	selectedOutputs  []string
+	editID           string // set when editing an existing task
 }
 
 // NewTaskCreateModel creates a new task creation model
@@ -104,6 +106,27 @@ func NewTaskCreateModel() TaskCreateModel {
 	}
 }
 
+// NewTaskEditModel returns the same wizard filled in from an existing
+// task. Answering no to a section keeps what the task already has; saving
+// updates the task in place instead of creating one.
+func NewTaskEditModel(task *taskmodels.Task) TaskCreateModel {
+	m := NewTaskCreateModel()
+	m.editID = task.ID
+	m.title.SetValue(task.Title)
+	m.description.SetValue(task.Description)
+	m.selectedSpecRefs = append([]string{}, task.SpecRefs...)
+	m.selectedScope = append([]string{}, task.Scope...)
+	m.selectedOutputs = append([]string{}, task.Outputs...)
+	m.acceptance.Items = append([]string{}, task.Acceptance...)
+
+	m.addSpecRefs = components.NewConfirm(fmt.Sprintf("Change specification references? (%d now)", len(task.SpecRefs)), false)
+	m.addScope = components.NewConfirm(fmt.Sprintf("Change scope? (%d now)", len(task.Scope)), false)
+	m.addOutputs = components.NewConfirm(fmt.Sprintf("Change expected output files? (%d now)", len(task.Outputs)), false)
+	m.addAcceptance = components.NewConfirm(fmt.Sprintf("Edit acceptance criteria? (%d now)", len(task.Acceptance)), false)
+	m.spinner = components.NewSpinner("Saving task...")
+	return m
+}
+
 // Init initializes the model
 func (m TaskCreateModel) Init() tea.Cmd {
 	return m.title.Focus()
@@ -219,6 +242,7 @@ func (m TaskCreateModel) handleEnter() (tea.Model, tea.Cmd) {
 		if m.addSpecRefs.IsYes() {
 			// Initialize file picker for spec refs
 			m.specRefsPicker = components.NewFilePicker("Select Specification References", ".agentic/spec", false, true)
+			preselect(&m.specRefsPicker, m.selectedSpecRefs)
 			m.step = TaskStepSpecRefsPicker
 		} else {
 			m.step = TaskStepScopeConfirm
@@ -233,6 +257,7 @@ func (m TaskCreateModel) handleEnter() (tea.Model, tea.Cmd) {
 		if m.addScope.IsYes() {
 			// Initialize file picker for scope (directories/files)
 			m.scopePicker = components.NewFilePicker("Select Scope (files/directories)", ".", false, true)
+			preselect(&m.scopePicker, m.selectedScope)
 			m.step = TaskStepScopePicker
 		} else {
 			m.step = TaskStepOutputsConfirm
@@ -247,6 +272,7 @@ func (m TaskCreateModel) handleEnter() (tea.Model, tea.Cmd) {
 		if m.addOutputs.IsYes() {
 			// Initialize file picker for outputs
 			m.outputsPicker = components.NewFilePicker("Select Expected Output Files", ".", false, true)
+			preselect(&m.outputsPicker, m.selectedOutputs)
 			m.step = TaskStepOutputsPicker
 		} else {
 			m.step = TaskStepAcceptanceConfirm
@@ -271,9 +297,13 @@ func (m TaskCreateModel) handleEnter() (tea.Model, tea.Cmd) {
 
 	case TaskStepPreview:
 		m.step = TaskStepCreating
+		save := m.createTask()
+		if m.editID != "" {
+			save = m.saveTask()
+		}
 		return m, tea.Batch(
 			m.spinner.Init(),
-			m.createTask(),
+			save,
 		)
 
 	case TaskStepComplete:
@@ -336,6 +366,33 @@ func (m *TaskCreateModel) createTask() tea.Cmd {
 	}
 }
 
+// saveTask writes the wizard's fields back to the task being edited
+func (m *TaskCreateModel) saveTask() tea.Cmd {
+	return func() tea.Msg {
+		tm := tasks.NewTaskManager(".agentic/tasks")
+		_, _, err := tm.EditTask(m.editID, func(e *tasks.TaskEdit) error {
+			e.Title = m.title.Value()
+			e.Description = strings.TrimSpace(m.description.Value())
+			e.SpecRefs = m.selectedSpecRefs
+			e.Scope = m.selectedScope
+			e.Outputs = m.selectedOutputs
+			e.Acceptance = m.acceptance.GetItems()
+			return nil
+		})
+		if err != nil {
+			return taskCreateErrorMsg{err}
+		}
+		return taskCreateCompleteMsg{taskID: m.editID}
+	}
+}
+
+// preselect marks paths already on the task in a file picker
+func preselect(fp *components.FilePicker, paths []string) {
+	for _, p := range paths {
+		fp.Selected[p] = true
+	}
+}
+
 // taskCreateCompleteMsg signals task creation is complete
 type taskCreateCompleteMsg struct {
 	taskID string
@@ -387,7 +444,11 @@ func (m TaskCreateModel) View() string {
 func (m TaskCreateModel) renderTitle() string {
 	var b strings.Builder
 
-	b.WriteString(styles.TitleStyle.Render("Create New Task") + "\n\n")
+	if m.editID != "" {
+		b.WriteString(styles.TitleStyle.Render("Edit Task "+m.editID) + "\n\n")
+	} else {
+		b.WriteString(styles.TitleStyle.Render("Create New Task") + "\n\n")
+	}
 	b.WriteString(m.title.View() + "\n")
 	b.WriteString(styles.HelpStyle.Render("Enter to continue • Esc to cancel") + "\n")
 
@@ -528,7 +589,11 @@ func (m TaskCreateModel) renderPreview() string {
 	}
 
 	b.WriteString(styles.CardStyle.Render(summary) + "\n")
-	b.WriteString(styles.HelpStyle.Render("Press Enter to create task • Esc to cancel") + "\n")
+	if m.editID != "" {
+		b.WriteString(styles.HelpStyle.Render("Press Enter to save changes • Esc to cancel") + "\n")
+	} else {
+		b.WriteString(styles.HelpStyle.Render("Press Enter to create task • Esc to cancel") + "\n")
+	}
 
 	return styles.ContainerStyle.Render(b.String())
 }
@@ -536,7 +601,11 @@ func (m TaskCreateModel) renderPreview() string {
 func (m TaskCreateModel) renderCreating() string {
 	var b strings.Builder
 
-	b.WriteString(styles.TitleStyle.Render("Creating Task") + "\n\n")
+	if m.editID != "" {
+		b.WriteString(styles.TitleStyle.Render("Saving Task") + "\n\n")
+	} else {
+		b.WriteString(styles.TitleStyle.Render("Creating Task") + "\n\n")
+	}
 	b.WriteString(m.spinner.View() + "\n\n")
 	b.WriteString(styles.MutedStyle.Render("Please wait...") + "\n")
 
@@ -547,12 +616,24 @@ func (m TaskCreateModel) renderComplete() string {
 	var b strings.Builder
 
 	if m.error != "" {
-		b.WriteString(styles.RenderError("Failed to create task") + "\n\n")
+		if m.editID != "" {
+			b.WriteString(styles.RenderError("Failed to save task") + "\n\n")
+		} else {
+			b.WriteString(styles.RenderError("Failed to create task") + "\n\n")
+		}
 		b.WriteString(styles.MutedStyle.Render(m.error) + "\n\n")
 		b.WriteString(styles.HelpStyle.Render("Press Enter to exit") + "\n")
 		return styles.ContainerStyle.Render(b.String())
 	}
 
+	if m.editID != "" {
+		b.WriteString(styles.RenderSuccess("Task "+m.editID+" updated!") + "\n\n")
+		b.WriteString(fmt.Sprintf("  View task: %s\n", styles.BoldStyle.Render("agentic-agent task show "+m.editID)))
+		b.WriteString(fmt.Sprintf("  History:   %s\n\n", styles.BoldStyle.Render("agentic-agent task log "+m.editID)))
+		b.WriteString(styles.HelpStyle.Render("Press Enter to exit") + "\n")
+		return styles.ContainerStyle.Render(b.String())
+	}
+
 	b.WriteString(styles.RenderSuccess("Task created successfully!") + "\n\n")
 
 	b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("Task ID: %s", m.taskID)) + "\n\n")
@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultBaselinePath is where validate --update-baseline writes accepted
+// findings, relative to the project root.
+const DefaultBaselinePath = ".agentic/validation-baseline.json"
+
+const baselineVersion = 1
+
+// Baseline is a set of accepted findings. A run with a baseline reports
+// only the findings it does not contain, so a legacy repository can turn
+// validation on without fixing everything first.
+type Baseline struct {
+	Version  int             `json:"version"`
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// BaselineEntry records one accepted finding. The rule, path and message
+// are kept for review; only the fingerprint is matched.
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	RuleID      string `json:"rule_id"`
+	Path        string `json:"path,omitempty"`
+	Message     string `json:"message"`
+}
+
+// Fingerprint identifies a finding across runs by its rule, path and
+// message. The line is left out so edits elsewhere in a file do not
+// invalidate the baseline.
+func Fingerprint(f Finding) string {
+	sum := sha256.Sum256([]byte(f.RuleID + "\x00" + f.Path + "\x00" + f.Message))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewBaseline accepts every finding in results, except those about
+// suppression comments and rules that could not run.
+func NewBaseline(results []*RuleResult) *Baseline {
+	b := &Baseline{Version: baselineVersion, Findings: []BaselineEntry{}}
+	for _, res := range results {
+		if res.RuleName == SuppressionsRule || res.Status == "ERROR" {
+			continue // problems with suppressions and rules are never accepted
+		}
+		for _, f := range res.Findings {
+			b.Findings = append(b.Findings, BaselineEntry{
+				Fingerprint: Fingerprint(f),
+				RuleID:      f.RuleID,
+				Path:        f.Path,
+				Message:     f.Message,
+			})
+		}
+	}
+	sort.SliceStable(b.Findings, func(i, j int) bool {
+		a, c := b.Findings[i], b.Findings[j]
+		if a.RuleID != c.RuleID {
+			return a.RuleID < c.RuleID
+		}
+		if a.Path != c.Path {
+			return a.Path < c.Path
+		}
+		return a.Message < c.Message
+	})
+	return b
+}
+
+// LoadBaseline reads a baseline file. A missing file is an empty baseline.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Baseline{Version: baselineVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("baseline %s has version %d, expected %d; run validate --update-baseline", path, b.Version, baselineVersion)
+	}
+	return &b, nil
+}
+
+// Save writes the baseline as indented JSON, creating its directory.
+func (b *Baseline) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// apply removes the baselined findings from results. Each entry accepts
+// one finding, so a new duplicate of a baselined problem is still
+// reported. It returns the entries of rules that ran but matched nothing.
+func (b *Baseline) apply(results []*RuleResult) []BaselineEntry {
+	remaining := make(map[string][]BaselineEntry)
+	for _, e := range b.Findings {
+		remaining[e.Fingerprint] = append(remaining[e.Fingerprint], e)
+	}
+
+	ran := make(map[string]bool)
+	for _, res := range results {
+		if res.Status == "ERROR" {
+			continue
+		}
+		ran[res.RuleName] = true
+		res.filterFindings(func(f Finding) bool {
+			fp := Fingerprint(f)
+			if len(remaining[fp]) == 0 {
+				return true
+			}
+			remaining[fp] = remaining[fp][1:]
+			return false
+		})
+	}
+
+	var stale []BaselineEntry
+	for _, e := range b.Findings {
+		if entries := remaining[e.Fingerprint]; len(entries) > 0 && ran[e.RuleID] {
+			stale = append(stale, entries[0])
+			remaining[e.Fingerprint] = entries[1:]
+		}
+	}
+	return stale
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNotGitRepo is returned by GitChangedFiles outside a git repository.
+var ErrNotGitRepo = errors.New("not a git repository")
+
+// GitChangedFiles lists the files changed in the git repository containing
+// root, relative to root and sorted. Deleted files are included, so rules
+// that check files on disk must skip missing ones.
+//
+//   - staged: the changes in the index, as `git commit` would record them.
+//   - since:  everything that differs from the ref in the working tree, both
+//     committed and uncommitted, plus untracked files.
+//   - neither: uncommitted changes against HEAD plus untracked files.
+func GitChangedFiles(root, since string, staged bool) ([]string, error) {
+	if err := gitCommand(root, "rev-parse", "--git-dir").Run(); err != nil {
+		return nil, ErrNotGitRepo
+	}
+
+	var queries [][]string
+	switch {
+	case staged:
+		queries = [][]string{{"diff", "--name-only", "--relative", "--cached"}}
+	default:
+		ref := since
+		if ref == "" {
+			ref = "HEAD"
+		}
+		queries = [][]string{
+			{"diff", "--name-only", "--relative", ref, "--"},
+			{"ls-files", "--others", "--exclude-standard"},
+		}
+	}
+
+	seen := make(map[string]bool)
+	files := []string{}
+	for _, args := range queries {
+		out, err := gitCommand(root, args...).Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+				return nil, fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return nil, fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+		}
+		for _, f := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if f != "" && !seen[f] {
+				seen[f] = true
+				files = append(files, filepath.FromSlash(f))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func gitCommand(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	return cmd
+}
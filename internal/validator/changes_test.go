@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name string) {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+	}
+
+	_, err := GitChangedFiles(root, "", false)
+	assert.ErrorIs(t, err, ErrNotGitRepo)
+
+	write("base.go")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "base")
+	git("tag", "base")
+
+	write("api/committed.go")
+	git("add", "-A")
+	git("commit", "-q", "-m", "feature")
+	write("api/staged.go")
+	git("add", "api/staged.go")
+	write("web/untracked.ts")
+
+	files, err := GitChangedFiles(root, "base", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join("api", "committed.go"),
+		filepath.Join("api", "staged.go"),
+		filepath.Join("web", "untracked.ts"),
+	}, files)
+
+	files, err = GitChangedFiles(root, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("api", "staged.go")}, files)
+
+	files, err = GitChangedFiles(root, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("api", "staged.go"), filepath.Join("web", "untracked.ts")}, files)
+
+	_, err = GitChangedFiles(root, "no-such-ref", false)
+	assert.ErrorContains(t, err, "no-such-ref")
+}
+
+func TestValidationContext_Touches(t *testing.T) {
+	ctx := &ValidationContext{ProjectRoot: "/project"}
+	assert.True(t, ctx.Touches("anything"), "unscoped runs touch everything")
+
+	ctx.ChangedFiles = []string{filepath.Join("api", "v2", "routes.go")}
+	assert.True(t, ctx.Touches("api"))
+	assert.True(t, ctx.Touches(filepath.Join("/project", "api", "v2")))
+	assert.True(t, ctx.Touches(filepath.Join("api", "v2", "routes.go")))
+	assert.True(t, ctx.Touches("."))
+	assert.False(t, ctx.Touches("ap"))
+	assert.False(t, ctx.Touches("web"))
+	assert.Equal(t, map[string]bool{filepath.Join("api", "v2"): true}, ctx.ChangedDirs())
+
+	ctx.ChangedFiles = []string{}
+	assert.False(t, ctx.Touches("api"))
+}
@@ -0,0 +1,119 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Finding severities, named after the SARIF result levels.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// Finding is one structured problem reported by a rule. Path is relative
+// to the project root with forward slashes once the Validator has run;
+// Line is 1-based, and 0 when the finding is about a whole file or
+// directory.
+type Finding struct {
+	RuleID      string `json:"rule_id"`
+	Severity    string `json:"severity"` // "error", "warning", "note"
+	Path        string `json:"path,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// String formats the finding the way the text report prints it:
+// "path:line: message".
+func (f Finding) String() string {
+	switch {
+	case f.Path == "":
+		return f.Message
+	case f.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", f.Path, f.Line, f.Message)
+	}
+	return fmt.Sprintf("%s: %s", f.Path, f.Message)
+}
+
+// AddFinding records f on the result and its text form in Errors, so the
+// text and JSON reports keep listing every problem.
+func (r *RuleResult) AddFinding(f Finding) {
+	r.Findings = append(r.Findings, f)
+	r.Errors = append(r.Errors, f.String())
+}
+
+// normalizeFindings fills in what rules may leave out: the rule ID, a
+// severity matching the result status, and project-relative paths. Rules
+// that only report Errors get one message-only finding per error.
+func normalizeFindings(res *RuleResult, root string) {
+	level := LevelError
+	if res.Status == "WARN" {
+		level = LevelWarning
+	}
+	if len(res.Findings) == 0 {
+		for _, msg := range res.Errors {
+			res.Findings = append(res.Findings, Finding{Message: msg})
+		}
+	}
+
+	for i := range res.Findings {
+		f := &res.Findings[i]
+		if f.RuleID == "" {
+			f.RuleID = res.RuleName
+		}
+		if f.Severity == "" || (level == LevelWarning && f.Severity == LevelError) {
+			f.Severity = level
+		}
+		f.Path = projectPath(root, f.Path)
+	}
+}
+
+// projectPath makes path relative to root, with forward slashes as SARIF
+// and most report viewers expect.
+func projectPath(root, path string) string {
+	if path == "" {
+		return ""
+	}
+	if filepath.IsAbs(path) && root != "" {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// filterFindings drops the findings keep rejects, counting them as
+// suppressed, and recomputes the status from what is left.
+func (r *RuleResult) filterFindings(keep func(Finding) bool) {
+	aligned := len(r.Errors) == len(r.Findings)
+	var findings []Finding
+	var errs []string
+	for i, f := range r.Findings {
+		if !keep(f) {
+			r.Suppressed++
+			continue
+		}
+		findings = append(findings, f)
+		if aligned {
+			errs = append(errs, r.Errors[i])
+		} else {
+			errs = append(errs, f.String())
+		}
+	}
+	if len(findings) == len(r.Findings) {
+		return
+	}
+	r.Findings, r.Errors = findings, errs
+
+	r.Status = "PASS"
+	for _, f := range findings {
+		if f.Severity == LevelError {
+			r.Status = "FAIL"
+			break
+		}
+		r.Status = "WARN"
+	}
+}
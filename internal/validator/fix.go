@@ -0,0 +1,185 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Fixer is implemented by rules that can repair their own findings, such
+// as generating a missing context file. Fix proposes new file contents for
+// the findings in result and must not write them; validate --fix shows the
+// changes as a diff, writes them and runs the rule again to confirm.
+type Fixer interface {
+	ValidationRule
+	Fix(ctx *ValidationContext, result *RuleResult) ([]FileChange, error)
+}
+
+// FileChange is the full new content of one file.
+type FileChange struct {
+	Path        string // relative to ProjectRoot
+	Content     []byte
+	Description string
+}
+
+// FixResult is what validate --fix did for one rule.
+type FixResult struct {
+	RuleName string
+	Changes  []FileChange
+	Diff     string // unified diff of Changes against the files on disk
+
+	// After is the rule's result once the changes are written; nil in a
+	// dry run or when the fix failed.
+	After *RuleResult
+	Err   error
+}
+
+// Fixed reports whether the rule passes after the fix.
+func (f *FixResult) Fixed() bool {
+	return f.After != nil && f.After.Status == "PASS"
+}
+
+// Fix asks the fixable rules with findings in results for changes, writes
+// them unless dryRun, and runs each fixed rule again on a fresh snapshot.
+// Rules are fixed one at a time in registration order, so a fixer sees the
+// files written by the ones before it.
+func (v *Validator) Fix(ctx *ValidationContext, results []*RuleResult, dryRun bool) []*FixResult {
+	byName := make(map[string]*RuleResult, len(results))
+	for _, res := range results {
+		byName[res.RuleName] = res
+	}
+
+	var fixes []*FixResult
+	for _, rule := range v.rules {
+		fixer, ok := rule.(Fixer)
+		res := byName[rule.Name()]
+		if !ok || res == nil || res.Status == "ERROR" || len(res.Findings) == 0 {
+			continue
+		}
+
+		fix := &FixResult{RuleName: rule.Name()}
+		fixes = append(fixes, fix)
+		changes, err := fixer.Fix(ctx, res)
+		if err != nil {
+			fix.Err = err
+			continue
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		fix.Changes = changes
+		if fix.Diff, fix.Err = diffChanges(ctx.ProjectRoot, changes); fix.Err != nil || dryRun {
+			continue
+		}
+		if fix.Err = writeChanges(ctx.ProjectRoot, changes); fix.Err != nil {
+			continue
+		}
+
+		recheck := &ValidationContext{
+			ProjectRoot:  ctx.ProjectRoot,
+			Config:       ctx.Config,
+			ChangedFiles: ctx.ChangedFiles,
+			Context:      ctx.Context,
+		}
+		fix.After = v.runRule(recheck, rule)
+		v.settle(recheck, rule, fix.After)
+		applySuppressions(recheck, []*RuleResult{fix.After})
+		if v.baseline != nil {
+			v.baseline.apply([]*RuleResult{fix.After})
+		}
+	}
+	return fixes
+}
+
+// diffChanges renders changes as one unified diff, with new files diffed
+// against /dev/null.
+func diffChanges(root string, changes []FileChange) (string, error) {
+	var out string
+	for _, c := range changes {
+		from := "a/" + filepath.ToSlash(c.Path)
+		old, err := os.ReadFile(filepath.Join(root, c.Path))
+		if errors.Is(err, os.ErrNotExist) {
+			from = "/dev/null"
+		} else if err != nil {
+			return "", err
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        diffLines(old),
+			B:        diffLines(c.Content),
+			FromFile: from,
+			ToFile:   "b/" + filepath.ToSlash(c.Path),
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		out += diff
+	}
+	return out, nil
+}
+
+// diffLines splits data into lines for difflib, which expects each line to
+// end in a newline; its own SplitLines adds a spurious empty last line.
+func diffLines(data []byte) []string {
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n"
+	}
+	return lines
+}
+
+func writeChanges(root string, changes []FileChange) error {
+	for _, c := range changes {
+		path := filepath.Join(root, c.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, c.Content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFixReport prints the diff of each fix and whether the rule passes
+// afterwards.
+func WriteFixReport(w io.Writer, fixes []*FixResult, dryRun bool) {
+	if len(fixes) == 0 {
+		fmt.Fprintln(w, "Nothing to fix.")
+		return
+	}
+	for _, fix := range fixes {
+		switch {
+		case fix.Err != nil:
+			fmt.Fprintf(w, "💥 %s: fix failed: %v\n", fix.RuleName, fix.Err)
+			continue
+		case len(fix.Changes) == 0:
+			fmt.Fprintf(w, "⚠️ %s: no automatic fix for these findings\n", fix.RuleName)
+			continue
+		}
+
+		fmt.Fprintf(w, "🔧 %s:\n", fix.RuleName)
+		for _, c := range fix.Changes {
+			fmt.Fprintf(w, "  - %s: %s\n", c.Path, c.Description)
+		}
+		fmt.Fprint(w, fix.Diff)
+		switch {
+		case dryRun:
+		case fix.Fixed():
+			fmt.Fprintf(w, "✅ %s passes after the fix\n", fix.RuleName)
+		default:
+			fmt.Fprintf(w, "❌ %s still reports %d findings\n", fix.RuleName, len(fix.After.Findings))
+		}
+		fmt.Fprintln(w)
+	}
+	if dryRun {
+		fmt.Fprintln(w, "Dry run: no files were written.")
+	}
+}
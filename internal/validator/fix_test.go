@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// todoRule fails for every file containing TODO and fixes it by
+// replacing the marker with its fix text.
+type todoRule struct {
+	fix    string
+	fixErr error
+}
+
+func (r *todoRule) Name() string { return "no-todo" }
+
+func (r *todoRule) Validate(ctx *ValidationContext) (*RuleResult, error) {
+	res := &RuleResult{RuleName: r.Name(), Status: "PASS"}
+	files, err := ctx.FS().Files()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		data, _ := ctx.FS().ReadFile(f)
+		if bytes.Contains(data, []byte("TODO")) {
+			res.Status = "FAIL"
+			res.AddFinding(Finding{Path: f, Message: "TODO left in file"})
+		}
+	}
+	return res, nil
+}
+
+func (r *todoRule) Fix(ctx *ValidationContext, result *RuleResult) ([]FileChange, error) {
+	if r.fixErr != nil {
+		return nil, r.fixErr
+	}
+	var changes []FileChange
+	for _, f := range result.Findings {
+		data, err := os.ReadFile(filepath.Join(ctx.ProjectRoot, f.Path))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, FileChange{
+			Path:        f.Path,
+			Content:     bytes.ReplaceAll(data, []byte("TODO"), []byte(r.fix)),
+			Description: "resolve TODO",
+		})
+	}
+	return changes, nil
+}
+
+func TestValidator_Fix(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("one\nTODO\nthree\n"), 0644))
+
+	run := func(rule *todoRule, dryRun bool) []*FixResult {
+		v := NewValidator()
+		v.Register(rule)
+		v.Register(&findingRule{name: "unfixable", status: "FAIL", findings: []Finding{{Message: "no fixer"}}})
+		ctx := &ValidationContext{ProjectRoot: root}
+		results, err := v.Validate(ctx)
+		require.NoError(t, err)
+		return v.Fix(ctx, results, dryRun)
+	}
+
+	fixes := run(&todoRule{fix: "done"}, true)
+	require.Len(t, fixes, 1, "rules without a Fixer are skipped")
+	assert.Equal(t, "--- a/notes.txt\n+++ b/notes.txt\n@@ -1,3 +1,3 @@\n one\n-TODO\n+done\n three\n", fixes[0].Diff)
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "one\nTODO\nthree\n", string(data), "a dry run writes nothing")
+
+	fixes = run(&todoRule{fix: "still TODO"}, false)
+	require.NotNil(t, fixes[0].After)
+	assert.False(t, fixes[0].Fixed(), "the rule runs again on the written files")
+
+	var out bytes.Buffer
+	WriteFixReport(&out, fixes, false)
+	assert.Contains(t, out.String(), "❌ no-todo still reports 1 findings")
+
+	fixes = run(&todoRule{fixErr: errors.New("boom")}, false)
+	assert.EqualError(t, fixes[0].Err, "boom")
+}
@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as JUnit XML for test-report tooling: one test
+// suite per rule and one test case per finding, carrying its file and line.
+// Error findings are failures, and a rule that could not run is an error;
+// warnings and notes pass with the message in system-out. A rule without
+// findings is a single passing test case.
+func WriteJUnit(w io.Writer, results []*RuleResult) error {
+	report := junitTestSuites{Name: toolName}
+	for _, res := range results {
+		suite := junitTestSuite{Name: res.RuleName}
+		for _, f := range res.Findings {
+			tc := junitTestCase{
+				Name:      f.String(),
+				ClassName: res.RuleName,
+				File:      f.Path,
+				Line:      f.Line,
+			}
+			detail := f.Message
+			if f.Remediation != "" {
+				detail += "\nRemediation: " + f.Remediation
+			}
+			switch {
+			case res.Status == "ERROR":
+				tc.Error = &junitFailure{Message: f.Message, Type: f.RuleID, Body: detail}
+				suite.Errors++
+			case f.Severity == LevelError:
+				tc.Failure = &junitFailure{Message: f.Message, Type: f.RuleID, Body: detail}
+				suite.Failures++
+			default:
+				tc.SystemOut = strings.ToUpper(f.Severity) + ": " + detail
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: res.RuleName, ClassName: res.RuleName})
+		}
+		suite.Tests = len(suite.TestCases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,238 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity controls how a rule's failures are reported.
+type Severity string
+
+const (
+	SeverityFail Severity = "fail"
+	SeverityWarn Severity = "warn"
+	SeverityOff  Severity = "off"
+)
+
+// Exit thresholds for workflow.validation.fail_on.
+const (
+	FailOnFail  = "fail"  // exit nonzero on any FAIL (default)
+	FailOnWarn  = "warn"  // also exit nonzero on a WARN
+	FailOnNever = "never" // always exit zero; for report-only runs
+)
+
+// allValidators enables every registered rule in workflow.validators.
+const allValidators = "all"
+
+// Registry holds the known rules by name, so a Validator can be built from
+// the workflow section of the config.
+type Registry struct {
+	rules   map[string]ValidationRule
+	order   []string
+	aliases map[string][]string
+	project map[string]Severity // default severities of project rules
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		rules:   make(map[string]ValidationRule),
+		aliases: make(map[string][]string),
+		project: make(map[string]Severity),
+	}
+}
+
+// Register adds a rule under its Name. Registering a name twice replaces
+// the earlier rule but keeps its position.
+func (r *Registry) Register(rule ValidationRule) {
+	name := rule.Name()
+	if _, ok := r.rules[name]; !ok {
+		r.order = append(r.order, name)
+	}
+	r.rules[name] = rule
+}
+
+// RegisterProjectRule registers a rule the project declares itself, such
+// as one loaded from .agentic/rules. It runs even when workflow.validators
+// lists only some rules, and its failures are reported at severity unless
+// workflow.validation.rules.<name>.severity overrides it.
+func (r *Registry) RegisterProjectRule(rule ValidationRule, severity Severity) {
+	r.Register(rule)
+	r.project[rule.Name()] = severity
+}
+
+// Alias lets workflow.validators name a group of rules under another name.
+func (r *Registry) Alias(alias string, names ...string) {
+	r.aliases[alias] = names
+}
+
+// Names returns the registered rule names in registration order.
+func (r *Registry) Names() []string {
+	return append([]string{}, r.order...)
+}
+
+// Get returns the rule registered under name.
+func (r *Registry) Get(name string) (ValidationRule, bool) {
+	rule, ok := r.rules[name]
+	return rule, ok
+}
+
+// Build returns a Validator running the rules enabled by cfg:
+//
+//   - workflow.validators lists the rules to run, by name or alias; an
+//     empty list or "all" runs every rule.
+//   - workflow.validation.rules.<name>.severity turns a rule's failures
+//     into warnings ("warn") or disables it ("off").
+//   - workflow.validation.rules.<name>.options are passed to rules that
+//     implement ConfigurableRule.
+//   - workflow.validation.rule_timeout bounds each rule's run.
+//
+// Unknown rule names, severities and options are errors, so a typo in the
+// config does not silently disable a check.
+func (r *Registry) Build(cfg *models.Config) (*Validator, error) {
+	var workflow models.WorkflowConfig
+	if cfg != nil {
+		workflow = cfg.Workflow
+	}
+
+	enabled, err := r.enabled(workflow.Validators)
+	if err != nil {
+		return nil, err
+	}
+	for name := range workflow.Validation.Rules {
+		if _, ok := r.rules[name]; !ok {
+			return nil, fmt.Errorf("workflow.validation.rules: unknown rule %q (available: %s)", name, strings.Join(r.order, ", "))
+		}
+	}
+	if _, err := ParseFailOn(workflow.Validation.FailOn); err != nil {
+		return nil, fmt.Errorf("workflow.validation.fail_on: %w", err)
+	}
+
+	v := NewValidator()
+	if workflow.Validation.RuleTimeout != "" {
+		timeout, err := time.ParseDuration(workflow.Validation.RuleTimeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("workflow.validation.rule_timeout: invalid duration %q", workflow.Validation.RuleTimeout)
+		}
+		v.SetRuleTimeout(timeout)
+	}
+	for _, name := range r.order {
+		defaultSeverity, isProject := r.project[name]
+		if !enabled[name] && !isProject {
+			continue
+		}
+		rule := r.rules[name]
+		ruleCfg := workflow.Validation.Rules[name]
+
+		severity := defaultSeverity
+		if ruleCfg.Severity != "" || !isProject {
+			if severity, err = ParseSeverity(ruleCfg.Severity); err != nil {
+				return nil, fmt.Errorf("rule %s: %w", name, err)
+			}
+		}
+		if severity == SeverityOff {
+			continue
+		}
+
+		if len(ruleCfg.Options) > 0 {
+			configurable, ok := rule.(ConfigurableRule)
+			if !ok {
+				return nil, fmt.Errorf("rule %s does not take options", name)
+			}
+			if err := configurable.Configure(ruleCfg.Options); err != nil {
+				return nil, fmt.Errorf("rule %s: %w", name, err)
+			}
+		}
+		v.RegisterWithSeverity(rule, severity)
+	}
+	return v, nil
+}
+
+func (r *Registry) enabled(validators []string) (map[string]bool, error) {
+	enabled := make(map[string]bool)
+	if len(validators) == 0 {
+		validators = []string{allValidators}
+	}
+	for _, name := range validators {
+		switch {
+		case name == allValidators:
+			for _, n := range r.order {
+				enabled[n] = true
+			}
+		case r.aliases[name] != nil:
+			for _, n := range r.aliases[name] {
+				enabled[n] = true
+			}
+		case r.rules[name] != nil:
+			enabled[name] = true
+		default:
+			known := append(r.Names(), allValidators)
+			for alias := range r.aliases {
+				known = append(known, alias)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("workflow.validators: unknown validator %q (available: %s)", name, strings.Join(known, ", "))
+		}
+	}
+	return enabled, nil
+}
+
+// ParseSeverity validates a rule severity; empty means SeverityFail.
+func ParseSeverity(value string) (Severity, error) {
+	switch severity := Severity(strings.ToLower(value)); severity {
+	case "":
+		return SeverityFail, nil
+	case SeverityFail, SeverityWarn, SeverityOff:
+		return severity, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (expected fail, warn or off)", value)
+}
+
+// ParseFailOn validates a workflow.validation.fail_on value; empty means
+// FailOnFail.
+func ParseFailOn(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "", FailOnFail:
+		return FailOnFail, nil
+	case FailOnWarn, FailOnNever:
+		return strings.ToLower(value), nil
+	}
+	return "", fmt.Errorf("unknown fail-on threshold %q (expected fail, warn or never)", value)
+}
+
+// ExitCode returns 1 when any result reaches the failOn threshold, else 0.
+// A rule that could not run (ERROR) counts as a failure.
+func ExitCode(results []*RuleResult, failOn string) int {
+	if failOn == FailOnNever {
+		return 0
+	}
+	for _, res := range results {
+		switch {
+		case res.Status == "FAIL" || res.Status == "ERROR":
+			return 1
+		case res.Status == "WARN" && failOn == FailOnWarn:
+			return 1
+		}
+	}
+	return 0
+}
+
+// DecodeOptions copies rule options onto the yaml-tagged fields of dst.
+// Options that do not match a field are an error.
+func DecodeOptions(options map[string]interface{}, dst interface{}) error {
+	data, err := yaml.Marshal(options)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRule struct {
+	name   string
+	status string
+}
+
+func (r *stubRule) Name() string { return r.name }
+
+func (r *stubRule) Validate(ctx *ValidationContext) (*RuleResult, error) {
+	return &RuleResult{RuleName: r.name, Status: r.status}, nil
+}
+
+type limitRule struct {
+	stubRule
+	Limit int `yaml:"limit"`
+}
+
+func (r *limitRule) Configure(options map[string]interface{}) error {
+	return DecodeOptions(options, r)
+}
+
+func newTestRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(&stubRule{name: "alpha", status: "PASS"})
+	r.Register(&stubRule{name: "beta", status: "FAIL"})
+	r.Register(&limitRule{stubRule: stubRule{name: "gamma", status: "FAIL"}})
+	r.Alias("group", "alpha", "beta")
+	return r
+}
+
+func ruleNames(v *Validator) []string {
+	var names []string
+	for _, rule := range v.Rules() {
+		names = append(names, rule.Name())
+	}
+	return names
+}
+
+func configWith(validators []string, validation models.ValidationConfig) *models.Config {
+	return &models.Config{Workflow: models.WorkflowConfig{Validators: validators, Validation: validation}}
+}
+
+func TestRegistry_EnabledRules(t *testing.T) {
+	r := newTestRegistry()
+
+	v, err := r.Build(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "beta", "gamma"}, ruleNames(v), "no config runs every rule")
+
+	v, err = r.Build(configWith([]string{"gamma", "group"}, models.ValidationConfig{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "beta", "gamma"}, ruleNames(v), "registration order is kept")
+
+	v, err = r.Build(configWith([]string{"beta"}, models.ValidationConfig{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"beta"}, ruleNames(v))
+
+	v, err = r.Build(configWith([]string{"all"}, models.ValidationConfig{
+		Rules: map[string]models.RuleConfig{"alpha": {Severity: "off"}},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"beta", "gamma"}, ruleNames(v))
+
+	_, err = r.Build(configWith([]string{"betta"}, models.ValidationConfig{}))
+	assert.ErrorContains(t, err, `unknown validator "betta"`)
+}
+
+func TestRegistry_SeverityAndOptions(t *testing.T) {
+	r := newTestRegistry()
+	v, err := r.Build(configWith(nil, models.ValidationConfig{
+		Rules: map[string]models.RuleConfig{
+			"beta":  {Severity: "warn"},
+			"gamma": {Options: map[string]interface{}{"limit": 7}},
+		},
+	}))
+	require.NoError(t, err)
+
+	results, err := v.Validate(&ValidationContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "PASS", results[0].Status)
+	assert.Equal(t, "WARN", results[1].Status, "warn severity downgrades a failure")
+	assert.Equal(t, "FAIL", results[2].Status)
+
+	gamma, _ := r.Get("gamma")
+	assert.Equal(t, 7, gamma.(*limitRule).Limit)
+}
+
+func TestRegistry_InvalidConfig(t *testing.T) {
+	cases := map[string]models.ValidationConfig{
+		`unknown rule "delta"`:                           {Rules: map[string]models.RuleConfig{"delta": {}}},
+		`unknown severity "loud"`:                        {Rules: map[string]models.RuleConfig{"beta": {Severity: "loud"}}},
+		"rule alpha does not take":                       {Rules: map[string]models.RuleConfig{"alpha": {Options: map[string]interface{}{"x": 1}}}},
+		"field limitt not found":                         {Rules: map[string]models.RuleConfig{"gamma": {Options: map[string]interface{}{"limitt": 1}}}},
+		`fail_on: unknown fail-on threshold "sometimes"`: {FailOn: "sometimes"},
+		`rule_timeout: invalid duration "soon"`:          {RuleTimeout: "soon"},
+	}
+	for want, validation := range cases {
+		_, err := newTestRegistry().Build(configWith(nil, validation))
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	pass := []*RuleResult{{Status: "PASS"}}
+	warn := []*RuleResult{{Status: "PASS"}, {Status: "WARN"}}
+	fail := []*RuleResult{{Status: "WARN"}, {Status: "FAIL"}}
+
+	assert.Equal(t, 0, ExitCode(pass, FailOnWarn))
+	assert.Equal(t, 0, ExitCode(warn, FailOnFail))
+	assert.Equal(t, 1, ExitCode(warn, FailOnWarn))
+	assert.Equal(t, 1, ExitCode(fail, FailOnFail))
+	assert.Equal(t, 0, ExitCode(fail, FailOnNever))
+}
@@ -6,36 +6,58 @@ import (
 	"os"
 )
 
-func PrintReport(results []*RuleResult, format string) {
-	if format == "json" {
+// PrintReport prints results as text, json, sarif or junit and exits
+// nonzero when they reach the failOn threshold (see ExitCode).
+func PrintReport(results []*RuleResult, format string, failOn string) {
+	code := ExitCode(results, failOn)
+	var err error
+	switch format {
+	case "json":
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		enc.Encode(results)
-		return
+		err = enc.Encode(results)
+	case "sarif":
+		err = WriteSARIF(os.Stdout, results)
+	case "junit":
+		err = WriteJUnit(os.Stdout, results)
+	default:
+		printText(results, code)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s report: %v\n", format, err)
+		os.Exit(1)
 	}
+	if code != 0 {
+		os.Exit(code)
+	}
+}
+
+func printText(results []*RuleResult, code int) {
 
 	// Text format
-	passed := true
 	for _, res := range results {
-		if res.Status != "PASS" {
-			passed = false
-		}
 		icon := "✅"
 		if res.Status == "FAIL" {
 			icon = "❌"
+		} else if res.Status == "ERROR" {
+			icon = "💥"
 		} else if res.Status == "WARN" {
 			icon = "⚠️"
 		}
 
-		fmt.Printf("%s Rule: %s\n", icon, res.RuleName)
+		if res.Suppressed > 0 {
+			fmt.Printf("%s Rule: %s (%d suppressed)\n", icon, res.RuleName, res.Suppressed)
+		} else {
+			fmt.Printf("%s Rule: %s\n", icon, res.RuleName)
+		}
 		for _, err := range res.Errors {
 			fmt.Printf("  - %s\n", err)
 		}
 	}
 
-	if !passed {
+	if code != 0 {
 		fmt.Println("\nValidation FAILED.")
-		os.Exit(1)
+		return
 	}
 	fmt.Println("\nValidation PASSED.")
 }
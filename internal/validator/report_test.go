@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type findingRule struct {
+	name     string
+	status   string
+	findings []Finding
+	errors   []string
+}
+
+func (r *findingRule) Name() string { return r.name }
+
+func (r *findingRule) Validate(ctx *ValidationContext) (*RuleResult, error) {
+	res := &RuleResult{RuleName: r.name, Status: r.status, Errors: append([]string{}, r.errors...)}
+	for _, f := range r.findings {
+		res.AddFinding(f)
+	}
+	return res, nil
+}
+
+func sampleResults(t *testing.T) []*RuleResult {
+	t.Helper()
+	root := t.TempDir()
+	v := NewValidator()
+	v.Register(&findingRule{name: "imports", status: "FAIL", findings: []Finding{{
+		Path:        filepath.Join(root, "api", "handler.go"),
+		Line:        4,
+		Message:     `imports "database/sql"`,
+		Remediation: "Use the repository package",
+	}}})
+	v.RegisterWithSeverity(&findingRule{name: "size", status: "FAIL", findings: []Finding{{
+		Path:    filepath.Join("api", "big.go"),
+		Message: "too many lines",
+	}}}, SeverityWarn)
+	v.Register(&findingRule{name: "legacy", status: "FAIL", errors: []string{"something is off"}})
+	v.Register(&findingRule{name: "clean", status: "PASS"})
+
+	results, err := v.Validate(&ValidationContext{ProjectRoot: root})
+	require.NoError(t, err)
+	return results
+}
+
+func TestFinding_String(t *testing.T) {
+	assert.Equal(t, "a.go:3: bad", Finding{Path: "a.go", Line: 3, Message: "bad"}.String())
+	assert.Equal(t, "src: bad", Finding{Path: "src", Message: "bad"}.String())
+	assert.Equal(t, "bad", Finding{Message: "bad"}.String())
+}
+
+func TestValidate_NormalizesFindings(t *testing.T) {
+	results := sampleResults(t)
+
+	assert.Equal(t, []Finding{{
+		RuleID:      "imports",
+		Severity:    LevelError,
+		Path:        "api/handler.go",
+		Line:        4,
+		Message:     `imports "database/sql"`,
+		Remediation: "Use the repository package",
+	}}, results[0].Findings, "absolute paths become project-relative")
+	assert.Len(t, results[0].Errors, 1)
+
+	assert.Equal(t, "WARN", results[1].Status)
+	assert.Equal(t, LevelWarning, results[1].Findings[0].Severity, "warn severity downgrades findings")
+
+	assert.Equal(t, []Finding{{RuleID: "legacy", Severity: LevelError, Message: "something is off"}}, results[2].Findings,
+		"plain errors become message-only findings")
+	assert.Empty(t, results[3].Findings)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, sampleResults(t)))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "agentic-agent", run.Tool.Driver.Name)
+	assert.Len(t, run.Tool.Driver.Rules, 4, "every rule that ran is listed")
+	require.Len(t, run.Results, 3)
+
+	first := run.Results[0]
+	assert.Equal(t, "imports", first.RuleID)
+	assert.Equal(t, 0, first.RuleIndex)
+	assert.Equal(t, "error", first.Level)
+	assert.Equal(t, "imports \"database/sql\"\n\nRemediation: Use the repository package", first.Message.Text)
+	require.Len(t, first.Locations, 1)
+	assert.Equal(t, "api/handler.go", first.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 4, first.Locations[0].PhysicalLocation.Region.StartLine)
+
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Nil(t, run.Results[1].Locations[0].PhysicalLocation.Region, "no line, no region")
+	assert.Empty(t, run.Results[2].Locations)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJUnit(&buf, sampleResults(t)))
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 4, report.Tests)
+	assert.Equal(t, 2, report.Failures)
+	require.Len(t, report.Suites, 4)
+
+	imports := report.Suites[0].TestCases[0]
+	assert.Equal(t, "api/handler.go", imports.File)
+	assert.Equal(t, 4, imports.Line)
+	require.NotNil(t, imports.Failure)
+	assert.Contains(t, imports.Failure.Body, "Remediation: Use the repository package")
+
+	size := report.Suites[1].TestCases[0]
+	assert.Nil(t, size.Failure, "warnings do not fail the test case")
+	assert.Equal(t, "WARNING: too many lines", size.SystemOut)
+
+	clean := report.Suites[3]
+	assert.Equal(t, 0, clean.Failures)
+	require.Len(t, clean.TestCases, 1)
+	assert.Equal(t, "clean", clean.TestCases[0].Name)
+}
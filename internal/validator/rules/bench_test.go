@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/internal/validator"
+)
+
+// syntheticTree lays out 50 packages of 100 directories each, every one
+// with a Go source file, most with an AGENTS.md, and a context.md with
+// constraints per package.
+func syntheticTree(b *testing.B) string {
+	b.Helper()
+	root := b.TempDir()
+	for p := 0; p < 50; p++ {
+		pkg := filepath.Join(root, fmt.Sprintf("pkg%02d", p))
+		for d := 0; d < 100; d++ {
+			dir := filepath.Join(pkg, fmt.Sprintf("mod%03d", d))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				b.Fatal(err)
+			}
+			src := fmt.Sprintf("package mod%03d\n\nimport \"fmt\"\n\nfunc Run() { fmt.Sprint(%d) }\n", d, d)
+			if err := os.WriteFile(filepath.Join(dir, "mod.go"), []byte(src), 0644); err != nil {
+				b.Fatal(err)
+			}
+			if d%10 != 0 {
+				if err := os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("# Context\n"), 0644); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		context := "## Cannot Do\n- forbid-import: database/sql\n- forbid-pattern: TODO\n"
+		if err := os.WriteFile(filepath.Join(pkg, "context.md"), []byte(context), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return root
+}
+
+func BenchmarkValidate_5kDirectories(b *testing.B) {
+	root := syntheticTree(b)
+	for _, concurrency := range []int{1, 0} {
+		name := "parallel"
+		if concurrency == 1 {
+			name = "sequential"
+		}
+		b.Run(name, func(b *testing.B) {
+			v := validator.NewValidator()
+			v.SetConcurrency(concurrency)
+			for _, rule := range []validator.ValidationRule{
+				&DirectoryContextRule{},
+				&ContextUpdateRule{},
+				&ContextConstraintsRule{},
+				&SkillTierRule{},
+			} {
+				v.Register(rule)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				results, err := v.Validate(&validator.ValidationContext{ProjectRoot: root})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if results[0].Status != "FAIL" || len(results[0].Findings) != 500 {
+					b.Fatalf("context-required: %s with %d findings", results[0].Status, len(results[0].Findings))
+				}
+			}
+		})
+	}
+}
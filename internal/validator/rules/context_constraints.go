@@ -0,0 +1,279 @@
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	appcontext "github.com/javierbenavides/agentic-agent/internal/context"
+	"github.com/javierbenavides/agentic-agent/internal/validator"
+)
+
+// ContextConstraintsRule enforces the machine-checkable Must Do and Cannot
+// Do items of each directory's context.md (see appcontext.ParseConstraints)
+// on that directory and everything below it. A run scoped by
+// ValidationContext.ChangedFiles checks only those files; otherwise, inside
+// a git repository with uncommitted changes only the changed files are
+// checked, and the whole tree is checked when there are none.
+type ContextConstraintsRule struct{}
+
+func (r *ContextConstraintsRule) Name() string {
+	return "context-constraints"
+}
+
+// constrainedDir is a directory whose context.md declares constraints.
+type constrainedDir struct {
+	dir         string // relative to the project root, "." for the root
+	constraints []appcontext.Constraint
+}
+
+func (r *ContextConstraintsRule) Validate(ctx *validator.ValidationContext) (*validator.RuleResult, error) {
+	result := &validator.RuleResult{
+		RuleName: r.Name(),
+		Status:   "PASS",
+		Errors:   []string{},
+	}
+
+	var dirs []constrainedDir
+	var files []string
+	changed := ctx.ChangedFiles
+	if ctx.Scoped() {
+		dirs = ancestorConstraints(ctx.FS(), changed, result)
+	} else {
+		var err error
+		if dirs, files, err = collectConstraints(ctx.FS(), result); err != nil {
+			return nil, err
+		}
+		if modified, err := ctx.FS().WorkingTreeChanges(); err == nil && len(modified) > 0 {
+			changed = modified
+		}
+	}
+	if len(dirs) == 0 && len(result.Errors) == 0 {
+		return result, nil
+	}
+
+	checkDirs := dirs
+	if changed != nil {
+		files = changed
+		checkDirs = nil
+		for _, d := range dirs {
+			for _, f := range changed {
+				if inDir(f, d.dir) {
+					checkDirs = append(checkDirs, d)
+					break
+				}
+			}
+		}
+	}
+
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !appcontext.IsSourceFile(f) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(ctx.ProjectRoot, f)); err != nil {
+			continue // deleted in the working tree
+		}
+		for _, d := range dirs {
+			if inDir(f, d.dir) {
+				checkFile(ctx.ProjectRoot, f, d, result)
+			}
+		}
+	}
+	for _, d := range checkDirs {
+		checkRequiredFiles(ctx.ProjectRoot, d, result)
+	}
+
+	if len(result.Errors) > 0 {
+		result.Status = "FAIL"
+	}
+	return result, nil
+}
+
+// collectConstraints finds the context.md files of the project and returns
+// the directories that declare constraints together with every file in the
+// tree. Malformed directives are reported on result.
+func collectConstraints(snap *validator.Snapshot, result *validator.RuleResult) ([]constrainedDir, []string, error) {
+	all, err := snap.Dirs()
+	if err != nil {
+		return nil, nil, err
+	}
+	var dirs []constrainedDir
+	for _, dir := range all {
+		if !hasEntry(snap.Entries(dir), "context.md") {
+			continue
+		}
+		if d, ok := loadConstraints(snap, dir, result); ok {
+			dirs = append(dirs, d)
+		}
+	}
+	files, err := snap.Files()
+	return dirs, files, err
+}
+
+// ancestorConstraints returns the constrained directories containing any
+// of the changed files, reading only the context.md files on their paths.
+func ancestorConstraints(snap *validator.Snapshot, changed []string, result *validator.RuleResult) []constrainedDir {
+	seen := make(map[string]bool)
+	var dirs []constrainedDir
+	for _, f := range changed {
+		for dir := filepath.Dir(f); !seen[dir]; dir = filepath.Dir(dir) {
+			seen[dir] = true
+			if d, ok := loadConstraints(snap, dir, result); ok {
+				dirs = append(dirs, d)
+			}
+			if dir == "." {
+				break
+			}
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].dir < dirs[j].dir })
+	return dirs
+}
+
+// loadConstraints parses the context.md of dir, if any. Malformed
+// directives are reported on result.
+func loadConstraints(snap *validator.Snapshot, dir string, result *validator.RuleResult) (constrainedDir, bool) {
+	data, err := snap.ReadFile(contextFile(dir))
+	if err != nil {
+		return constrainedDir{}, false
+	}
+	constraints, errs := appcontext.ParseConstraints(appcontext.ParseContextDocument(data).Context(dir))
+	for _, e := range errs {
+		result.AddFinding(validator.Finding{
+			Path:        contextFile(dir),
+			Message:     e.Error(),
+			Remediation: "Fix the directive so it parses, or remove it",
+		})
+	}
+	return constrainedDir{dir: dir, constraints: constraints}, len(constraints) > 0
+}
+
+// checkFile applies the import and pattern constraints of d to one file.
+func checkFile(root, file string, d constrainedDir, result *validator.RuleResult) {
+	path := filepath.Join(root, file)
+
+	var imports []appcontext.ImportRef
+	var lines []string
+	for _, c := range d.constraints {
+		switch c.Kind {
+		case appcontext.ForbidImport:
+			if imports == nil {
+				imports, _ = appcontext.FileImports(path)
+			}
+			for _, imp := range imports {
+				if c.MatchesImport(imp.Path) || c.MatchesImport(resolveRelativeImport(file, imp.Path)) {
+					result.AddFinding(validator.Finding{
+						Path:        file,
+						Line:        imp.Line,
+						Message:     fmt.Sprintf("imports %q, forbidden by %s (%s)", imp.Path, contextFile(d.dir), c.Item),
+						Remediation: fmt.Sprintf("Remove the import or move this code out of %s", d.dir),
+					})
+				}
+			}
+		case appcontext.ForbidPattern:
+			if lines == nil {
+				lines = readLines(path)
+			}
+			for i, line := range lines {
+				if c.Pattern.MatchString(line) {
+					result.AddFinding(validator.Finding{
+						Path:        file,
+						Line:        i + 1,
+						Message:     fmt.Sprintf("matches forbidden pattern %q from %s: %s", c.Value, contextFile(d.dir), strings.TrimSpace(line)),
+						Remediation: fmt.Sprintf("Rewrite the line so it no longer matches %q", c.Value),
+					})
+				}
+			}
+		case appcontext.RequireTest:
+			if !requiresTest(file, c) {
+				continue
+			}
+			candidates := appcontext.TestFileCandidates(filepath.Base(file))
+			found := false
+			for _, candidate := range candidates {
+				if _, err := os.Stat(filepath.Join(root, filepath.Dir(file), candidate)); err == nil {
+					found = true
+					break
+				}
+			}
+			if !found {
+				testFile := filepath.Join(filepath.Dir(file), candidates[0])
+				result.AddFinding(validator.Finding{
+					Path:        file,
+					Message:     fmt.Sprintf("missing test file %s, required by %s (%s)", testFile, contextFile(d.dir), c.Item),
+					Remediation: fmt.Sprintf("Add tests in %s", testFile),
+				})
+			}
+		}
+	}
+}
+
+// checkRequiredFiles applies the require-file constraints of d to its own
+// directory.
+func checkRequiredFiles(root string, d constrainedDir, result *validator.RuleResult) {
+	for _, c := range d.constraints {
+		if c.Kind != appcontext.RequireFile {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(root, d.dir, c.Value))
+		if len(matches) == 0 {
+			result.AddFinding(validator.Finding{
+				Path:        d.dir,
+				Message:     fmt.Sprintf("missing required file %s, required by %s (%s)", c.Value, contextFile(d.dir), c.Item),
+				Remediation: fmt.Sprintf("Add %s", filepath.Join(d.dir, c.Value)),
+			})
+		}
+	}
+}
+
+// requiresTest reports whether a require-test constraint covers file.
+func requiresTest(file string, c appcontext.Constraint) bool {
+	if appcontext.IsTestFile(file) {
+		return false
+	}
+	if c.Value == "" {
+		return true
+	}
+	ok, _ := filepath.Match(c.Value, filepath.Base(file))
+	return ok
+}
+
+// resolveRelativeImport turns a "./" or "../" import into a path relative
+// to the project root, so "forbid-import: src/db" also catches "../db".
+func resolveRelativeImport(file, imp string) string {
+	if !strings.HasPrefix(imp, "./") && !strings.HasPrefix(imp, "../") {
+		return imp
+	}
+	return filepath.ToSlash(filepath.Join(filepath.Dir(file), imp))
+}
+
+func readLines(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return []string{}
+	}
+	defer f.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// inDir reports whether the relative path file lies in dir or below it.
+func inDir(file, dir string) bool {
+	return dir == "." || strings.HasPrefix(file, dir+string(filepath.Separator))
+}
+
+func contextFile(dir string) string {
+	return filepath.Join(dir, "context.md")
+}
@@ -0,0 +1,179 @@
+package rules
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/internal/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const apiContext = `# Context for internal/api
+
+## Cannot Do
+- Talk to the database directly ` + "`forbid-import: database/sql`" + `
+- forbid-import: github.com/acme/legacy
+- Never print to stdout ` + "`forbid-pattern: fmt\\.Print`" + `
+- Keep handlers thin
+
+## Must Do
+- require-file: README.md
+- require-test: *.go
+`
+
+func writeProjectFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestContextConstraintsRule_Name(t *testing.T) {
+	rule := &ContextConstraintsRule{}
+	assert.Equal(t, "context-constraints", rule.Name())
+}
+
+func TestContextConstraintsRule_NoConstraints(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "src/context.md", "# Context\n\n## Cannot Do\n- Anything silly\n")
+	writeProjectFile(t, tmpDir, "src/main.go", "package main\n\nimport \"database/sql\"\n")
+
+	result, err := (&ContextConstraintsRule{}).Validate(&validator.ValidationContext{ProjectRoot: tmpDir})
+	require.NoError(t, err)
+	assert.Equal(t, "PASS", result.Status)
+	assert.Empty(t, result.Errors)
+}
+
+func TestContextConstraintsRule_Violations(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "internal/api/context.md", apiContext)
+	writeProjectFile(t, tmpDir, "internal/api/handler.go", `package api
+
+import (
+	"database/sql/driver"
+	"net/http"
+)
+
+func Handle(w http.ResponseWriter) {
+	fmt.Println("hi")
+}
+`)
+	writeProjectFile(t, tmpDir, "internal/api/handler_test.go", "package api\n\nfunc helper() { fmt.Println() }\n")
+	writeProjectFile(t, tmpDir, "internal/api/v2/routes.go", "package v2\n\nimport _ \"github.com/acme/legacy/db\"\n")
+	writeProjectFile(t, tmpDir, "internal/other/ok.go", "package other\n\nimport \"database/sql\"\n")
+
+	result, err := (&ContextConstraintsRule{}).Validate(&validator.ValidationContext{ProjectRoot: tmpDir})
+	require.NoError(t, err)
+	assert.Equal(t, "FAIL", result.Status)
+
+	api := filepath.Join("internal", "api")
+	assert.ElementsMatch(t, []string{
+		filepath.Join(api, "handler.go") + `:4: imports "database/sql/driver", forbidden by ` + filepath.Join(api, "context.md") + " (Talk to the database directly `forbid-import: database/sql`)",
+		filepath.Join(api, "handler.go") + `:9: matches forbidden pattern "fmt\\.Print" from ` + filepath.Join(api, "context.md") + `: fmt.Println("hi")`,
+		filepath.Join(api, "handler_test.go") + `:3: matches forbidden pattern "fmt\\.Print" from ` + filepath.Join(api, "context.md") + `: func helper() { fmt.Println() }`,
+		filepath.Join(api, "v2", "routes.go") + `:3: imports "github.com/acme/legacy/db", forbidden by ` + filepath.Join(api, "context.md") + " (forbid-import: github.com/acme/legacy)",
+		filepath.Join(api, "v2", "routes.go") + ": missing test file " + filepath.Join(api, "v2", "routes_test.go") + ", required by " + filepath.Join(api, "context.md") + " (require-test: *.go)",
+		api + ": missing required file README.md, required by " + filepath.Join(api, "context.md") + " (require-file: README.md)",
+	}, result.Errors)
+}
+
+func TestContextConstraintsRule_ScriptImports(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "src/api/context.md", "## Cannot Do\n- `forbid-import: src/db`\n- `forbid-import: sqlalchemy`\n")
+	writeProjectFile(t, tmpDir, "src/api/index.ts", "// import db from '../db';\nimport { query } from '../db/query';\n")
+	writeProjectFile(t, tmpDir, "src/api/views.py", "from sqlalchemy.orm import Session\n")
+
+	result, err := (&ContextConstraintsRule{}).Validate(&validator.ValidationContext{ProjectRoot: tmpDir})
+	require.NoError(t, err)
+	assert.Equal(t, "FAIL", result.Status)
+	require.Len(t, result.Errors, 2)
+	assert.Contains(t, result.Errors[0], filepath.Join("src", "api", "index.ts")+`:2: imports "../db/query"`)
+	assert.Contains(t, result.Errors[1], filepath.Join("src", "api", "views.py")+`:1: imports "sqlalchemy.orm"`)
+}
+
+func TestContextConstraintsRule_MalformedDirective(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "src/context.md", "## Cannot Do\n- `forbid-pattern: (unclosed`\n")
+
+	result, err := (&ContextConstraintsRule{}).Validate(&validator.ValidationContext{ProjectRoot: tmpDir})
+	require.NoError(t, err)
+	assert.Equal(t, "FAIL", result.Status)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], filepath.Join("src", "context.md")+": invalid forbid-pattern")
+}
+
+func TestContextConstraintsRule_OnlyChangedFilesInGitRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := setupTestProject(t)
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = tmpDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	writeProjectFile(t, tmpDir, "api/context.md", "## Cannot Do\n- forbid-pattern: TODO\n")
+	writeProjectFile(t, tmpDir, "api/old.go", "package api\n\n// TODO: committed before the rule\n")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "init")
+
+	rule := &ContextConstraintsRule{}
+	ctx := &validator.ValidationContext{ProjectRoot: tmpDir}
+
+	// A clean working tree is checked in full
+	result, err := rule.Validate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("api", "old.go") + `:3: matches forbidden pattern "TODO" from ` + filepath.Join("api", "context.md") + ": // TODO: committed before the rule"}, result.Errors)
+
+	// With changes, only the changed and new files are
+	writeProjectFile(t, tmpDir, "api/new.go", "package api\n\n// TODO: new\n")
+	result, err = rule.Validate(&validator.ValidationContext{ProjectRoot: tmpDir})
+	require.NoError(t, err)
+	assert.Equal(t, "FAIL", result.Status)
+	assert.Equal(t, []string{filepath.Join("api", "new.go") + `:3: matches forbidden pattern "TODO" from ` + filepath.Join("api", "context.md") + ": // TODO: new"}, result.Errors)
+}
+
+func TestContextConstraintsRule_Findings(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "api/context.md", "## Cannot Do\n- forbid-import: database/sql\n")
+	writeProjectFile(t, tmpDir, "api/db.go", "package api\n\nimport \"database/sql\"\n")
+
+	v := validator.NewValidator()
+	v.Register(&ContextConstraintsRule{})
+	results, err := v.Validate(&validator.ValidationContext{ProjectRoot: tmpDir})
+	require.NoError(t, err)
+	require.Len(t, results[0].Findings, 1)
+
+	f := results[0].Findings[0]
+	assert.Equal(t, "context-constraints", f.RuleID)
+	assert.Equal(t, validator.LevelError, f.Severity)
+	assert.Equal(t, "api/db.go", f.Path)
+	assert.Equal(t, 3, f.Line)
+	assert.Equal(t, `imports "database/sql", forbidden by `+filepath.Join("api", "context.md")+" (forbid-import: database/sql)", f.Message)
+	assert.Equal(t, "Remove the import or move this code out of api", f.Remediation)
+}
+
+func TestContextConstraintsRule_ScopedToChangedFiles(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "api/context.md", "## Cannot Do\n- forbid-pattern: TODO\n\n## Must Do\n- require-file: README.md\n")
+	writeProjectFile(t, tmpDir, "api/old.go", "package api\n\n// TODO: old\n")
+	writeProjectFile(t, tmpDir, "api/v2/new.go", "package v2\n\n// TODO: new\n")
+	writeProjectFile(t, tmpDir, "web/context.md", "## Must Do\n- require-file: README.md\n")
+
+	rule := &ContextConstraintsRule{}
+	result, err := rule.Validate(&validator.ValidationContext{
+		ProjectRoot:  tmpDir,
+		ChangedFiles: []string{filepath.Join("api", "v2", "new.go")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join("api", "v2", "new.go") + `:3: matches forbidden pattern "TODO" from ` + filepath.Join("api", "context.md") + ": // TODO: new",
+		"api: missing required file README.md, required by " + filepath.Join("api", "context.md") + " (require-file: README.md)",
+	}, result.Errors, "web is untouched and api/old.go unchanged")
+}
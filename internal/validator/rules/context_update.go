@@ -2,10 +2,11 @@ package rules
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"strings"
 
+	appcontext "github.com/javierbenavides/agentic-agent/internal/context"
 	"github.com/javierbenavides/agentic-agent/internal/validator"
 )
 
@@ -16,54 +17,88 @@ func (r *ContextUpdateRule) Name() string {
 }
 
 func (r *ContextUpdateRule) Validate(ctx *validator.ValidationContext) (*validator.RuleResult, error) {
-	var failures []string
+	result := &validator.RuleResult{
+		RuleName: r.Name(),
+		Status:   "PASS",
+	}
 
-	err := filepath.Walk(ctx.ProjectRoot, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	dirs, err := dirsToCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		if info.IsDir() {
-			// Skip logic same as above
-			if strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" {
-				if info.Name() != "." {
-					return filepath.SkipDir
-				}
-			}
 
-			contextPath := filepath.Join(path, "AGENTS.md")
-			contextInfo, err := os.Stat(contextPath)
-			if err == nil {
-				// Check source files vs context mod time
-				entries, _ := os.ReadDir(path)
-				for _, e := range entries {
-					if !e.IsDir() && e.Name() != "AGENTS.md" {
-						ext := filepath.Ext(e.Name())
-						if ext == ".go" || ext == ".ts" || ext == ".js" {
-							fileInfo, _ := e.Info()
-							if fileInfo.ModTime().After(contextInfo.ModTime()) {
-								failures = append(failures, fmt.Sprintf("Stale context in %s (newer file: %s)", path, e.Name()))
-								break // Report once per dir
-							}
-						}
-					}
-				}
+		entries := ctx.FS().Entries(dir)
+		contextInfo := entryInfo(entries, agentsFile)
+		if contextInfo == nil {
+			continue
+		}
+
+		// Check source files vs context mod time
+		for _, e := range entries {
+			ext := filepath.Ext(e.Name())
+			if ext != ".go" && ext != ".ts" && ext != ".js" {
+				continue
+			}
+			fileInfo, err := e.Info()
+			if err == nil && fileInfo.ModTime().After(contextInfo.ModTime()) {
+				result.AddFinding(validator.Finding{
+					Path:        dir,
+					Message:     fmt.Sprintf("Stale context (newer file: %s)", e.Name()),
+					Remediation: fmt.Sprintf("Run `agentic-agent context update %s`", dir),
+				})
+				break // Report once per dir
 			}
 		}
-		return nil
-	})
+	}
 
-	if err != nil {
-		return nil, err
+	if len(result.Errors) > 0 {
+		result.Status = "FAIL"
 	}
+	return result, nil
+}
+
+// Fix regenerates the facts of each stale context file and merges them in,
+// keeping what was written by hand, as context update does.
+func (r *ContextUpdateRule) Fix(ctx *validator.ValidationContext, result *validator.RuleResult) ([]validator.FileChange, error) {
+	var changes []validator.FileChange
+	for _, f := range result.Findings {
+		if f.Path == "" {
+			continue
+		}
+		dir := filepath.FromSlash(f.Path)
+		path := filepath.Join(dir, agentsFile)
+		data, err := os.ReadFile(filepath.Join(ctx.ProjectRoot, path))
+		if err != nil {
+			return nil, err
+		}
+		generated, err := appcontext.GenerateContextWithConfig(filepath.Join(ctx.ProjectRoot, dir), ctx.Config)
+		if err != nil {
+			return nil, fmt.Errorf("generating context for %s: %w", f.Path, err)
+		}
+		generated.Path = f.Path
 
-	status := "PASS"
-	if len(failures) > 0 {
-		status = "FAIL"
+		doc := appcontext.ParseContextDocument(data)
+		doc.SetContext(appcontext.MergeContext(doc.Context(f.Path), generated))
+		changes = append(changes, validator.FileChange{
+			Path:        path,
+			Content:     doc.Bytes(),
+			Description: "regenerate context",
+		})
 	}
+	return changes, nil
+}
 
-	return &validator.RuleResult{
-		RuleName: r.Name(),
-		Status:   status,
-		Errors:   failures,
-	}, nil
+func entryInfo(entries []fs.DirEntry, name string) fs.FileInfo {
+	for _, e := range entries {
+		if e.Name() == name {
+			if info, err := e.Info(); err == nil {
+				return info
+			}
+		}
+	}
+	return nil
 }
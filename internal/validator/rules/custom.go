@@ -0,0 +1,501 @@
+package rules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	appcontext "github.com/javierbenavides/agentic-agent/internal/context"
+	"github.com/javierbenavides/agentic-agent/internal/validator"
+	"gopkg.in/yaml.v3"
+)
+
+// CustomRulesDir holds the project's own rules, declared in YAML files
+// relative to the project root.
+const CustomRulesDir = ".agentic/rules"
+
+// CustomRuleSpec is a rule declared in a YAML file under CustomRulesDir. A
+// file may hold several rules as separate YAML documents:
+//
+//	name: no-sql-in-handlers
+//	description: Handlers go through the repository layer
+//	severity: warn
+//	files: ["internal/api/**/*.go"]
+//	exclude: ["*_test.go"]
+//	checks:
+//	  - forbid_pattern: 'sql\.Open'
+//	  - forbid_import: {from: internal/api, to: internal/db}
+//	  - max_lines: 400
+//	  - require_companion: "{dir}/{base}_test.go"
+//	  - command: go vet ./internal/api/...
+//
+// Globs match paths relative to the root with forward slashes; "**"
+// matches any number of directories, and a glob without a slash matches
+// the file name alone. No files means every file.
+type CustomRuleSpec struct {
+	Name        string        `yaml:"name"`
+	Description string        `yaml:"description,omitempty"`
+	Severity    string        `yaml:"severity,omitempty"`
+	Files       []string      `yaml:"files,omitempty"`
+	Exclude     []string      `yaml:"exclude,omitempty"`
+	Checks      []CustomCheck `yaml:"checks"`
+}
+
+// CustomCheck is one check of a custom rule. Exactly one of the check
+// fields is set; Message and Remediation replace the defaults reported for
+// its findings.
+type CustomCheck struct {
+	ForbidPattern    string      `yaml:"forbid_pattern,omitempty"`
+	RequirePattern   string      `yaml:"require_pattern,omitempty"`
+	ForbidImport     *ImportEdge `yaml:"forbid_import,omitempty"`
+	MaxLines         int         `yaml:"max_lines,omitempty"`
+	RequireCompanion string      `yaml:"require_companion,omitempty"`
+	Command          string      `yaml:"command,omitempty"`
+	ExpectExit       *int        `yaml:"expect_exit,omitempty"` // for Command; default 0
+
+	Message     string `yaml:"message,omitempty"`
+	Remediation string `yaml:"remediation,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// ImportEdge forbids code under From importing code under To; both are
+// directories relative to the project root.
+type ImportEdge struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+var customRuleName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// companionPlaceholders are expanded in require_companion for each file.
+var companionPlaceholders = []string{"{dir}", "{base}", "{ext}", "{name}"}
+
+// CustomRule runs the checks of a CustomRuleSpec.
+type CustomRule struct {
+	spec CustomRuleSpec
+	file string // the YAML file it was declared in, relative to the root
+}
+
+// ProjectRegistry returns DefaultRegistry with the custom rules declared
+// under root/CustomRulesDir added as project rules.
+func ProjectRegistry(root string) (*validator.Registry, error) {
+	r := DefaultRegistry()
+	custom, err := LoadCustomRules(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range custom {
+		if _, ok := r.Get(rule.Name()); ok {
+			return nil, fmt.Errorf("%s: rule %q is already defined", rule.file, rule.Name())
+		}
+		severity, _ := validator.ParseSeverity(rule.spec.Severity)
+		r.RegisterProjectRule(rule, severity)
+	}
+	return r, nil
+}
+
+// LoadCustomRules parses every *.yaml and *.yml file in root/CustomRulesDir,
+// in name order. A missing directory means no custom rules.
+func LoadCustomRules(root string) ([]*CustomRule, error) {
+	var paths []string
+	for _, ext := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(root, CustomRulesDir, ext))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	var loaded []*CustomRule
+	for _, path := range paths {
+		rel, _ := filepath.Rel(root, path)
+		rules, err := parseCustomRules(path, filepath.ToSlash(rel))
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, rules...)
+	}
+	return loaded, nil
+}
+
+func parseCustomRules(path, rel string) ([]*CustomRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*CustomRule
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	for {
+		var spec CustomRuleSpec
+		if err := dec.Decode(&spec); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		if err := spec.compile(); err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		rules = append(rules, &CustomRule{spec: spec, file: rel})
+	}
+	return rules, nil
+}
+
+func (s *CustomRuleSpec) compile() error {
+	if !customRuleName.MatchString(s.Name) {
+		return fmt.Errorf("rule name %q must be lowercase letters, digits, '.', '_' or '-'", s.Name)
+	}
+	if _, err := validator.ParseSeverity(s.Severity); err != nil {
+		return fmt.Errorf("rule %s: %w", s.Name, err)
+	}
+	for _, glob := range append(append([]string{}, s.Files...), s.Exclude...) {
+		if _, err := filepath.Match(strings.ReplaceAll(glob, "**", "*"), ""); err != nil {
+			return fmt.Errorf("rule %s: invalid glob %q: %w", s.Name, glob, err)
+		}
+	}
+	if len(s.Checks) == 0 {
+		return fmt.Errorf("rule %s has no checks", s.Name)
+	}
+	for i := range s.Checks {
+		if err := s.Checks[i].compile(); err != nil {
+			return fmt.Errorf("rule %s, check %d: %w", s.Name, i+1, err)
+		}
+	}
+	return nil
+}
+
+func (c *CustomCheck) compile() error {
+	kinds := 0
+	for _, set := range []bool{
+		c.ForbidPattern != "", c.RequirePattern != "", c.ForbidImport != nil,
+		c.MaxLines != 0, c.RequireCompanion != "", c.Command != "",
+	} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return errors.New("set exactly one of forbid_pattern, require_pattern, forbid_import, max_lines, require_companion or command")
+	}
+	if c.ExpectExit != nil && c.Command == "" {
+		return errors.New("expect_exit needs a command")
+	}
+
+	var err error
+	switch {
+	case c.ForbidPattern != "":
+		c.pattern, err = regexp.Compile(c.ForbidPattern)
+	case c.RequirePattern != "":
+		c.pattern, err = regexp.Compile("(?m)" + c.RequirePattern)
+	case c.ForbidImport != nil:
+		if c.ForbidImport.From == "" || c.ForbidImport.To == "" {
+			err = errors.New("forbid_import needs from and to directories")
+		}
+	case c.MaxLines < 0:
+		err = errors.New("max_lines must be positive")
+	}
+	return err
+}
+
+func (r *CustomRule) Name() string {
+	return r.spec.Name
+}
+
+// Validate applies the file checks to every matching file, or to the
+// matching changed files of a scoped run, and runs the commands once. A
+// scoped run that changes no matching file skips the commands too.
+func (r *CustomRule) Validate(ctx *validator.ValidationContext) (*validator.RuleResult, error) {
+	result := &validator.RuleResult{
+		RuleName: r.Name(),
+		Status:   "PASS",
+	}
+
+	files, err := r.files(ctx)
+	if err != nil {
+		return nil, err
+	}
+	module := goModule(ctx.FS())
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r.checkFile(ctx.ProjectRoot, file, module, result)
+	}
+
+	if !ctx.Scoped() || len(files) > 0 {
+		for _, c := range r.spec.Checks {
+			if c.Command == "" {
+				continue
+			}
+			if err := r.runCommand(ctx, c, result); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		result.Status = "FAIL"
+	}
+	return result, nil
+}
+
+// files returns the files the rule applies to, relative to the root.
+func (r *CustomRule) files(ctx *validator.ValidationContext) ([]string, error) {
+	candidates := ctx.ChangedFiles
+	if !ctx.Scoped() {
+		var err error
+		if candidates, err = ctx.FS().Files(); err != nil {
+			return nil, err
+		}
+	}
+
+	var files []string
+	for _, f := range candidates {
+		slash := filepath.ToSlash(f)
+		if len(r.spec.Files) > 0 && !matchAnyGlob(r.spec.Files, slash) {
+			continue
+		}
+		if matchAnyGlob(r.spec.Exclude, slash) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(ctx.ProjectRoot, f)); err != nil || !info.Mode().IsRegular() {
+			continue // deleted in the working tree
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func (r *CustomRule) checkFile(root, file, module string, result *validator.RuleResult) {
+	path := filepath.Join(root, file)
+	slash := filepath.ToSlash(file)
+
+	var lines []string
+	for _, c := range r.spec.Checks {
+		if lines == nil && (c.ForbidPattern != "" || c.RequirePattern != "" || c.MaxLines > 0) {
+			lines = readLines(path)
+		}
+		switch {
+		case c.ForbidPattern != "":
+			for i, line := range lines {
+				if c.pattern.MatchString(line) {
+					r.report(result, c, validator.Finding{
+						Path:        file,
+						Line:        i + 1,
+						Message:     fmt.Sprintf("matches forbidden pattern %q: %s", c.ForbidPattern, strings.TrimSpace(line)),
+						Remediation: fmt.Sprintf("Rewrite the line so it no longer matches %q", c.ForbidPattern),
+					})
+				}
+			}
+		case c.RequirePattern != "":
+			if !c.pattern.MatchString(strings.Join(lines, "\n")) {
+				r.report(result, c, validator.Finding{
+					Path:        file,
+					Message:     fmt.Sprintf("does not match required pattern %q", c.RequirePattern),
+					Remediation: fmt.Sprintf("Add a line matching %q", c.RequirePattern),
+				})
+			}
+		case c.MaxLines > 0:
+			if len(lines) > c.MaxLines {
+				r.report(result, c, validator.Finding{
+					Path:        file,
+					Message:     fmt.Sprintf("has %d lines, more than %d", len(lines), c.MaxLines),
+					Remediation: "Split the file",
+				})
+			}
+		case c.ForbidImport != nil:
+			if !underDir(slash, c.ForbidImport.From) {
+				continue
+			}
+			imports, _ := appcontext.FileImports(path)
+			for _, imp := range imports {
+				if target := importTarget(slash, imp.Path, module); underDir(target, c.ForbidImport.To) {
+					r.report(result, c, validator.Finding{
+						Path:        file,
+						Line:        imp.Line,
+						Message:     fmt.Sprintf("imports %q: %s may not depend on %s", imp.Path, c.ForbidImport.From, c.ForbidImport.To),
+						Remediation: fmt.Sprintf("Remove the import or move the code out of %s", c.ForbidImport.From),
+					})
+				}
+			}
+		case c.RequireCompanion != "":
+			companion := expandCompanion(c.RequireCompanion, slash)
+			if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(companion))); err != nil {
+				r.report(result, c, validator.Finding{
+					Path:        file,
+					Message:     fmt.Sprintf("missing companion file %s", companion),
+					Remediation: fmt.Sprintf("Add %s", companion),
+				})
+			}
+		}
+	}
+}
+
+// runCommand runs a command check through the shell in the project root,
+// under the rule's timeout.
+func (r *CustomRule) runCommand(ctx *validator.ValidationContext, c CustomCheck, result *validator.RuleResult) error {
+	want := 0
+	if c.ExpectExit != nil {
+		want = *c.ExpectExit
+	}
+
+	var cmd *exec.Cmd
+	if ctx.Context != nil {
+		cmd = exec.CommandContext(ctx.Context, "sh", "-c", c.Command)
+	} else {
+		cmd = exec.Command("sh", "-c", c.Command)
+	}
+	cmd.Dir = ctx.ProjectRoot
+	output, err := cmd.CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	code := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		return fmt.Errorf("running %q: %w", c.Command, err)
+	}
+	if code == want {
+		return nil
+	}
+
+	message := fmt.Sprintf("command %q exited with %d, expected %d", c.Command, code, want)
+	if tail := lastLines(string(output), 5); tail != "" {
+		message += ":\n" + tail
+	}
+	r.report(result, c, validator.Finding{
+		Path:        r.file,
+		Message:     message,
+		Remediation: fmt.Sprintf("Run `%s` and fix what it reports", c.Command),
+	})
+	return nil
+}
+
+// report adds f, with the check's own message and remediation if it has
+// them.
+func (r *CustomRule) report(result *validator.RuleResult, c CustomCheck, f validator.Finding) {
+	if c.Message != "" {
+		f.Message = c.Message
+	}
+	if c.Remediation != "" {
+		f.Remediation = c.Remediation
+	} else if r.spec.Description != "" {
+		f.Remediation = r.spec.Description + ". " + f.Remediation
+	}
+	result.AddFinding(f)
+}
+
+// importTarget returns the project path an import refers to, with forward
+// slashes: relative imports are resolved against the importing file, Go
+// imports inside the module lose the module prefix and Python modules
+// become paths. Other imports are returned as written.
+func importTarget(file, imp, module string) string {
+	switch {
+	case strings.HasPrefix(imp, "./") || strings.HasPrefix(imp, "../"):
+		return resolveRelativeImport(file, imp)
+	case module != "" && (imp == module || strings.HasPrefix(imp, module+"/")):
+		return strings.TrimPrefix(strings.TrimPrefix(imp, module), "/")
+	case strings.HasSuffix(file, ".py") && !strings.HasPrefix(imp, "."):
+		return strings.ReplaceAll(imp, ".", "/")
+	}
+	return imp
+}
+
+var modulePattern = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// goModule returns the module path declared in the root go.mod, if any.
+func goModule(snap *validator.Snapshot) string {
+	data, err := snap.ReadFile("go.mod")
+	if err != nil {
+		return ""
+	}
+	if m := modulePattern.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// underDir reports whether the slash path p is dir or lies below it.
+func underDir(p, dir string) bool {
+	dir = strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/")
+	return dir == "." || p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+// expandCompanion fills the placeholders of a require_companion template
+// for file: {dir} is its directory, {name} its file name, {base} the name
+// without extension and {ext} the extension with its dot.
+func expandCompanion(template, file string) string {
+	dir, name := filepath.ToSlash(filepath.Dir(file)), filepath.Base(file)
+	ext := filepath.Ext(name)
+	values := map[string]string{
+		"{dir}":  dir,
+		"{name}": name,
+		"{base}": strings.TrimSuffix(name, ext),
+		"{ext}":  ext,
+	}
+	out := template
+	for _, p := range companionPlaceholders {
+		out = strings.ReplaceAll(out, p, values[p])
+	}
+	return strings.TrimPrefix(out, "./")
+}
+
+func matchAnyGlob(globs []string, p string) bool {
+	for _, g := range globs {
+		if matchGlob(g, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash path against a glob where "**" stands for any
+// number of directories. A glob without a slash matches the base name.
+func matchGlob(glob, p string) bool {
+	if !strings.Contains(glob, "/") {
+		ok, _ := filepath.Match(glob, filepath.Base(p))
+		return ok
+	}
+	return matchSegments(strings.Split(glob, "/"), strings.Split(p, "/"))
+}
+
+func matchSegments(glob, parts []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(glob[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(glob[0], parts[0]); !ok {
+			return false
+		}
+		glob, parts = glob[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
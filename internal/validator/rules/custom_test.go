@@ -0,0 +1,119 @@
+package rules
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/internal/validator"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomRules_Checks(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "go.mod", "module example.com/shop\n\ngo 1.23\n")
+	writeProjectFile(t, tmpDir, ".agentic/rules/api.yaml", `name: api-layering
+description: Handlers go through the service layer
+files: ["internal/api/**/*.go"]
+exclude: ["*_test.go"]
+checks:
+  - forbid_pattern: 'sql\.Open'
+  - forbid_import: {from: internal/api, to: internal/db}
+  - require_pattern: '^// Package'
+    message: missing package comment
+  - max_lines: 5
+  - require_companion: "{dir}/{base}_test.go"
+---
+name: tidy
+checks:
+  - command: "echo checking; exit 3"
+    expect_exit: 0
+`)
+	writeProjectFile(t, tmpDir, "internal/api/handler.go", "// Package api serves HTTP.\npackage api\n\nimport \"example.com/shop/internal/db\"\n\nvar _ = sql.Open\n")
+	writeProjectFile(t, tmpDir, "internal/api/v1/routes.go", "package v1\n")
+	writeProjectFile(t, tmpDir, "internal/api/v1/routes_test.go", "package v1\n")
+	writeProjectFile(t, tmpDir, "internal/db/db.go", "package db\n")
+
+	custom, err := LoadCustomRules(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, custom, 2)
+
+	ctx := &validator.ValidationContext{ProjectRoot: tmpDir}
+	result, err := custom[0].Validate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "FAIL", result.Status)
+	handler, routes := filepath.Join("internal", "api", "handler.go"), filepath.Join("internal", "api", "v1", "routes.go")
+	assert.Equal(t, []string{
+		handler + `:6: matches forbidden pattern "sql\\.Open": var _ = sql.Open`,
+		handler + `:4: imports "example.com/shop/internal/db": internal/api may not depend on internal/db`,
+		handler + ": has 6 lines, more than 5",
+		handler + ": missing companion file internal/api/handler_test.go",
+		routes + ": missing package comment",
+	}, result.Errors)
+	assert.Equal(t, "Handlers go through the service layer. Add internal/api/handler_test.go", result.Findings[3].Remediation)
+
+	result, err = custom[1].Validate(&validator.ValidationContext{ProjectRoot: tmpDir})
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, ".agentic/rules/api.yaml: command \"echo checking; exit 3\" exited with 3, expected 0:\nchecking", result.Errors[0])
+
+	result, err = custom[0].Validate(&validator.ValidationContext{ProjectRoot: tmpDir, ChangedFiles: []string{routes}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{routes + ": missing package comment"}, result.Errors)
+}
+
+func TestCustomRules_InvalidSpecs(t *testing.T) {
+	for name, spec := range map[string]string{
+		"bad name":       "name: Bad Name\nchecks: [{max_lines: 3}]\n",
+		"no checks":      "name: empty\n",
+		"two kinds":      "name: two\nchecks: [{max_lines: 3, forbid_pattern: x}]\n",
+		"bad regex":      "name: regex\nchecks: [{forbid_pattern: '('}]\n",
+		"unknown key":    "name: typo\nchecks: [{max_line: 3}]\n",
+		"bad severity":   "name: sev\nseverity: loud\nchecks: [{max_lines: 3}]\n",
+		"half edge":      "name: edge\nchecks: [{forbid_import: {from: a}}]\n",
+		"stray exit":     "name: exit\nchecks: [{max_lines: 3, expect_exit: 1}]\n",
+		"built-in clash": "name: task-size\nchecks: [{max_lines: 3}]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := setupTestProject(t)
+			writeProjectFile(t, tmpDir, ".agentic/rules/rule.yml", spec)
+			_, err := ProjectRegistry(tmpDir)
+			require.Error(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), ".agentic/rules/rule.yml: "), err.Error())
+		})
+	}
+}
+
+func TestProjectRegistry_CustomRules(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, ".agentic/rules/size.yaml", "name: file-size\nseverity: warn\nchecks: [{max_lines: 100}]\n---\nname: parked\nseverity: off\nchecks: [{max_lines: 1}]\n")
+
+	r, err := ProjectRegistry(tmpDir)
+	require.NoError(t, err)
+	assert.Len(t, r.Names(), 13)
+
+	cfg := &models.Config{Workflow: models.WorkflowConfig{Validators: []string{"task-size"}}}
+	v, err := r.Build(cfg)
+	require.NoError(t, err)
+	var names []string
+	for _, rule := range v.Rules() {
+		names = append(names, rule.Name())
+	}
+	assert.Equal(t, []string{"task-size", "file-size"}, names, "project rules run without being listed; off disables them")
+
+	cfg.Workflow.Validation.Rules = map[string]models.RuleConfig{"file-size": {Severity: "off"}, "parked": {Severity: "fail"}}
+	v, err = r.Build(cfg)
+	require.NoError(t, err)
+	assert.Len(t, v.Rules(), 2)
+	assert.Equal(t, "parked", v.Rules()[1].Name())
+}
+
+func TestMatchGlob(t *testing.T) {
+	assert.True(t, matchGlob("internal/**/*.go", "internal/api/v1/routes.go"))
+	assert.True(t, matchGlob("internal/**/*.go", "internal/main.go"))
+	assert.False(t, matchGlob("internal/**/*.go", "cmd/main.go"))
+	assert.True(t, matchGlob("*_test.go", "internal/api/handler_test.go"))
+	assert.True(t, matchGlob("**", "a/b/c"))
+}
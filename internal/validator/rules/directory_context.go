@@ -2,13 +2,16 @@ package rules
 
 import (
 	"fmt"
-	"os"
+	"io/fs"
 	"path/filepath"
-	"strings"
 
+	appcontext "github.com/javierbenavides/agentic-agent/internal/context"
 	"github.com/javierbenavides/agentic-agent/internal/validator"
 )
 
+// agentsFile is the per-directory context file the context rules look for.
+const agentsFile = "AGENTS.md"
+
 type DirectoryContextRule struct{}
 
 func (r *DirectoryContextRule) Name() string {
@@ -16,55 +19,95 @@ func (r *DirectoryContextRule) Name() string {
 }
 
 func (r *DirectoryContextRule) Validate(ctx *validator.ValidationContext) (*validator.RuleResult, error) {
-	var failures []string
+	result := &validator.RuleResult{
+		RuleName: r.Name(),
+		Status:   "PASS",
+	}
 
-	err := filepath.Walk(ctx.ProjectRoot, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	dirs, err := dirsToCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		if info.IsDir() {
-			// Skip hidden and system dirs
-			if strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" || info.Name() == "node_modules" {
-				if info.Name() != "." { // Don't skip root
-					return filepath.SkipDir
-				}
-			}
 
-			// Check for source files
-			hasSource := false
-			entries, _ := os.ReadDir(path)
-			for _, e := range entries {
-				if !e.IsDir() {
-					ext := filepath.Ext(e.Name())
-					if ext == ".go" || ext == ".ts" || ext == ".js" || ext == ".py" {
-						hasSource = true
-						break
-					}
-				}
+		// Check for source files
+		hasSource := false
+		entries := ctx.FS().Entries(dir)
+		for _, e := range entries {
+			ext := filepath.Ext(e.Name())
+			if ext == ".go" || ext == ".ts" || ext == ".js" || ext == ".py" {
+				hasSource = true
+				break
 			}
+		}
 
-			if hasSource {
-				// Check for context.md
-				if _, err := os.Stat(filepath.Join(path, "AGENTS.md")); os.IsNotExist(err) {
-					failures = append(failures, fmt.Sprintf("Missing context.md in %s", path))
-				}
-			}
+		// Check for context.md
+		if hasSource && !hasEntry(entries, agentsFile) {
+			result.AddFinding(validator.Finding{
+				Path:        dir,
+				Message:     "Missing context.md",
+				Remediation: fmt.Sprintf("Run `agentic-agent context generate %s`", dir),
+			})
 		}
-		return nil
-	})
+	}
 
-	if err != nil {
-		return nil, err
+	if len(result.Errors) > 0 {
+		result.Status = "FAIL"
 	}
+	return result, nil
+}
 
-	status := "PASS"
-	if len(failures) > 0 {
-		status = "FAIL"
+// Fix generates a context file for each directory missing one.
+func (r *DirectoryContextRule) Fix(ctx *validator.ValidationContext, result *validator.RuleResult) ([]validator.FileChange, error) {
+	var changes []validator.FileChange
+	for _, f := range result.Findings {
+		if f.Path == "" {
+			continue
+		}
+		dir := filepath.FromSlash(f.Path)
+		generated, err := appcontext.GenerateContextWithConfig(filepath.Join(ctx.ProjectRoot, dir), ctx.Config)
+		if err != nil {
+			return nil, fmt.Errorf("generating context for %s: %w", f.Path, err)
+		}
+		generated.Path = f.Path
+
+		doc := appcontext.NewContextDocument(f.Path)
+		doc.SetContext(generated)
+		changes = append(changes, validator.FileChange{
+			Path:        filepath.Join(dir, agentsFile),
+			Content:     doc.Bytes(),
+			Description: "generate context",
+		})
 	}
+	return changes, nil
+}
 
-	return &validator.RuleResult{
-		RuleName: r.Name(),
-		Status:   status,
-		Errors:   failures,
-	}, nil
+// dirsToCheck returns the project directories a context rule looks at,
+// relative to the root: all of them, or in a scoped run only those holding
+// changed files.
+func dirsToCheck(ctx *validator.ValidationContext) ([]string, error) {
+	dirs, err := ctx.FS().Dirs()
+	if err != nil || !ctx.Scoped() {
+		return dirs, err
+	}
+	changed := ctx.ChangedDirs()
+	var scoped []string
+	for _, dir := range dirs {
+		if changed[dir] {
+			scoped = append(scoped, dir)
+		}
+	}
+	return scoped, nil
+}
+
+func hasEntry(entries []fs.DirEntry, name string) bool {
+	for _, e := range entries {
+		if e.Name() == name {
+			return true
+		}
+	}
+	return false
 }
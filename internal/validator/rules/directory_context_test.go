@@ -234,3 +234,24 @@ func TestDirectoryContextRule_ExcludedDirectories(t *testing.T) {
 	assert.Equal(t, "PASS", result.Status)
 	assert.Empty(t, result.Errors)
 }
+
+func TestDirectoryContextRule_ScopedToChangedFiles(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "src/auth/auth.go", "package auth")
+	writeProjectFile(t, tmpDir, "src/core/core.go", "package core")
+
+	rule := &DirectoryContextRule{}
+	ctx := &validator.ValidationContext{
+		ProjectRoot:  tmpDir,
+		ChangedFiles: []string{filepath.Join("src", "core", "core.go")},
+	}
+
+	result, err := rule.Validate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("src", "core") + ": Missing context.md"}, result.Errors)
+
+	ctx.ChangedFiles = []string{}
+	result, err = rule.Validate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "PASS", result.Status, "an empty change set checks nothing")
+}
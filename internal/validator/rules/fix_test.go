@@ -0,0 +1,87 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/internal/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixProject(t *testing.T, rule validator.ValidationRule, root string, dryRun bool) ([]*validator.RuleResult, []*validator.FixResult) {
+	t.Helper()
+	v := validator.NewValidator()
+	v.Register(rule)
+	ctx := &validator.ValidationContext{ProjectRoot: root}
+	results, err := v.Validate(ctx)
+	require.NoError(t, err)
+	return results, v.Fix(ctx, results, dryRun)
+}
+
+func TestDirectoryContextRule_Fix(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "src/auth/auth.go", "package auth\n\nfunc Login() {}\n")
+
+	results, fixes := fixProject(t, &DirectoryContextRule{}, tmpDir, true)
+	assert.Equal(t, "FAIL", results[0].Status)
+	require.Len(t, fixes, 1)
+	require.NoError(t, fixes[0].Err)
+	assert.Contains(t, fixes[0].Diff, "--- /dev/null\n+++ b/src/auth/AGENTS.md\n")
+	assert.Contains(t, fixes[0].Diff, "+# Context for src/auth\n")
+	assert.Nil(t, fixes[0].After, "a dry run does not recheck")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "src", "auth", agentsFile))
+
+	_, fixes = fixProject(t, &DirectoryContextRule{}, tmpDir, false)
+	require.Len(t, fixes, 1)
+	assert.True(t, fixes[0].Fixed())
+	data, err := os.ReadFile(filepath.Join(tmpDir, "src", "auth", agentsFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Login")
+}
+
+func TestContextUpdateRule_Fix(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, "src/AGENTS.md", "# Context for src\n\n## Purpose\nHand-written purpose.\n\n## Cannot Do\n- Import database/sql\n")
+	writeProjectFile(t, tmpDir, "src/main.go", "package main\n\nfunc Serve() {}\n")
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(tmpDir, "src", agentsFile), old, old))
+
+	results, fixes := fixProject(t, &ContextUpdateRule{}, tmpDir, false)
+	assert.Equal(t, "FAIL", results[0].Status)
+	require.Len(t, fixes, 1)
+	require.NoError(t, fixes[0].Err)
+	assert.Contains(t, fixes[0].Diff, "--- a/src/AGENTS.md\n+++ b/src/AGENTS.md\n")
+	assert.True(t, fixes[0].Fixed())
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "src", agentsFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Hand-written purpose.")
+	assert.Contains(t, string(data), "- Import database/sql")
+	assert.Contains(t, string(data), "Serve")
+}
+
+func TestSpecMetadataRule_Fix(t *testing.T) {
+	tmpDir := setupTestProject(t)
+	writeProjectFile(t, tmpDir, ".agentic/openspec/changes/add-login/proposal.md", "# Add login\n")
+	writeProjectFile(t, tmpDir, ".agentic/openspec/changes/add-logout/metadata.yaml", "implements: auth\nstatus: Draft")
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	results, fixes := fixProject(t, &SpecMetadataRule{}, tmpDir, false)
+	assert.Equal(t, "FAIL", results[0].Status)
+	assert.Len(t, results[0].Findings, 3)
+	require.Len(t, fixes, 1)
+	require.NoError(t, fixes[0].Err)
+	require.Len(t, fixes[0].Changes, 2)
+	assert.True(t, fixes[0].Fixed())
+
+	data, err := os.ReadFile(".agentic/openspec/changes/add-logout/metadata.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "implements: auth\nstatus: Draft\ncontext_pack: \"\"\nblocked_by: []\n", string(data))
+	assert.FileExists(t, ".agentic/openspec/changes/add-login/metadata.yaml")
+}
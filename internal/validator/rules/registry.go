@@ -0,0 +1,24 @@
+package rules
+
+import "github.com/javierbenavides/agentic-agent/internal/validator"
+
+// DefaultRegistry returns a registry of every built-in rule, in the order
+// validate runs them. "context-check", the name used by older configs,
+// enables the three context rules.
+func DefaultRegistry() *validator.Registry {
+	r := validator.NewRegistry()
+	r.Register(&DirectoryContextRule{})
+	r.Register(&ContextUpdateRule{})
+	r.Register(&TaskScopeRule{})
+	r.Register(&TaskSizeRule{})
+	r.Register(&BrowserVerificationRule{})
+	r.Register(&SpecMetadataRule{})
+	r.Register(&SpecGraphRule{})
+	r.Register(&ADRBlockingRule{})
+	r.Register(&VerifyMdRule{})
+	r.Register(&SkillTierRule{})
+	r.Register(&ContextConstraintsRule{})
+
+	r.Alias("context-check", "context-required", "context-freshness", "context-constraints")
+	return r
+}
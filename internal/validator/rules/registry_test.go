@@ -0,0 +1,37 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	r := DefaultRegistry()
+	assert.Len(t, r.Names(), 11)
+
+	cfg := &models.Config{Workflow: models.WorkflowConfig{
+		Validators: []string{"context-check", "task-size"},
+		Validation: models.ValidationConfig{Rules: map[string]models.RuleConfig{
+			"task-size": {Options: map[string]interface{}{"max_files": 8, "max_directories": 3}},
+		}},
+	}}
+	v, err := r.Build(cfg)
+	require.NoError(t, err)
+
+	var names []string
+	for _, rule := range v.Rules() {
+		names = append(names, rule.Name())
+	}
+	assert.Equal(t, []string{"context-required", "context-freshness", "task-size", "context-constraints"}, names)
+
+	size, _ := r.Get("task-size")
+	assert.Equal(t, &TaskSizeRule{MaxFiles: 8, MaxDirectories: 3}, size)
+}
+
+func TestTaskSizeRule_RejectsNegativeLimits(t *testing.T) {
+	err := (&TaskSizeRule{}).Configure(map[string]interface{}{"max_files": -1})
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/javierbenavides/agentic-agent/internal/validator"
 	"gopkg.in/yaml.v3"
@@ -11,6 +12,17 @@ import (
 
 type SpecMetadataRule struct{}
 
+// requiredMetadataFields are the fields every change's metadata.yaml must
+// have, with the value --fix fills in for each.
+var requiredMetadataFields = []string{"implements", "context_pack", "blocked_by", "status"}
+
+var metadataDefaults = map[string]string{
+	"implements":   `""`,
+	"context_pack": `""`,
+	"blocked_by":   "[]",
+	"status":       "Draft",
+}
+
 func (r *SpecMetadataRule) Name() string {
 	return "sdd-metadata"
 }
@@ -42,14 +54,20 @@ func (r *SpecMetadataRule) Validate(ctx *validator.ValidationContext) (*validato
 		}
 
 		changeID := entry.Name()
+		if !ctx.Touches(filepath.Join(changesDir, changeID)) {
+			continue // unchanged in a scoped run
+		}
 		metadataPath := filepath.Join(changesDir, changeID, "metadata.yaml")
 
 		data, err := os.ReadFile(metadataPath)
 		if err != nil {
 			if os.IsNotExist(err) {
 				result.Status = "FAIL"
-				result.Errors = append(result.Errors,
-					fmt.Sprintf("Change %s missing metadata.yaml", changeID))
+				result.AddFinding(validator.Finding{
+					Path:        metadataPath,
+					Message:     fmt.Sprintf("Change %s missing metadata.yaml", changeID),
+					Remediation: "Run `agentic-agent validate --fix` to scaffold it",
+				})
 				continue
 			}
 			return nil, fmt.Errorf("failed to read metadata for %s: %w", changeID, err)
@@ -58,18 +76,22 @@ func (r *SpecMetadataRule) Validate(ctx *validator.ValidationContext) (*validato
 		var metadata map[string]interface{}
 		if err := yaml.Unmarshal(data, &metadata); err != nil {
 			result.Status = "FAIL"
-			result.Errors = append(result.Errors,
-				fmt.Sprintf("Change %s has invalid YAML metadata: %v", changeID, err))
+			result.AddFinding(validator.Finding{
+				Path:    metadataPath,
+				Message: fmt.Sprintf("Change %s has invalid YAML metadata: %v", changeID, err),
+			})
 			continue
 		}
 
 		// Check required fields
-		requiredFields := []string{"implements", "context_pack", "blocked_by", "status"}
-		for _, field := range requiredFields {
+		for _, field := range requiredMetadataFields {
 			if _, ok := metadata[field]; !ok {
 				result.Status = "FAIL"
-				result.Errors = append(result.Errors,
-					fmt.Sprintf("Change %s missing metadata field: %s", changeID, field))
+				result.AddFinding(validator.Finding{
+					Path:        metadataPath,
+					Message:     fmt.Sprintf("Change %s missing metadata field: %s", changeID, field),
+					Remediation: "Run `agentic-agent validate --fix` to add it",
+				})
 			}
 		}
 
@@ -86,11 +108,70 @@ func (r *SpecMetadataRule) Validate(ctx *validator.ValidationContext) (*validato
 			}
 			if !found && status != "" {
 				result.Status = "FAIL"
-				result.Errors = append(result.Errors,
-					fmt.Sprintf("Change %s has invalid status: %s", changeID, status))
+				result.AddFinding(validator.Finding{
+					Path:    metadataPath,
+					Message: fmt.Sprintf("Change %s has invalid status: %s", changeID, status),
+				})
 			}
 		}
 	}
 
 	return result, nil
 }
+
+// Fix scaffolds a missing metadata.yaml and appends missing required
+// fields to an existing one. Invalid YAML and statuses are left for a
+// person to correct.
+func (r *SpecMetadataRule) Fix(ctx *validator.ValidationContext, result *validator.RuleResult) ([]validator.FileChange, error) {
+	var changes []validator.FileChange
+	seen := make(map[string]bool)
+	for _, f := range result.Findings {
+		if f.Path == "" || seen[f.Path] {
+			continue
+		}
+		seen[f.Path] = true
+		path := filepath.FromSlash(f.Path)
+
+		data, err := os.ReadFile(filepath.Join(ctx.ProjectRoot, path))
+		if os.IsNotExist(err) {
+			content := "# Scaffolded by agentic-agent validate --fix; fill in implements and context_pack.\n"
+			for _, field := range requiredMetadataFields {
+				content += field + ": " + metadataDefaults[field] + "\n"
+			}
+			changes = append(changes, validator.FileChange{
+				Path:        path,
+				Content:     []byte(content),
+				Description: "scaffold metadata",
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var metadata map[string]interface{}
+		if err := yaml.Unmarshal(data, &metadata); err != nil {
+			continue
+		}
+		content := string(data)
+		added := false
+		for _, field := range requiredMetadataFields {
+			if _, ok := metadata[field]; ok {
+				continue
+			}
+			if content != "" && !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			content += field + ": " + metadataDefaults[field] + "\n"
+			added = true
+		}
+		if added {
+			changes = append(changes, validator.FileChange{
+				Path:        path,
+				Content:     []byte(content),
+				Description: "add missing metadata fields",
+			})
+		}
+	}
+	return changes, nil
+}
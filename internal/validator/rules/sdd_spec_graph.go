@@ -59,6 +59,9 @@ func (r *SpecGraphRule) Validate(ctx *validator.ValidationContext) (*validator.R
 		}
 
 		changeID := entry.Name()
+		if !ctx.Touches(filepath.Join(changesDir, changeID)) {
+			continue // unchanged in a scoped run
+		}
 		metadataPath := filepath.Join(changesDir, changeID, "metadata.yaml")
 
 		// Read status from metadata
@@ -41,6 +41,9 @@ func (r *VerifyMdRule) Validate(ctx *validator.ValidationContext) (*validator.Ru
 		}
 
 		changeID := entry.Name()
+		if !ctx.Touches(filepath.Join(changesDir, changeID)) {
+			continue // unchanged in a scoped run
+		}
 		metadataPath := filepath.Join(changesDir, changeID, "metadata.yaml")
 
 		data, err := os.ReadFile(metadataPath)
@@ -29,13 +29,17 @@ var requiredSkillSections = []string{
 // Tier 2: Skill files (SKILL.md) stay focused (54-130 lines)
 // Tier 3: Resources (resources/*.md) hold detail on-demand (150-500 lines)
 type SkillTierRule struct {
-	PacksDir string // overridable for tests; defaults to "internal/skills/packs"
+	PacksDir string `yaml:"packs_dir"` // defaults to "internal/skills/packs"
 }
 
 func (r *SkillTierRule) Name() string {
 	return "skill-tier"
 }
 
+func (r *SkillTierRule) Configure(options map[string]interface{}) error {
+	return validator.DecodeOptions(options, r)
+}
+
 func (r *SkillTierRule) Validate(ctx *validator.ValidationContext) (*validator.RuleResult, error) {
 	result := &validator.RuleResult{
 		RuleName: r.Name(),
@@ -56,7 +60,9 @@ func (r *SkillTierRule) Validate(ctx *validator.ValidationContext) (*validator.R
 
 	// Check root router once
 	rootRouter := filepath.Join(packsDir, "SKILLS.md")
-	checkRouter(rootRouter, result)
+	if ctx.Touches(rootRouter) {
+		checkRouter(rootRouter, result)
+	}
 
 	// Walk through packs directory
 	entries, err := os.ReadDir(packsDir)
@@ -70,6 +76,9 @@ func (r *SkillTierRule) Validate(ctx *validator.ValidationContext) (*validator.R
 		}
 
 		packDir := filepath.Join(packsDir, entry.Name())
+		if !ctx.Touches(packDir) {
+			continue // unchanged in a scoped run
+		}
 
 		// Check top-level SKILL.md
 		skillPath := filepath.Join(packDir, "SKILL.md")
@@ -132,56 +141,56 @@ func (r *SkillTierRule) Validate(ctx *validator.ValidationContext) (*validator.R
 func checkRouter(path string, result *validator.RuleResult) {
 	lines, err := countLines(path)
 	if err != nil {
-		addFail(result, fmt.Sprintf("Failed to read router %s: %v", relPath(path), err))
+		addFail(result, path, fmt.Sprintf("Failed to read router %s: %v", relPath(path), err))
 		return
 	}
 
 	if lines > RouterFailLines {
-		addFail(result, fmt.Sprintf("Router %s: %d lines (fail threshold: %d)", relPath(path), lines, RouterFailLines))
+		addFail(result, path, fmt.Sprintf("Router %s: %d lines (fail threshold: %d)", relPath(path), lines, RouterFailLines))
 	} else if lines > RouterWarnLines {
-		addWarn(result, fmt.Sprintf("Router %s: %d lines (warn threshold: %d)", relPath(path), lines, RouterWarnLines))
+		addWarn(result, path, fmt.Sprintf("Router %s: %d lines (warn threshold: %d)", relPath(path), lines, RouterWarnLines))
 	}
 }
 
 func checkSkillFile(path string, result *validator.RuleResult) {
 	lines, err := countLines(path)
 	if err != nil {
-		addFail(result, fmt.Sprintf("Failed to read skill %s: %v", relPath(path), err))
+		addFail(result, path, fmt.Sprintf("Failed to read skill %s: %v", relPath(path), err))
 		return
 	}
 
 	if lines > SkillFailLines {
-		addFail(result, fmt.Sprintf("Skill %s: %d lines (fail threshold: %d)", relPath(path), lines, SkillFailLines))
+		addFail(result, path, fmt.Sprintf("Skill %s: %d lines (fail threshold: %d)", relPath(path), lines, SkillFailLines))
 	} else if lines > SkillWarnLines {
-		addWarn(result, fmt.Sprintf("Skill %s: %d lines (warn threshold: %d)", relPath(path), lines, SkillWarnLines))
+		addWarn(result, path, fmt.Sprintf("Skill %s: %d lines (warn threshold: %d)", relPath(path), lines, SkillWarnLines))
 	}
 
 	// Check required sections
 	missing, err := hasSections(path, requiredSkillSections)
 	if err != nil {
-		addFail(result, fmt.Sprintf("Failed to check sections in %s: %v", relPath(path), err))
+		addFail(result, path, fmt.Sprintf("Failed to check sections in %s: %v", relPath(path), err))
 		return
 	}
 
 	if len(missing) > 0 {
-		addFail(result, fmt.Sprintf("Skill %s missing required sections: %v", relPath(path), missing))
+		addFail(result, path, fmt.Sprintf("Skill %s missing required sections: %v", relPath(path), missing))
 	}
 
 	// Check resource links
 	if err := checkResourceLinks(path, result); err != nil {
-		addFail(result, fmt.Sprintf("Failed to check links in %s: %v", relPath(path), err))
+		addFail(result, path, fmt.Sprintf("Failed to check links in %s: %v", relPath(path), err))
 	}
 }
 
 func checkResourceFile(path string, result *validator.RuleResult) {
 	lines, err := countLines(path)
 	if err != nil {
-		addFail(result, fmt.Sprintf("Failed to read resource %s: %v", relPath(path), err))
+		addFail(result, path, fmt.Sprintf("Failed to read resource %s: %v", relPath(path), err))
 		return
 	}
 
 	if lines > ResourceWarnLines {
-		addWarn(result, fmt.Sprintf("Resource %s: %d lines (warn threshold: %d)", relPath(path), lines, ResourceWarnLines))
+		addWarn(result, path, fmt.Sprintf("Resource %s: %d lines (warn threshold: %d)", relPath(path), lines, ResourceWarnLines))
 	}
 }
 
@@ -288,7 +297,7 @@ func checkResourceLinks(skillPath string, result *validator.RuleResult) error {
 
 		// Verify file exists
 		if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-			addFail(result, fmt.Sprintf("Broken link in %s: %s (file not found)", relPath(skillPath), linkPart))
+			addFail(result, skillPath, fmt.Sprintf("Broken link in %s: %s (file not found)", relPath(skillPath), linkPart))
 		}
 	}
 
@@ -303,14 +312,25 @@ func relPath(abs string) string {
 	return abs
 }
 
-func addFail(result *validator.RuleResult, msg string) {
+// addFail and addWarn record a problem with the file at path. The messages
+// already name the file, so they go into Errors unchanged.
+func addFail(result *validator.RuleResult, path, msg string) {
 	result.Status = "FAIL"
-	result.Errors = append(result.Errors, msg)
+	addFinding(result, path, validator.LevelError, msg)
 }
 
-func addWarn(result *validator.RuleResult, msg string) {
+func addWarn(result *validator.RuleResult, path, msg string) {
 	if result.Status == "PASS" {
 		result.Status = "WARN"
 	}
+	addFinding(result, path, validator.LevelWarning, msg)
+}
+
+func addFinding(result *validator.RuleResult, path, severity, msg string) {
 	result.Errors = append(result.Errors, msg)
+	result.Findings = append(result.Findings, validator.Finding{
+		Severity: severity,
+		Path:     path,
+		Message:  msg,
+	})
 }
@@ -23,16 +23,21 @@ func (r *TaskScopeRule) Validate(ctx *validator.ValidationContext) (*validator.R
 		Errors:   []string{},
 	}
 
-	// Check if we're in a git repository
-	if !isGitRepo() {
-		// Not in a git repo, skip validation (can't check modified files)
-		return result, nil
-	}
+	// A scoped run already knows which files changed
+	modifiedFiles := ctx.ChangedFiles
+	if !ctx.Scoped() {
+		// Check if we're in a git repository
+		if !isGitRepo() {
+			// Not in a git repo, skip validation (can't check modified files)
+			return result, nil
+		}
 
-	// Get modified files from git
-	modifiedFiles, err := getModifiedFiles()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get modified files: %w", err)
+		// Get modified files from git
+		var err error
+		modifiedFiles, err = getModifiedFiles()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get modified files: %w", err)
+		}
 	}
 
 	if len(modifiedFiles) == 0 {
@@ -10,18 +10,41 @@ import (
 	"github.com/javierbenavides/agentic-agent/internal/validator"
 )
 
+// Default task size limits, overridable with the max_files and
+// max_directories options of the task-size rule.
 const (
 	MaxFilesPerTask       = 5
 	MaxDirectoriesPerTask = 2
 )
 
-type TaskSizeRule struct{}
+type TaskSizeRule struct {
+	MaxFiles       int `yaml:"max_files"`       // 0 means MaxFilesPerTask
+	MaxDirectories int `yaml:"max_directories"` // 0 means MaxDirectoriesPerTask
+}
 
 func (r *TaskSizeRule) Name() string {
 	return "task-size"
 }
 
+func (r *TaskSizeRule) Configure(options map[string]interface{}) error {
+	if err := validator.DecodeOptions(options, r); err != nil {
+		return err
+	}
+	if r.MaxFiles < 0 || r.MaxDirectories < 0 {
+		return fmt.Errorf("max_files and max_directories must not be negative")
+	}
+	return nil
+}
+
 func (r *TaskSizeRule) Validate(ctx *validator.ValidationContext) (*validator.RuleResult, error) {
+	maxFiles, maxDirs := r.MaxFiles, r.MaxDirectories
+	if maxFiles == 0 {
+		maxFiles = MaxFilesPerTask
+	}
+	if maxDirs == 0 {
+		maxDirs = MaxDirectoriesPerTask
+	}
+
 	result := &validator.RuleResult{
 		RuleName: r.Name(),
 		Status:   "PASS",
@@ -69,18 +92,18 @@ func (r *TaskSizeRule) Validate(ctx *validator.ValidationContext) (*validator.Ru
 			}
 
 			if info.IsDir() {
-				// Count all files in directory
-				err := filepath.Walk(cleanPath, func(path string, info os.FileInfo, err error) error {
-					if err != nil {
-						return err
-					}
-					if !info.IsDir() && isSourceFile(path) {
+				// Count all files in directory, from the shared snapshot
+				// when the walk covers it
+				var files []string
+				if ctx.FS().HasDir(cleanPath) {
+					files = ctx.FS().FilesUnder(cleanPath)
+				} else if files, err = walkFiles(cleanPath); err != nil {
+					return nil, fmt.Errorf("failed to walk directory %s: %w", cleanPath, err)
+				}
+				for _, path := range files {
+					if isSourceFile(path) {
 						fileCount++
 					}
-					return nil
-				})
-				if err != nil {
-					return nil, fmt.Errorf("failed to walk directory %s: %w", cleanPath, err)
 				}
 				dirSet[cleanPath] = true
 			} else {
@@ -93,24 +116,40 @@ func (r *TaskSizeRule) Validate(ctx *validator.ValidationContext) (*validator.Ru
 		dirCount := len(dirSet)
 
 		// Check limits
-		if fileCount > MaxFilesPerTask {
+		if fileCount > maxFiles {
 			result.Status = "FAIL"
 			result.Errors = append(result.Errors,
 				fmt.Sprintf("Task %s exceeds file limit: %d files (max %d). Consider decomposing into subtasks.",
-					task.ID, fileCount, MaxFilesPerTask))
+					task.ID, fileCount, maxFiles))
 		}
 
-		if dirCount > MaxDirectoriesPerTask {
+		if dirCount > maxDirs {
 			result.Status = "FAIL"
 			result.Errors = append(result.Errors,
 				fmt.Sprintf("Task %s exceeds directory limit: %d directories (max %d). Consider decomposing into subtasks.",
-					task.ID, dirCount, MaxDirectoriesPerTask))
+					task.ID, dirCount, maxDirs))
 		}
 	}
 
 	return result, nil
 }
 
+// walkFiles lists the files below dir, for scopes the snapshot leaves out
+// such as hidden directories.
+func walkFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
 // isSourceFile checks if a file is a source code file
 func isSourceFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
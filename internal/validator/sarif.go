@@ -0,0 +1,114 @@
+package validator
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "agentic-agent"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSARIF writes results as a SARIF 2.1.0 log with one run, for code
+// scanning viewers. Every rule that ran is listed in the tool driver;
+// each finding becomes a result located on its file and line, relative to
+// the project root (%SRCROOT%).
+func WriteSARIF(w io.Writer, results []*RuleResult) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: toolName, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	ruleIndex := make(map[string]int)
+	addRule := func(id string) int {
+		if i, ok := ruleIndex[id]; ok {
+			return i
+		}
+		ruleIndex[id] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: id}})
+		return ruleIndex[id]
+	}
+
+	for _, res := range results {
+		addRule(res.RuleName)
+		for _, f := range res.Findings {
+			result := sarifResult{
+				RuleID:    f.RuleID,
+				RuleIndex: addRule(f.RuleID),
+				Level:     f.Severity,
+				Message:   sarifMessage{Text: f.Message},
+			}
+			if f.Remediation != "" {
+				result.Message.Text += "\n\nRemediation: " + f.Remediation
+			}
+			if f.Path != "" {
+				loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.Path, URIBaseID: "%SRCROOT%"}}
+				if f.Line > 0 {
+					loc.Region = &sarifRegion{StartLine: f.Line}
+				}
+				result.Locations = []sarifLocation{{PhysicalLocation: loc}}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}})
+}
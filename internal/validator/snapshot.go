@@ -0,0 +1,145 @@
+package validator
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Snapshot is a read-only view of the project shared by the rules of one
+// run: the directory tree is walked once, small files are read once, and
+// git is asked once for the working tree changes. It is safe for
+// concurrent use; the first caller of each part pays for it.
+type Snapshot struct {
+	root string
+
+	walkOnce sync.Once
+	dirs     []string // relative to root, in walk order, "." first
+	dirSet   map[string]bool
+	entries  map[string][]fs.DirEntry // files by directory
+	files    []string                 // every file, relative to root, in walk order
+	walkErr  error
+
+	readMu sync.Mutex
+	reads  map[string]*cachedRead
+
+	gitOnce    sync.Once
+	gitChanges []string
+	gitErr     error
+}
+
+type cachedRead struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// NewSnapshot returns an empty snapshot of the tree at root.
+func NewSnapshot(root string) *Snapshot {
+	return &Snapshot{root: root, reads: make(map[string]*cachedRead)}
+}
+
+// SkipDir reports whether directories with this name are left out of the
+// walk: hidden directories, vendor and node_modules.
+func SkipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules"
+}
+
+func (s *Snapshot) walk() {
+	s.walkOnce.Do(func() {
+		s.entries = make(map[string][]fs.DirEntry)
+		s.dirSet = make(map[string]bool)
+		s.walkErr = filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == s.root {
+					return err
+				}
+				return nil // unreadable; leave it out rather than fail every rule
+			}
+			rel, _ := filepath.Rel(s.root, path)
+			if d.IsDir() {
+				if rel != "." && SkipDir(d.Name()) {
+					return filepath.SkipDir
+				}
+				s.dirs = append(s.dirs, rel)
+				s.dirSet[rel] = true
+				return nil
+			}
+			dir := filepath.Dir(rel)
+			s.entries[dir] = append(s.entries[dir], d)
+			s.files = append(s.files, rel)
+			return nil
+		})
+	})
+}
+
+// Dirs returns every directory of the project, relative to the root, with
+// "." first and parents before their children.
+func (s *Snapshot) Dirs() ([]string, error) {
+	s.walk()
+	return s.dirs, s.walkErr
+}
+
+// HasDir reports whether dir, relative to the root, is part of the walk.
+func (s *Snapshot) HasDir(dir string) bool {
+	s.walk()
+	return s.dirSet[filepath.Clean(dir)]
+}
+
+// Entries returns the files, not subdirectories, directly in dir.
+func (s *Snapshot) Entries(dir string) []fs.DirEntry {
+	s.walk()
+	return s.entries[filepath.Clean(dir)]
+}
+
+// Files returns every file of the project, relative to the root.
+func (s *Snapshot) Files() ([]string, error) {
+	s.walk()
+	return s.files, s.walkErr
+}
+
+// FilesUnder returns the files in dir and below it.
+func (s *Snapshot) FilesUnder(dir string) []string {
+	s.walk()
+	dir = filepath.Clean(dir)
+	if dir == "." {
+		return s.files
+	}
+	var files []string
+	prefix := dir + string(filepath.Separator)
+	for _, f := range s.files {
+		if strings.HasPrefix(f, prefix) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// ReadFile returns the contents of a file relative to the root, reading it
+// at most once per run. Use it for small files several rules look at, such
+// as context.md; read large sources directly.
+func (s *Snapshot) ReadFile(rel string) ([]byte, error) {
+	s.readMu.Lock()
+	r, ok := s.reads[rel]
+	if !ok {
+		r = &cachedRead{}
+		s.reads[rel] = r
+	}
+	s.readMu.Unlock()
+
+	r.once.Do(func() {
+		r.data, r.err = os.ReadFile(filepath.Join(s.root, rel))
+	})
+	return r.data, r.err
+}
+
+// WorkingTreeChanges returns the uncommitted and untracked files, as
+// GitChangedFiles(root, "", false), asking git once per run.
+func (s *Snapshot) WorkingTreeChanges() ([]string, error) {
+	s.gitOnce.Do(func() {
+		s.gitChanges, s.gitErr = GitChangedFiles(s.root, "", false)
+	})
+	return s.gitChanges, s.gitErr
+}
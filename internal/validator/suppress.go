@@ -0,0 +1,230 @@
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SuppressionsRule is the name results about suppressions are reported
+// under: comments without a reason, and suppressions that no longer match
+// a finding.
+const SuppressionsRule = "suppressions"
+
+// suppressionMarker starts a suppression comment. In any comment syntax:
+//
+//	// agentic-agent:ignore context-constraints -- legacy client, see #123
+//	# agentic-agent:ignore-file task-scope,context-constraints -- generated
+//	<!-- agentic-agent:ignore-file skill-tier -- reference table -->
+//
+// ignore covers findings on its own line and the next; ignore-file covers
+// the whole file. The reason after "--" is required. The marker must open
+// its comment, so mentions of it inside other comments are not read.
+const suppressionMarker = "agentic-agent:ignore"
+
+var commentOpeners = []string{"//", "#", "<!--", "/*", "--"}
+
+var suppressionPattern = regexp.MustCompile(`agentic-agent:ignore(-file)?(?:\s+([\w.,-]+))?(?:\s+--\s+(.*?))?\s*(?:-->|\*/)?\s*$`)
+
+// maxSuppressionFileSize skips large files, which are rarely hand-edited
+// sources, when scanning for suppression comments.
+const maxSuppressionFileSize = 1 << 20
+
+type suppression struct {
+	path   string // relative to the project root, forward slashes
+	line   int
+	file   bool // ignore-file
+	rules  []string
+	reason string
+	used   bool
+}
+
+func (s *suppression) covers(f Finding) bool {
+	if f.Path != s.path {
+		return false
+	}
+	if !s.file && f.Line != s.line && f.Line != s.line+1 {
+		return false
+	}
+	for _, rule := range s.rules {
+		if rule == f.RuleID {
+			return true
+		}
+	}
+	return false
+}
+
+// applySuppressions removes the findings covered by suppression comments
+// in the project and returns findings about the comments themselves.
+func applySuppressions(ctx *ValidationContext, results []*RuleResult) []Finding {
+	if ctx.ProjectRoot == "" {
+		return nil
+	}
+	suppressions := scanSuppressions(ctx)
+
+	var problems []Finding
+	var valid []*suppression
+	for _, s := range suppressions {
+		if s.reason == "" || len(s.rules) == 0 {
+			problems = append(problems, Finding{
+				RuleID:      SuppressionsRule,
+				Severity:    LevelError,
+				Path:        s.path,
+				Line:        s.line,
+				Message:     "suppression comment needs a rule and a reason",
+				Remediation: fmt.Sprintf("Write it as `%s <rule> -- <reason>`", suppressionMarker),
+			})
+			continue
+		}
+		valid = append(valid, s)
+	}
+
+	ran := make(map[string]bool)
+	for _, res := range results {
+		if res.Status == "ERROR" {
+			continue
+		}
+		ran[res.RuleName] = true
+		res.filterFindings(func(f Finding) bool {
+			suppressed := false
+			for _, s := range valid {
+				if s.covers(f) {
+					s.used = true
+					suppressed = true
+				}
+			}
+			return !suppressed
+		})
+	}
+
+	for _, s := range valid {
+		if s.used {
+			continue
+		}
+		var checked []string
+		for _, rule := range s.rules {
+			if ran[rule] {
+				checked = append(checked, rule)
+			}
+		}
+		if len(checked) == 0 {
+			continue // the rules are disabled; nothing to compare against
+		}
+		problems = append(problems, Finding{
+			RuleID:      SuppressionsRule,
+			Severity:    LevelWarning,
+			Path:        s.path,
+			Line:        s.line,
+			Message:     fmt.Sprintf("stale suppression: no %s finding here (reason: %s)", strings.Join(checked, ", "), s.reason),
+			Remediation: "Remove the suppression comment",
+		})
+	}
+	return problems
+}
+
+// scanSuppressions finds suppression comments in the files of the project,
+// or in the changed files of a scoped run.
+func scanSuppressions(ctx *ValidationContext) []*suppression {
+	var found []*suppression
+	scan := func(rel string) {
+		found = append(found, fileSuppressions(ctx.ProjectRoot, rel)...)
+	}
+
+	if ctx.Scoped() {
+		for _, f := range ctx.ChangedFiles {
+			scan(f)
+		}
+		return found
+	}
+
+	snap := ctx.FS()
+	dirs, _ := snap.Dirs()
+	for _, dir := range dirs {
+		for _, e := range snap.Entries(dir) {
+			info, err := e.Info()
+			if err == nil && info.Mode().IsRegular() && info.Size() <= maxSuppressionFileSize {
+				scan(filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+	return found
+}
+
+func fileSuppressions(root, rel string) []*suppression {
+	data, err := os.ReadFile(filepath.Join(root, rel))
+	if err != nil || !bytes.Contains(data, []byte(suppressionMarker)) {
+		return nil
+	}
+
+	var found []*suppression
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSuppressionFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		idx := strings.Index(text, suppressionMarker)
+		if idx < 0 || !opensComment(text[:idx]) {
+			continue
+		}
+		m := suppressionPattern.FindStringSubmatch(text[idx:])
+		s := &suppression{path: filepath.ToSlash(rel), line: line}
+		if m != nil {
+			s.file = m[1] != ""
+			if m[2] != "" {
+				s.rules = strings.Split(m[2], ",")
+			}
+			s.reason = strings.TrimSpace(m[3])
+		}
+		found = append(found, s)
+	}
+	return found
+}
+
+// suppressionResult reports problems with suppressions and stale baseline
+// entries, or nil when there are none.
+func suppressionResult(problems []Finding, stale []BaselineEntry) *RuleResult {
+	if len(stale) > 0 {
+		problems = append(problems, Finding{
+			RuleID:      SuppressionsRule,
+			Severity:    LevelWarning,
+			Message:     fmt.Sprintf("%d baseline entries no longer match a finding", len(stale)),
+			Remediation: "Run `agentic-agent validate --update-baseline` to remove them",
+		})
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	res := &RuleResult{RuleName: SuppressionsRule, Status: "WARN"}
+	for _, f := range problems {
+		res.AddFinding(f)
+		if f.Severity == LevelError {
+			res.Status = "FAIL"
+		}
+	}
+	return res
+}
+
+// opensComment reports whether the text before a marker ends with the
+// comment opener that starts it, and that opener is not itself inside a
+// comment begun at the start of the line.
+func opensComment(prefix string) bool {
+	trimmed := strings.TrimRight(prefix, " \t")
+	for _, opener := range commentOpeners {
+		if !strings.HasSuffix(trimmed, opener) {
+			continue
+		}
+		start := len(trimmed) - len(opener)
+		lead := strings.TrimLeft(trimmed, " \t")
+		for _, o := range commentOpeners {
+			if strings.HasPrefix(lead, o) && len(trimmed)-len(lead) != start {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
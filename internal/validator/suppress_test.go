@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func legacyValidator() *Validator {
+	v := NewValidator()
+	v.Register(&findingRule{name: "context-required", status: "FAIL", findings: []Finding{
+		{Path: "src/a", Message: "Missing context.md"},
+		{Path: "src/b", Message: "Missing context.md"},
+	}})
+	v.Register(&findingRule{name: "clean", status: "PASS"})
+	return v
+}
+
+func TestBaseline_HidesAcceptedFindings(t *testing.T) {
+	root := t.TempDir()
+	ctx := &ValidationContext{ProjectRoot: root}
+	path := filepath.Join(root, DefaultBaselinePath)
+
+	results, err := legacyValidator().Validate(ctx)
+	require.NoError(t, err)
+	require.NoError(t, NewBaseline(results).Save(path))
+
+	baseline, err := LoadBaseline(path)
+	require.NoError(t, err)
+	require.Len(t, baseline.Findings, 2)
+	assert.Equal(t, "src/a", baseline.Findings[0].Path)
+
+	v := legacyValidator()
+	v.UseBaseline(baseline)
+	results, err = v.Validate(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 2, "no suppressions result when nothing is stale")
+	assert.Equal(t, "PASS", results[0].Status)
+	assert.Empty(t, results[0].Errors)
+	assert.Equal(t, 2, results[0].Suppressed)
+}
+
+func TestBaseline_NewAndStaleFindings(t *testing.T) {
+	root := t.TempDir()
+	entry := func(rule, path, message string) BaselineEntry {
+		f := Finding{RuleID: rule, Path: path, Message: message}
+		return BaselineEntry{Fingerprint: Fingerprint(f), RuleID: rule, Path: path, Message: message}
+	}
+	baseline := &Baseline{Version: baselineVersion, Findings: []BaselineEntry{
+		entry("context-required", "src/a", "Missing context.md"),
+		entry("context-required", "src/gone", "Missing context.md"),
+		entry("disabled", "x", "y"),
+	}}
+
+	v := legacyValidator()
+	v.UseBaseline(baseline)
+	results, err := v.Validate(&ValidationContext{ProjectRoot: root})
+	require.NoError(t, err)
+
+	assert.Equal(t, "FAIL", results[0].Status)
+	assert.Equal(t, []string{"src/b: Missing context.md"}, results[0].Errors)
+	assert.Equal(t, 1, results[0].Suppressed)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, SuppressionsRule, results[2].RuleName)
+	assert.Equal(t, "WARN", results[2].Status)
+	assert.Equal(t, []string{"1 baseline entries no longer match a finding"}, results[2].Errors,
+		"entries of rules that did not run are not stale")
+}
+
+func TestLoadBaseline(t *testing.T) {
+	dir := t.TempDir()
+	b, err := LoadBaseline(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Empty(t, b.Findings)
+
+	bad := filepath.Join(dir, "bad.json")
+	require.NoError(t, os.WriteFile(bad, []byte(`{"version": 9}`), 0644))
+	_, err = LoadBaseline(bad)
+	assert.ErrorContains(t, err, "version 9")
+}
+
+func TestSuppressionComments(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	marker := suppressionMarker
+	write("api/handler.go", "package api\n\n// "+marker+" imports -- wrapped by the repository layer\nimport \"database/sql\"\n"+
+		"var x = 1 // "+marker+" imports -- nothing here any more\n")
+	write("api/gen.go", "// "+marker+"-file imports,size -- generated code\npackage api\n")
+	write("web/page.md", "<!-- "+marker+" size -->\n")
+	write("docs/notes.go", "//\t// "+marker+" imports -- an example in a doc comment\n")
+
+	v := NewValidator()
+	v.Register(&findingRule{name: "imports", status: "FAIL", findings: []Finding{
+		{Path: "api/handler.go", Line: 4, Message: `imports "database/sql"`},
+		{Path: "api/handler.go", Line: 9, Message: `imports "os/exec"`},
+		{Path: "api/gen.go", Line: 12, Message: `imports "unsafe"`},
+	}})
+	v.Register(&findingRule{name: "size", status: "WARN", findings: []Finding{
+		{Path: "api/gen.go", Severity: LevelWarning, Message: "too long"},
+	}})
+
+	results, err := v.Validate(&ValidationContext{ProjectRoot: root})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "FAIL", results[0].Status)
+	assert.Equal(t, []string{`api/handler.go:9: imports "os/exec"`}, results[0].Errors)
+	assert.Equal(t, 2, results[0].Suppressed)
+	assert.Equal(t, "PASS", results[1].Status, "file-level suppression covers every listed rule")
+
+	problems := results[2]
+	assert.Equal(t, "FAIL", problems.Status, "a suppression without a reason is an error")
+	assert.ElementsMatch(t, []string{
+		"api/handler.go:5: stale suppression: no imports finding here (reason: nothing here any more)",
+		"web/page.md:1: suppression comment needs a rule and a reason",
+	}, problems.Errors)
+
+	assert.Len(t, NewBaseline(results).Findings, 1, "suppression problems are not baselined")
+}
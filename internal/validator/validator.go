@@ -1,16 +1,105 @@
 package validator
 
-import "github.com/javierbenavides/agentic-agent/pkg/models"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+)
+
+// DefaultRuleTimeout bounds each rule's run unless SetRuleTimeout or
+// workflow.validation.rule_timeout changes it.
+const DefaultRuleTimeout = 2 * time.Minute
 
 type ValidationContext struct {
 	ProjectRoot string
 	Config      *models.Config
+
+	// ChangedFiles, when non-nil, limits the run to these files, relative
+	// to ProjectRoot (validate --since and --staged). Rules then check only
+	// the files and directories the change touches; nil checks everything.
+	ChangedFiles []string
+
+	// Context is cancelled when the rule's time is up; long-running rules
+	// should check Err and stop. Nil means no deadline.
+	Context context.Context
+
+	snapshot *Snapshot
+}
+
+// FS returns the filesystem and git snapshot shared by the rules of a run,
+// creating it on first use. A context describes one run: the snapshot is
+// not refreshed, so use a new context to see later changes.
+func (c *ValidationContext) FS() *Snapshot {
+	if c.snapshot == nil {
+		c.snapshot = NewSnapshot(c.ProjectRoot)
+	}
+	return c.snapshot
+}
+
+// Err returns the error of Context once it is done, else nil.
+func (c *ValidationContext) Err() error {
+	if c.Context == nil {
+		return nil
+	}
+	return c.Context.Err()
+}
+
+// Scoped reports whether the run is limited to ChangedFiles.
+func (c *ValidationContext) Scoped() bool {
+	return c.ChangedFiles != nil
+}
+
+// Touches reports whether a file or directory is in scope: always for an
+// unscoped run, otherwise when it is a changed file or contains one. path
+// may be absolute or relative to ProjectRoot.
+func (c *ValidationContext) Touches(path string) bool {
+	if !c.Scoped() {
+		return true
+	}
+	path = c.relative(path)
+	for _, f := range c.ChangedFiles {
+		if path == "." || f == path || strings.HasPrefix(f, path+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangedDirs returns the directories holding ChangedFiles, relative to
+// ProjectRoot.
+func (c *ValidationContext) ChangedDirs() map[string]bool {
+	dirs := make(map[string]bool)
+	for _, f := range c.ChangedFiles {
+		dirs[filepath.Dir(f)] = true
+	}
+	return dirs
+}
+
+func (c *ValidationContext) relative(path string) string {
+	if filepath.IsAbs(path) && c.ProjectRoot != "" {
+		if rel, err := filepath.Rel(c.ProjectRoot, path); err == nil {
+			return rel
+		}
+	}
+	return filepath.Clean(path)
 }
 
 type RuleResult struct {
-	RuleName string   `json:"rule_name"`
-	Status   string   `json:"status"` // "PASS", "FAIL", "WARN"
-	Errors   []string `json:"errors,omitempty"`
+	RuleName string    `json:"rule_name"`
+	Status   string    `json:"status"` // "PASS", "FAIL", "WARN", or "ERROR" when the rule could not run
+	Errors   []string  `json:"errors,omitempty"`
+	Findings []Finding `json:"findings,omitempty"`
+
+	// Suppressed counts findings hidden by the baseline or by suppression
+	// comments.
+	Suppressed int `json:"suppressed,omitempty"`
 }
 
 type ValidationRule interface {
@@ -18,26 +107,165 @@ type ValidationRule interface {
 	Validate(ctx *ValidationContext) (*RuleResult, error)
 }
 
+// ConfigurableRule is implemented by rules that accept options from the
+// workflow.validation.rules section of the config.
+type ConfigurableRule interface {
+	ValidationRule
+	Configure(options map[string]interface{}) error
+}
+
 type Validator struct {
-	rules []ValidationRule
+	rules       []ValidationRule
+	severities  map[string]Severity
+	baseline    *Baseline
+	timeout     time.Duration
+	concurrency int
 }
 
 func NewValidator() *Validator {
-	return &Validator{}
+	return &Validator{severities: make(map[string]Severity)}
 }
 
 func (v *Validator) Register(rule ValidationRule) {
 	v.rules = append(v.rules, rule)
 }
 
+// RegisterWithSeverity registers a rule whose failures are reported at the
+// given severity; SeverityWarn turns a FAIL into a WARN.
+func (v *Validator) RegisterWithSeverity(rule ValidationRule, severity Severity) {
+	v.Register(rule)
+	v.severities[rule.Name()] = severity
+}
+
+// UseBaseline hides the findings accepted in b from later runs.
+func (v *Validator) UseBaseline(b *Baseline) {
+	v.baseline = b
+}
+
+// SetRuleTimeout bounds how long each rule may run; zero restores
+// DefaultRuleTimeout.
+func (v *Validator) SetRuleTimeout(d time.Duration) {
+	v.timeout = d
+}
+
+// SetConcurrency limits how many rules run at once; zero or less means one
+// per CPU.
+func (v *Validator) SetConcurrency(n int) {
+	v.concurrency = n
+}
+
+// Rules returns the registered rules in run order.
+func (v *Validator) Rules() []ValidationRule {
+	return v.rules
+}
+
+// Validate runs the rules concurrently over one shared Snapshot and
+// returns their results in registration order. A rule that returns an
+// error, panics or runs past its timeout gets an ERROR result; the others
+// still report.
 func (v *Validator) Validate(ctx *ValidationContext) ([]*RuleResult, error) {
-	var results []*RuleResult
-	for _, rule := range v.rules {
-		res, err := rule.Validate(ctx)
-		if err != nil {
-			return nil, err
+	ctx.FS()
+
+	workers := v.concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	results := make([]*RuleResult, len(v.rules))
+	slots := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, rule := range v.rules {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, rule ValidationRule) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = v.runRule(ctx, rule)
+		}(i, rule)
+	}
+	wg.Wait()
+
+	for i, res := range results {
+		v.settle(ctx, v.rules[i], res)
+	}
+
+	// Suppression comments apply first, so a baseline written afterwards
+	// does not repeat them.
+	problems := applySuppressions(ctx, results)
+	var stale []BaselineEntry
+	if v.baseline != nil {
+		stale = v.baseline.apply(results)
+		if ctx.Scoped() {
+			stale = nil // most findings were not looked for
 		}
+	}
+	if res := suppressionResult(problems, stale); res != nil {
 		results = append(results, res)
 	}
 	return results, nil
 }
+
+// settle applies the configured severity to a rule's result and fills in
+// its findings.
+func (v *Validator) settle(ctx *ValidationContext, rule ValidationRule, res *RuleResult) {
+	if res.Status == "FAIL" && v.severities[rule.Name()] == SeverityWarn {
+		res.Status = "WARN"
+	}
+	normalizeFindings(res, ctx.ProjectRoot)
+}
+
+type ruleOutcome struct {
+	res *RuleResult
+	err error
+}
+
+// runRule runs one rule under its timeout. A rule that overruns is left to
+// finish in the background with its Context cancelled.
+func (v *Validator) runRule(ctx *ValidationContext, rule ValidationRule) *RuleResult {
+	timeout := v.timeout
+	if timeout <= 0 {
+		timeout = DefaultRuleTimeout
+	}
+	parent := ctx.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	runCtx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	ruleCtx := *ctx
+	ruleCtx.Context = runCtx
+	done := make(chan ruleOutcome, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- ruleOutcome{err: fmt.Errorf("panic: %v", p)}
+			}
+		}()
+		res, err := rule.Validate(&ruleCtx)
+		done <- ruleOutcome{res: res, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		if out.err == nil && out.res == nil {
+			out.err = errors.New("returned no result")
+		}
+		if out.err != nil {
+			return errorResult(rule.Name(), out.err)
+		}
+		return out.res
+	case <-runCtx.Done():
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return errorResult(rule.Name(), fmt.Errorf("timed out after %s", timeout))
+		}
+		return errorResult(rule.Name(), runCtx.Err())
+	}
+}
+
+func errorResult(name string, err error) *RuleResult {
+	return &RuleResult{
+		RuleName: name,
+		Status:   "ERROR",
+		Errors:   []string{fmt.Sprintf("rule could not run: %v", err)},
+	}
+}
@@ -0,0 +1,122 @@
+package validator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type funcRule struct {
+	name string
+	fn   func(ctx *ValidationContext) (*RuleResult, error)
+}
+
+func (r *funcRule) Name() string { return r.name }
+
+func (r *funcRule) Validate(ctx *ValidationContext) (*RuleResult, error) {
+	return r.fn(ctx)
+}
+
+func passRule(name string) *funcRule {
+	return &funcRule{name: name, fn: func(ctx *ValidationContext) (*RuleResult, error) {
+		return &RuleResult{RuleName: name, Status: "PASS"}, nil
+	}}
+}
+
+// meetRule passes only if the other rule starts while it is running.
+func meetRule(name string, mine, theirs chan struct{}) *funcRule {
+	return &funcRule{name: name, fn: func(*ValidationContext) (*RuleResult, error) {
+		close(mine)
+		select {
+		case <-theirs:
+			return &RuleResult{RuleName: name, Status: "PASS"}, nil
+		case <-time.After(time.Second):
+			return &RuleResult{RuleName: name, Status: "FAIL", Errors: []string{"ran alone"}}, nil
+		}
+	}}
+}
+
+func TestValidate_ConcurrentInOrder(t *testing.T) {
+	a, b := make(chan struct{}), make(chan struct{})
+	v := NewValidator()
+	v.SetConcurrency(2)
+	v.Register(meetRule("first", a, b))
+	v.Register(meetRule("second", b, a))
+	v.Register(passRule("third"))
+
+	results, err := v.Validate(&ValidationContext{})
+	require.NoError(t, err)
+	var names, statuses []string
+	for _, res := range results {
+		names = append(names, res.RuleName)
+		statuses = append(statuses, res.Status)
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, names, "results keep registration order")
+	assert.Equal(t, []string{"PASS", "PASS", "PASS"}, statuses, "rules run concurrently")
+}
+
+func TestValidate_RuleErrorsArePartial(t *testing.T) {
+	v := NewValidator()
+	v.SetRuleTimeout(20 * time.Millisecond)
+	v.Register(&funcRule{name: "broken", fn: func(*ValidationContext) (*RuleResult, error) {
+		return nil, errors.New("cannot read tasks")
+	}})
+	v.Register(&funcRule{name: "panics", fn: func(*ValidationContext) (*RuleResult, error) {
+		var m map[string]int
+		m["x"]++
+		return nil, nil
+	}})
+	v.Register(&funcRule{name: "hangs", fn: func(ctx *ValidationContext) (*RuleResult, error) {
+		<-ctx.Context.Done()
+		return nil, ctx.Err()
+	}})
+	v.Register(passRule("fine"))
+
+	results, err := v.Validate(&ValidationContext{})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, "ERROR", results[0].Status)
+	assert.Equal(t, []string{"rule could not run: cannot read tasks"}, results[0].Errors)
+	assert.Equal(t, LevelError, results[0].Findings[0].Severity)
+	assert.Equal(t, "ERROR", results[1].Status)
+	assert.Contains(t, results[1].Errors[0], "panic: assignment to entry in nil map")
+	assert.Equal(t, "ERROR", results[2].Status)
+	assert.Equal(t, []string{"rule could not run: timed out after 20ms"}, results[2].Errors)
+	assert.Equal(t, "PASS", results[3].Status)
+
+	assert.Equal(t, 1, ExitCode(results, FailOnFail))
+	assert.Equal(t, 0, ExitCode(results, FailOnNever))
+}
+
+func TestSnapshot(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "api/handler.go", "api/v2/routes.go", ".git/config", "node_modules/x/index.js", "vendor/y/y.go"} {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+	}
+
+	snap := NewSnapshot(root)
+	dirs, err := snap.Dirs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", "api", filepath.Join("api", "v2")}, dirs)
+	assert.True(t, snap.HasDir("api/v2"))
+	assert.False(t, snap.HasDir("vendor"))
+
+	require.Len(t, snap.Entries("api"), 1)
+	assert.Equal(t, "handler.go", snap.Entries("api")[0].Name())
+	assert.Equal(t, []string{filepath.Join("api", "handler.go"), filepath.Join("api", "v2", "routes.go")}, snap.FilesUnder("api"))
+
+	data, err := snap.ReadFile("main.go")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("changed"), 0644))
+	again, err := snap.ReadFile("main.go")
+	require.NoError(t, err)
+	assert.Equal(t, data, again, "files are read once per snapshot")
+}
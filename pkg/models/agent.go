@@ -8,6 +8,17 @@ type AgentExecutionResult struct {
 	FilesModified    []string
 	ErrorMessage     string
 	TokensUsed       int
+	Stderr           string // captured stderr of CLI agents
+	ExitCode         int    // exit code of CLI agents
+	Criteria         []CriterionResult // per-criterion verification evidence
+}
+
+// CriterionResult is the verification outcome of one acceptance criterion.
+type CriterionResult struct {
+	Criterion string `json:"criterion" yaml:"criterion"`
+	Passed    bool   `json:"passed" yaml:"passed"`
+	Method    string `json:"method" yaml:"method"` // cmd, file, regex, model or promise
+	Evidence  string `json:"evidence,omitempty" yaml:"evidence,omitempty"`
 }
 
 func (r *AgentExecutionResult) AllCriteriaMet() bool {
@@ -1,13 +1,20 @@
 package models
 
 type Config struct {
-	Project     ProjectConfig     `yaml:"project"`
-	Agents      AgentsConfig      `yaml:"agents"`
-	Workflow    WorkflowConfig    `yaml:"workflow"`
-	Paths       PathsConfig       `yaml:"paths"`
-	Checkpoint  CheckpointConfig  `yaml:"checkpoint,omitempty"`
-	SDD         SDDConfig         `yaml:"sdd,omitempty"`
-	ActiveAgent string            `yaml:"-"` // Runtime-only: detected agent name
+	Project     ProjectConfig         `yaml:"project"`
+	Agents      AgentsConfig          `yaml:"agents"`
+	Workflow    WorkflowConfig        `yaml:"workflow"`
+	Paths       PathsConfig           `yaml:"paths"`
+	Checkpoint  CheckpointConfig      `yaml:"checkpoint,omitempty"`
+	SDD         SDDConfig             `yaml:"sdd,omitempty"`
+	Queries     map[string]SavedQuery `yaml:"queries,omitempty"` // named task queries for task list --query
+	ActiveAgent string                `yaml:"-"`                 // Runtime-only: detected agent name
+}
+
+// SavedQuery is a named task list query, see tasks.ParseQuery.
+type SavedQuery struct {
+	Where string `yaml:"where"`
+	Sort  string `yaml:"sort,omitempty"` // sort keys, e.g. "status,-claimed"
 }
 
 type ProjectConfig struct {
@@ -33,10 +40,49 @@ type AgentConfig struct {
 	SkillPacks []string `yaml:"skill_packs,omitempty"` // packs to auto-install
 	ExtraRules []string `yaml:"extra_rules,omitempty"` // additional rule lines
 	AutoSetup  bool     `yaml:"auto_setup,omitempty"`  // auto-generate on init/ensure
+
+	// Executor selection for agents driven over an API
+	Type           string `yaml:"type,omitempty"`            // executor type, e.g. "openai" for any OpenAI-compatible server
+	BaseURL        string `yaml:"base_url,omitempty"`        // API base URL, e.g. http://localhost:11434/v1
+	APIKeyEnv      string `yaml:"api_key_env,omitempty"`     // env var holding the API key
+	TimeoutSeconds int    `yaml:"timeout_seconds,omitempty"` // per-request timeout
+	MaxRetries     int    `yaml:"max_retries,omitempty"`     // retries on transient errors
+
+	Command *CommandConfig `yaml:"command,omitempty"` // for type "command": how to run the agent CLI
+}
+
+// CommandConfig describes how to run a headless agent CLI.
+// Args are Go templates with .Prompt, .PromptFile, .TaskID, .Model and .WorkDir.
+type CommandConfig struct {
+	Binary     string            `yaml:"binary"`
+	Args       []string          `yaml:"args,omitempty"`
+	PromptMode string            `yaml:"prompt_mode,omitempty"` // "stdin" (default), "arg" or "file"
+	WorkDir    string            `yaml:"work_dir,omitempty"`    // default: the task worktree, else the current dir
+	Env        map[string]string `yaml:"env,omitempty"`         // extra environment; values expand $VARS
 }
 
 type WorkflowConfig struct {
-	Validators []string `yaml:"validators"`
+	Validators []string         `yaml:"validators"` // rules to run by name; empty or "all" runs every rule
+	Validation ValidationConfig `yaml:"validation,omitempty"`
+	// ClaimLease is how long a task claim lasts without a heartbeat, as a
+	// duration such as "45m" (default 30m).
+	ClaimLease string `yaml:"claim_lease,omitempty"`
+}
+
+// ValidationConfig tunes the rules enabled by WorkflowConfig.Validators.
+type ValidationConfig struct {
+	FailOn   string                `yaml:"fail_on,omitempty"`  // exit threshold: "fail" (default), "warn" or "never"
+	Rules    map[string]RuleConfig `yaml:"rules,omitempty"`    // keyed by rule name
+	Baseline string                `yaml:"baseline,omitempty"` // accepted findings file (default .agentic/validation-baseline.json)
+	// RuleTimeout bounds each rule's run, as a duration such as "30s"
+	// (default 2m). A rule that overruns reports ERROR.
+	RuleTimeout string `yaml:"rule_timeout,omitempty"`
+}
+
+// RuleConfig overrides one validation rule.
+type RuleConfig struct {
+	Severity string                 `yaml:"severity,omitempty"` // "fail" (default), "warn" or "off"
+	Options  map[string]interface{} `yaml:"options,omitempty"`  // rule-specific, e.g. max_files for task-size
 }
 
 type CheckpointConfig struct {
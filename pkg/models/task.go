@@ -32,12 +32,15 @@ type Task struct {
 	TrackID     string     `yaml:"track_id,omitempty"`     // Associated track ID
 	ChangeID    string     `yaml:"change_id,omitempty"`    // Associated openspec change
 	ClaimedAt   time.Time  `yaml:"claimed_at,omitempty"`   // When the task was claimed
+	LeaseExpiresAt time.Time `yaml:"lease_expires_at,omitempty"` // Claim lapses unless a heartbeat renews it
+	HeartbeatAt time.Time  `yaml:"heartbeat_at,omitempty"` // Last heartbeat from the claimant
 	CompletedAt time.Time  `yaml:"completed_at,omitempty"` // When the task was completed
 	Branch      string     `yaml:"branch,omitempty"`       // Git branch when claimed
 	Commits     []string   `yaml:"commits,omitempty"`      // Associated git commit hashes
 	WorktreePath string    `yaml:"worktree_path,omitempty"` // Path to isolated git worktree
 	Learnings   string     `yaml:"learnings,omitempty"`    // Lessons learned during task
 	Type        string     `yaml:"type,omitempty"`         // Task type (build, review, research, etc.)
+	DependsOn   []string   `yaml:"depends_on,omitempty"`   // IDs of tasks that must be done first
 	GithubPR    GithubPR   `yaml:"github_pr,omitempty"`    // Associated GitHub PR
 }
 
@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/javierbenavides/agentic-agent/internal/config"
+	"github.com/javierbenavides/agentic-agent/internal/encoding"
+	"github.com/javierbenavides/agentic-agent/internal/project"
+	"github.com/javierbenavides/agentic-agent/internal/tasks"
+	"github.com/javierbenavides/agentic-agent/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContextBundle_RespectsAgentBudget builds a bundle for a scoped task in
+// a repo with more directory context than the agent's max_tokens allows.
+func TestContextBundle_RespectsAgentBudget(t *testing.T) {
+	_ = setupIntegrationTest(t)
+
+	require.NoError(t, project.InitProject("BudgetTest"))
+
+	// Three source directories, each with a large context.md
+	for _, dir := range []string{"internal/auth", "internal/db", "internal/ui"} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package x\n"), 0644))
+		content := "# Context for " + dir + "\n\n" + strings.Repeat("Details about "+dir+".\n", 200)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "context.md"), []byte(content), 0644))
+	}
+
+	tm := tasks.NewTaskManager(".agentic/tasks")
+	dep, err := tm.CreateTask("Add db layer")
+	require.NoError(t, err)
+	task, err := tm.CreateTask("Add login")
+	require.NoError(t, err)
+
+	backlog, err := tm.LoadTasks("backlog")
+	require.NoError(t, err)
+	for i := range backlog.Tasks {
+		switch backlog.Tasks[i].ID {
+		case dep.ID:
+			backlog.Tasks[i].Scope = []string{"internal/db"}
+		case task.ID:
+			backlog.Tasks[i].Scope = []string{"internal/auth"}
+			backlog.Tasks[i].DependsOn = []string{dep.ID}
+		}
+	}
+	require.NoError(t, tm.SaveTasks("backlog", backlog))
+
+	cfg := &models.Config{ActiveAgent: "claude-code"}
+	config.SetDefaults(cfg)
+	cfg.Agents.Overrides = []models.AgentConfig{{Name: "claude-code", MaxTokens: 1500}}
+
+	bundle, err := encoding.BuildContextBundle(task.ID, "", cfg)
+	require.NoError(t, err)
+
+	manifest := bundle.Manifest
+	require.NotNil(t, manifest)
+	assert.Equal(t, 1500, manifest.Budget)
+
+	var paths []string
+	for _, d := range bundle.Directories {
+		paths = append(paths, d.Path)
+	}
+	require.NotEmpty(t, paths)
+	assert.Equal(t, "internal/auth", paths[0], "scope directory comes first")
+	assert.NotContains(t, paths, "internal/ui")
+
+	status := make(map[string]encoding.ManifestEntry)
+	for _, e := range manifest.Sections {
+		status[e.Name] = e
+	}
+	assert.Equal(t, encoding.RelevanceScope, status["internal/auth"].Relevance)
+	assert.Equal(t, encoding.RelevanceDependency, status["internal/db"].Relevance)
+	assert.NotContains(t, status, "internal/ui", "directories unrelated to the task are not loaded")
+}